@@ -2,15 +2,35 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
+	
+	"github.com/go-git/go-billy/v5/osfs"
+	git "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/format/idxfile"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage/filesystem"
 )
 
 // Couleurs ANSI
@@ -18,12 +38,19 @@ const (
 	ColorReset  = "\033[0m"
 	ColorRed    = "\033[31m"
 	ColorGreen  = "\033[32m"
+	ColorYellow = "\033[33m"
 	ColorCyan   = "\033[36m" // Bleu clair
 	ColorBold   = "\033[1m"
 )
 
+// Désactivées par la configuration (ui.colors) ou NO_COLOR
+var colorsEnabled = true
+
 // Fonctions utilitaires pour les couleurs
 func colorize(color, text string) string {
+	if !colorsEnabled {
+		return text
+	}
 	return color + text + ColorReset
 }
 
@@ -35,53 +62,84 @@ func red(text string) string {
 	return colorize(ColorRed, text)
 }
 
+func yellow(text string) string {
+	return colorize(ColorYellow, text)
+}
+
 func cyan(text string) string {
 	return colorize(ColorCyan, text)
 }
 
 func bold(text string) string {
-	return ColorBold + text + ColorReset
+	return colorize(ColorBold, text)
+}
+
+// Message de commit prédéfini, identifié par un nom court pour la ligne de commande
+type commitPreset struct {
+	Name    string
+	Message string
 }
 
 type GitAssistant struct {
-	workingDir   string
-	quickCommits []string
-	lastActions  []string
+	workingDir    string
+	backend       GitBackend
+	backendForced bool // choisi par --backend: la configuration ne le remplace pas
+	localeForced  bool // choisi par --lang
+	config        *Config
+	quickCommits  []commitPreset
+	origin        string // menu, cli ou tui: inscrit dans le journal
+	journalDepth  int    // actions en cours, la plus externe seule est journalisée
+	journalNote   string // résumé de l'action en cours, donné par addToHistory
+	autoStash     string // changement de branche bloqué: demander, toujours ou jamais
+	
+	// Entrée/sortie du mode interactif (os.Stdin/os.Stdout par défaut)
+	in          io.Reader
+	out         io.Writer
+	scanner     *bufio.Scanner
+	inputClosed bool
 }
 
 func NewGitAssistant() *GitAssistant {
 	wd, err := os.Getwd()
 	if err != nil {
-		log.Fatal("Erreur lors de la récupération du répertoire courant:", err)
+		log.Fatal(tr("Erreur lors de la récupération du répertoire courant:"), err)
 	}
-	return &GitAssistant{
+	ga := &GitAssistant{
 		workingDir: wd,
-		quickCommits: []string{
-			"🚀 Mise à jour rapide",
-			"🐛 Correction de bug",
-			"✨ Nouvelle fonctionnalité",
-			"📝 Documentation",
-			"♻️ Refactoring",
-			"🎨 Améliorations UI",
-			"⚡ Performance",
-			"🔧 Configuration",
-		},
-		lastActions: make([]string, 0),
+		origin:     originMenu,
+		in:         os.Stdin,
+		out:        os.Stdout,
 	}
+	ga.backend, _ = newGitBackend(backendAuto, ga.currentDir)
+	ga.reloadConfig()
+	return ga
 }
 
-func (ga *GitAssistant) runCommand(command string, args ...string) (string, error) {
-	cmd := exec.Command(command, args...)
-	cmd.Dir = ga.workingDir
-	output, err := cmd.CombinedOutput()
-	return string(output), err
+// Remplace l'entrée et la sortie (sessions scriptées, tests)
+func (ga *GitAssistant) setIO(in io.Reader, out io.Writer) {
+	ga.in = in
+	ga.out = out
+	ga.scanner = nil
+	ga.inputClosed = false
 }
 
-func (ga *GitAssistant) addToHistory(action string) {
-	ga.lastActions = append(ga.lastActions, fmt.Sprintf("[%s] %s", time.Now().Format("15:04"), action))
-	if len(ga.lastActions) > 10 {
-		ga.lastActions = ga.lastActions[1:]
+func (ga *GitAssistant) currentDir() string {
+	return ga.workingDir
+}
+
+func (ga *GitAssistant) setBackend(kind string) error {
+	backend, err := newGitBackend(kind, ga.currentDir)
+	if err != nil {
+		return err
 	}
+	ga.backend = backend
+	ga.backendForced = true
+	return nil
+}
+
+// Résumé lisible de l'action en cours, repris dans son entrée du journal
+func (ga *GitAssistant) addToHistory(action string) {
+	ga.journalNote = action
 }
 
 func (ga *GitAssistant) isGitRepo() bool {
@@ -90,114 +148,272 @@ func (ga *GitAssistant) isGitRepo() bool {
 }
 
 func (ga *GitAssistant) getCurrentBranch() string {
-	output, err := ga.runCommand("git", "branch", "--show-current")
+	branch, err := ga.backend.CurrentBranch()
 	if err != nil {
 		return "main"
 	}
-	return strings.TrimSpace(output)
+	return branch
 }
 
 func (ga *GitAssistant) getRepoStats() (int, int, int) {
 	// Commits count
-	commitsOutput, _ := ga.runCommand("git", "rev-list", "--count", "HEAD")
-	commits, _ := strconv.Atoi(strings.TrimSpace(commitsOutput))
+	commits, _ := ga.backend.CommitCount()
 	
 	// Files count
-	filesOutput, _ := ga.runCommand("git", "ls-files")
-	files := len(strings.Split(strings.TrimSpace(filesOutput), "\n"))
-	if filesOutput == "" {
-		files = 0
-	}
+	files, _ := ga.backend.LsFiles()
 	
 	// Branches count
-	branchesOutput, _ := ga.runCommand("git", "branch")
-	branches := len(strings.Split(strings.TrimSpace(branchesOutput), "\n"))
-	if branchesOutput == "" {
-		branches = 0
-	}
+	branches, _ := ga.backend.Branches()
 	
-	return commits, files, branches
+	return commits, len(files), len(branches)
 }
 
 func (ga *GitAssistant) smartStatus() error {
-	fmt.Println("📊 === STATUT INTELLIGENT ===")
-	
-	// Infos générales
-	currentBranch := ga.getCurrentBranch()
-	commits, files, branches := ga.getRepoStats()
-	
-	fmt.Printf("🌿 Branche actuelle: %s\n", currentBranch)
-	fmt.Printf("📁 Projet: %s\n", filepath.Base(ga.workingDir))
-	fmt.Printf("📊 %d commits | %d fichiers | %d branches\n\n", commits, files, branches)
+	fmt.Fprintln(ga.out, tr("📊 === STATUT INTELLIGENT ==="))
 	
 	// Changements en cours
-	status, err := ga.runCommand("git", "status", "--porcelain")
+	status, err := ga.backend.Status()
 	if err != nil {
 		return err
 	}
+	report := ga.buildStatusReport(status)
+	
+	// Infos générales
+	if status.Branch == "" && len(status.Head) >= 7 {
+		fmt.Fprintf(ga.out, tr("🌿 HEAD détachée sur %s\n"), status.Head[:7])
+	} else {
+		fmt.Fprintf(ga.out, tr("🌿 Branche actuelle: %s\n"), report.Branch)
+	}
+	if upstream := report.Upstream; upstream != nil {
+		fmt.Fprintf(ga.out, tr("🔗 Branche suivie: %s (%s)\n"), upstream.Name, formatAheadBehind(*upstream))
+	}
+	fmt.Fprintf(ga.out, tr("📁 Projet: %s\n"), report.Project)
+	fmt.Fprintf(ga.out, tr("📊 %d commits | %d fichiers | %d branches\n\n"), report.Commits, report.Files, report.Branches)
 	
-	if strings.TrimSpace(status) == "" {
-		fmt.Println("✅ Aucun changement - Dépôt propre")
+	if report.Clean {
+		fmt.Fprintln(ga.out, tr("✅ Aucun changement - Dépôt propre"))
 		
 		// Dernier commit
-		lastCommit, err := ga.runCommand("git", "log", "-1", "--pretty=format:%h - %s (%cr)")
-		if err == nil && lastCommit != "" {
-			fmt.Printf("📝 Dernier commit: %s\n", lastCommit)
+		if last := report.LastCommit; last != nil {
+			fmt.Fprintf(ga.out, tr("📝 Dernier commit: %s - %s (%s)\n"), last.ShortHash, last.Subject, relativeTime(last.Date))
 		}
 	} else {
-		ga.analyzeChanges(status)
+		ga.analyzeChanges(status.Entries)
 	}
 	
 	return nil
 }
 
-func (ga *GitAssistant) analyzeChanges(status string) {
-	lines := strings.Split(strings.TrimSpace(status), "\n")
-	
-	var added, modified, deleted, untracked []string
+// Durée écoulée lisible ("il y a 3 heures")
+func relativeTime(t time.Time) string {
+	elapsed := time.Since(t)
+	plural := func(n int, one, many string) string {
+		if n > 1 {
+			return fmt.Sprintf(many, n)
+		}
+		return fmt.Sprintf(one, n)
+	}
+	switch {
+	case elapsed < time.Minute:
+		return plural(int(elapsed.Seconds()), tr("il y a %d seconde"), tr("il y a %d secondes"))
+	case elapsed < time.Hour:
+		return plural(int(elapsed.Minutes()), tr("il y a %d minute"), tr("il y a %d minutes"))
+	case elapsed < 24*time.Hour:
+		return plural(int(elapsed.Hours()), tr("il y a %d heure"), tr("il y a %d heures"))
+	case elapsed < 7*24*time.Hour:
+		return plural(int(elapsed.Hours()/24), tr("il y a %d jour"), tr("il y a %d jours"))
+	case elapsed < 30*24*time.Hour:
+		return plural(int(elapsed.Hours()/(24*7)), tr("il y a %d semaine"), tr("il y a %d semaines"))
+	case elapsed < 365*24*time.Hour:
+		return plural(int(elapsed.Hours()/(24*30)), tr("il y a %d mois"), tr("il y a %d mois"))
+	default:
+		return plural(int(elapsed.Hours()/(24*365)), tr("il y a %d an"), tr("il y a %d ans"))
+	}
+}
+
+// Changements classés par catégorie
+type ChangeSummary struct {
+	Added      []string          `json:"added"`
+	Modified   []string          `json:"modified"`
+	Deleted    []string          `json:"deleted"`
+	Untracked  []string          `json:"untracked"`
+	Renamed    []RenamedFile     `json:"renamed"`
+	Conflicted []ConflictedFile  `json:"conflicted"`
+	Submodules []SubmoduleChange `json:"submodules"`
+	Staged     []string          `json:"staged"`
+	Unstaged   []string          `json:"unstaged"`
+}
+
+type RenamedFile struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Score int    `json:"score"`
+	Copy  bool   `json:"copy"`
+}
+
+type ConflictedFile struct {
+	Path  string `json:"path"`
+	State string `json:"state"`
+}
+
+type SubmoduleChange struct {
+	Path string `json:"path"`
+	SubmoduleState
+}
+
+func categorizeChanges(status []StatusEntry) ChangeSummary {
+	summary := ChangeSummary{
+		Added: []string{}, Modified: []string{}, Deleted: []string{}, Untracked: []string{},
+		Renamed: []RenamedFile{}, Conflicted: []ConflictedFile{}, Submodules: []SubmoduleChange{},
+		Staged: []string{}, Unstaged: []string{},
+	}
 	
-	for _, line := range lines {
-		if len(line) < 3 {
+	for _, entry := range status {
+		filename := entry.Path
+		
+		if entry.Conflict {
+			summary.Conflicted = append(summary.Conflicted, ConflictedFile{filename, string([]byte{entry.Index, entry.Worktree})})
 			continue
 		}
+		if entry.Index == '?' {
+			summary.Untracked = append(summary.Untracked, filename)
+			continue
+		}
+		if entry.Submodule != nil {
+			summary.Submodules = append(summary.Submodules, SubmoduleChange{filename, *entry.Submodule})
+		}
+		if entry.Index != ' ' {
+			summary.Staged = append(summary.Staged, filename)
+		}
+		if entry.Worktree != ' ' {
+			summary.Unstaged = append(summary.Unstaged, filename)
+		}
 		
-		statusCode := line[:2]
-		filename := line[3:]
-		
-		switch statusCode[0] {
-		case 'A':
-			added = append(added, filename)
-		case 'M':
-			modified = append(modified, filename)
-		case 'D':
-			deleted = append(deleted, filename)
-		case '?':
-			untracked = append(untracked, filename)
+		switch {
+		case entry.Index == 'R' || entry.Index == 'C':
+			summary.Renamed = append(summary.Renamed, RenamedFile{entry.OrigPath, filename, entry.Score, entry.Index == 'C'})
+		case entry.Index == 'A':
+			summary.Added = append(summary.Added, filename)
+		case entry.Index == 'D' || entry.Worktree == 'D':
+			summary.Deleted = append(summary.Deleted, filename)
+		default:
+			summary.Modified = append(summary.Modified, filename)
+		}
+	}
+	return summary
+}
+
+// Libellé d'une lettre de statut
+func statusLabel(code byte) string {
+	switch code {
+	case 'A':
+		return tr("ajouté")
+	case 'M':
+		return tr("modifié")
+	case 'D':
+		return tr("supprimé")
+	case 'R':
+		return tr("renommé")
+	case 'C':
+		return tr("copié")
+	case 'T':
+		return tr("type modifié")
+	default:
+		return string(code)
+	}
+}
+
+// Description d'un conflit à partir de ses lettres XY
+func conflictLabel(state string) string {
+	switch state {
+	case "UU":
+		return tr("modifié des deux côtés")
+	case "AA":
+		return tr("ajouté des deux côtés")
+	case "DD":
+		return tr("supprimé des deux côtés")
+	case "AU":
+		return tr("ajouté par nous")
+	case "UA":
+		return tr("ajouté par eux")
+	case "DU":
+		return tr("supprimé par nous")
+	case "UD":
+		return tr("supprimé par eux")
+	default:
+		return state
+	}
+}
+
+func (ga *GitAssistant) analyzeChanges(status []StatusEntry) {
+	summary := categorizeChanges(status)
+	
+	var staged, unstaged []StatusEntry
+	for _, entry := range status {
+		if entry.Conflict || entry.Index == '?' {
+			continue
+		}
+		if entry.Index != ' ' {
+			staged = append(staged, entry)
+		}
+		if entry.Worktree != ' ' {
+			unstaged = append(unstaged, entry)
 		}
 	}
 	
-	fmt.Println("📝 Changements détectés:")
+	fmt.Fprintln(ga.out, tr("📝 Changements détectés:"))
 	
-	if len(added) > 0 {
-		fmt.Printf("  ✅ Ajoutés (%d): %s\n", len(added), strings.Join(added, ", "))
+	if len(staged) > 0 {
+		fmt.Fprintf(ga.out, tr("  📥 Indexés (%d):\n"), len(staged))
+		for _, entry := range staged {
+			path := entry.Path
+			if entry.OrigPath != "" {
+				path = fmt.Sprintf("%s → %s (%d%%)", entry.OrigPath, entry.Path, entry.Score)
+			}
+			fmt.Fprintf(ga.out, "     %s %-12s %s\n", green(string(entry.Index)), statusLabel(entry.Index), path)
+		}
+	}
+	if len(unstaged) > 0 {
+		fmt.Fprintf(ga.out, tr("  ✏️ Non indexés (%d):\n"), len(unstaged))
+		for _, entry := range unstaged {
+			fmt.Fprintf(ga.out, "     %s %-12s %s\n", red(string(entry.Worktree)), statusLabel(entry.Worktree), entry.Path)
+		}
 	}
-	if len(modified) > 0 {
-		fmt.Printf("  ✏️ Modifiés (%d): %s\n", len(modified), strings.Join(modified, ", "))
+	if len(summary.Conflicted) > 0 {
+		fmt.Fprintf(ga.out, tr("  ⚔️ Conflits (%d):\n"), len(summary.Conflicted))
+		for _, conflict := range summary.Conflicted {
+			fmt.Fprintf(ga.out, "     %s %s (%s)\n", red(conflict.State), conflict.Path, conflictLabel(conflict.State))
+		}
 	}
-	if len(deleted) > 0 {
-		fmt.Printf("  🗑️ Supprimés (%d): %s\n", len(deleted), strings.Join(deleted, ", "))
+	if len(summary.Submodules) > 0 {
+		fmt.Fprintf(ga.out, tr("  📦 Sous-modules (%d):\n"), len(summary.Submodules))
+		for _, sub := range summary.Submodules {
+			var details []string
+			if sub.CommitChanged {
+				details = append(details, tr("nouveaux commits"))
+			}
+			if sub.Modified {
+				details = append(details, tr("modifications"))
+			}
+			if sub.Untracked {
+				details = append(details, tr("fichiers non suivis"))
+			}
+			fmt.Fprintf(ga.out, "     %s (%s)\n", sub.Path, strings.Join(details, ", "))
+		}
 	}
-	if len(untracked) > 0 {
-		fmt.Printf("  📂 Non suivis (%d): %s\n", len(untracked), strings.Join(untracked, ", "))
+	if len(summary.Untracked) > 0 {
+		fmt.Fprintf(ga.out, tr("  📂 Non suivis (%d): %s\n"), len(summary.Untracked), strings.Join(summary.Untracked, ", "))
 	}
 	
 	// Suggestions intelligentes
-	fmt.Println("\n💡 Suggestions:")
-	if len(untracked) > 0 || len(modified) > 0 || len(added) > 0 {
-		fmt.Println("  → Utilisez 'Sync rapide' pour ajouter et commiter automatiquement")
+	fmt.Fprintln(ga.out, "\n💡 Suggestions:")
+	if len(summary.Conflicted) > 0 {
+		fmt.Fprintln(ga.out, tr("  → Des conflits sont en cours - résolvez-les avec 'Résoudre les conflits' (16)"))
+	} else if len(summary.Untracked) > 0 || len(staged) > 0 || len(unstaged) > 0 {
+		fmt.Fprintln(ga.out, tr("  → Utilisez 'Sync rapide' (9) pour ajouter, commiter et synchroniser automatiquement"))
 	}
-	if len(deleted) > 0 {
-		fmt.Println("  → Des fichiers ont été supprimés - vérifiez que c'est intentionnel")
+	if len(summary.Deleted) > 0 {
+		fmt.Fprintln(ga.out, tr("  → Des fichiers ont été supprimés - vérifiez que c'est intentionnel"))
 	}
 }
 
@@ -208,37 +424,73 @@ func (ga *GitAssistant) quickCommit() error {
 		return err
 	}
 	
-	if strings.TrimSpace(status) == "" {
-		fmt.Println("ℹ️ Aucun changement à commiter")
+	if len(status) == 0 {
+		fmt.Fprintln(ga.out, tr("ℹ️ Aucun changement à commiter"))
 		return nil
 	}
 	
-	fmt.Printf("🚀 === %s ===\n", bold("COMMIT RAPIDE"))
-	fmt.Println("Messages prédéfinis:")
+	fmt.Fprintf(ga.out, "🚀 === %s ===\n", bold(tr("COMMIT RAPIDE")))
+	
+	// Sélection déjà indexée: proposer de ne commiter qu'elle
+	summary := categorizeChanges(status)
+	if len(summary.Staged) > 0 && (len(summary.Unstaged) > 0 || len(summary.Untracked) > 0) {
+		if ga.confirm(fmt.Sprintf(tr("📥 %d fichier(s) déjà indexé(s). Commiter uniquement la sélection?"), len(summary.Staged)), false) {
+			message, err := ga.chooseCommitMessage(summary.Staged)
+			if err != nil {
+				return err
+			}
+			return ga.commitSelectionWithMessage(message)
+		}
+	}
+	
+	paths := make([]string, 0, len(status))
+	for _, entry := range status {
+		paths = append(paths, entry.Path)
+	}
+	message, err := ga.chooseCommitMessage(paths)
+	if err != nil {
+		return err
+	}
+	return ga.commitAllWithMessage(message)
+}
+
+// Choix d'un message prédéfini, personnalisé ou composé (Conventional Commits);
+// paths sert à suggérer la portée
+func (ga *GitAssistant) chooseCommitMessage(paths []string) (string, error) {
+	fmt.Fprintln(ga.out, tr("Messages prédéfinis:"))
 	
-	for i, msg := range ga.quickCommits {
-		fmt.Printf("%d. %s\n", i+1, green(msg))
+	for i, preset := range ga.quickCommits {
+		fmt.Fprintf(ga.out, "%d. %s\n", i+1, green(tr(preset.Message)))
 	}
-	fmt.Printf("%d. %s\n", len(ga.quickCommits)+1, cyan("💬 Message personnalisé"))
+	customChoice := len(ga.quickCommits) + 1
+	composerChoice := len(ga.quickCommits) + 2
+	fmt.Fprintf(ga.out, "%d. %s\n", customChoice, cyan(tr("💬 Message personnalisé")))
+	fmt.Fprintf(ga.out, "%d. %s\n", composerChoice, cyan(tr("📐 Conventional Commit (type, portée, sujet...)")))
 	
-	fmt.Print(cyan("\nChoisissez (1-9): "))
+	fmt.Fprintf(ga.out, cyan(tr("\nChoisissez (1-%d): ")), composerChoice)
 	choice := ga.getUserInput()
 	
 	var message string
-	if choice == strconv.Itoa(len(ga.quickCommits)+1) {
-		fmt.Print(cyan("💬 Votre message: "))
+	if choice == strconv.Itoa(composerChoice) {
+		return ga.composeConventionalCommit(paths)
+	} else if choice == strconv.Itoa(customChoice) {
+		fmt.Fprint(ga.out, cyan(tr("💬 Votre message: ")))
 		message = ga.getUserInput()
 	} else {
 		idx, err := strconv.Atoi(choice)
 		if err != nil || idx < 1 || idx > len(ga.quickCommits) {
-			fmt.Println(red("❌ Choix invalide, utilisation du message par défaut"))
-			message = ga.quickCommits[0]
+			fmt.Fprintln(ga.out, red(tr("❌ Choix invalide, utilisation du message par défaut")))
+			message = tr(ga.quickCommits[0].Message)
 		} else {
-			message = ga.quickCommits[idx-1]
+			message = tr(ga.quickCommits[idx-1].Message)
 		}
 	}
-	
-	// Auto-add et commit
+	return message, nil
+}
+
+// Ajoute tout et commite sans interaction
+func (ga *GitAssistant) commitAllWithMessage(message string) (err error) {
+	defer ga.journal("commit", message)(&err)
 	if err := ga.addAll(); err != nil {
 		return err
 	}
@@ -247,199 +499,677 @@ func (ga *GitAssistant) quickCommit() error {
 		return err
 	}
 	
-	ga.addToHistory(fmt.Sprintf("Commit rapide: %s", message))
+	ga.addToHistory(fmt.Sprintf(tr("Commit rapide: %s"), message))
 	return nil
 }
 
-func (ga *GitAssistant) intelligentBranching() error {
-	fmt.Printf("🌿 === %s ===\n", bold("GESTION INTELLIGENTE DES BRANCHES"))
-	
-	// Lister toutes les branches avec infos
-	branchesOutput, err := ga.runCommand("git", "branch", "-v")
-	if err != nil {
-		return err
-	}
-	
-	fmt.Printf("%s:\n", cyan("Branches existantes"))
-	lines := strings.Split(strings.TrimSpace(branchesOutput), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			if strings.HasPrefix(line, "*") {
-				// Branche active en vert
-				fmt.Printf("%s\n", green(line))
-			} else {
-				fmt.Printf("%s\n", line)
-			}
+func (ga *GitAssistant) findPreset(name string) (commitPreset, bool) {
+	for _, preset := range ga.quickCommits {
+		if preset.Name == name {
+			return preset, true
 		}
 	}
-	
-	fmt.Printf("\n%s:\n", cyan("Actions disponibles"))
-	fmt.Println("1. 🌱 Créer branche de fonctionnalité")
-	fmt.Println("2. 🐛 Créer branche de correction")
-	fmt.Println("3. 🔄 Changer de branche")
-	fmt.Println("4. 🗑️ Supprimer une branche")
-	fmt.Println("5. 🔀 Fusionner une branche")
-	fmt.Print(cyan("\nChoisissez (1-5): "))
-	
-	choice := ga.getUserInput()
-	
-	switch choice {
-	case "1":
-		return ga.createFeatureBranch()
-	case "2":
-		return ga.createBugfixBranch()
-	case "3":
-		return ga.switchBranch("")
-	case "4":
-		return ga.deleteBranch()
-	case "5":
-		return ga.mergeBranch()
-	default:
-		fmt.Println(red("❌ Choix invalide"))
+	return commitPreset{}, false
+}
+
+// Conventional Commits (https://www.conventionalcommits.org)
+
+type commitType struct {
+	Name        string
+	Description string
+}
+
+var conventionalTypes = []commitType{
+	{"feat", "Nouvelle fonctionnalité"},
+	{"fix", "Correction de bug"},
+	{"docs", "Documentation"},
+	{"style", "Mise en forme, sans changement de comportement"},
+	{"refactor", "Refactoring"},
+	{"perf", "Performance"},
+	{"test", "Tests"},
+	{"build", "Build et dépendances"},
+	{"ci", "Intégration continue"},
+	{"chore", "Maintenance"},
+	{"revert", "Annulation d'un commit"},
+}
+
+// Longueur maximale de la première ligne (type, portée et sujet)
+const maxCommitHeaderLength = 72
+
+var (
+	scopePattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]*$`)
+	footerPattern = regexp.MustCompile(`^(BREAKING CHANGE|[A-Za-z][A-Za-z-]*)(: | #)\S`)
+)
+
+type conventionalCommit struct {
+	Type     string
+	Scope    string
+	Breaking bool
+	Subject  string
+	Body     string
+	Footers  []string
+}
+
+func (cc conventionalCommit) header() string {
+	header := cc.Type
+	if cc.Scope != "" {
+		header += "(" + cc.Scope + ")"
 	}
-	
-	return nil
+	if cc.Breaking {
+		header += "!"
+	}
+	return header + ": " + cc.Subject
 }
 
-func (ga *GitAssistant) createFeatureBranch() error {
-	fmt.Print("✨ Nom de la fonctionnalité: ")
-	feature := ga.getUserInput()
-	if feature == "" {
-		return fmt.Errorf("nom requis")
+func (cc conventionalCommit) String() string {
+	parts := []string{cc.header()}
+	if cc.Body != "" {
+		parts = append(parts, cc.Body)
 	}
-	
-	// Nettoyer le nom
-	branchName := "feature/" + strings.ReplaceAll(strings.ToLower(feature), " ", "-")
-	
-	_, err := ga.runCommand("git", "checkout", "-b", branchName)
-	if err != nil {
+	if len(cc.Footers) > 0 {
+		parts = append(parts, strings.Join(cc.Footers, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func isConventionalType(name string) bool {
+	for _, t := range conventionalTypes {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Vérifie le message avant le commit; retourne le premier problème trouvé
+func (cc conventionalCommit) validate() error {
+	if !isConventionalType(cc.Type) {
+		return fmt.Errorf(tr("type inconnu: '%s'"), cc.Type)
+	}
+	if err := validateScope(cc.Scope); err != nil {
 		return err
 	}
 	
-	fmt.Printf("✅ Branche '%s' créée et activée!\n", branchName)
-	ga.addToHistory(fmt.Sprintf("Branche créée: %s", branchName))
+	subject := cc.Subject
+	switch {
+	case strings.TrimSpace(subject) == "":
+		return errors.New(tr("sujet requis"))
+	case subject != strings.TrimSpace(subject):
+		return errors.New(tr("le sujet ne doit pas commencer ni finir par un espace"))
+	case strings.HasSuffix(subject, "."):
+		return errors.New(tr("le sujet ne doit pas finir par un point"))
+	case strings.Contains(subject, "\n"):
+		return errors.New(tr("le sujet doit tenir sur une ligne"))
+	}
+	if first := []rune(subject)[0]; unicode.IsUpper(first) {
+		return errors.New(tr("le sujet doit commencer par une minuscule"))
+	}
+	if cc.headerTooLong() {
+		return fmt.Errorf(tr("première ligne trop longue: %d caractères (max %d)"), utf8.RuneCountInString(cc.header()), maxCommitHeaderLength)
+	}
+	return cc.validateFooters()
+}
+
+// Portée vide (aucune) ou au format scopePattern
+func validateScope(scope string) error {
+	if scope != "" && !scopePattern.MatchString(scope) {
+		return fmt.Errorf(tr("portée invalide '%s': minuscules, chiffres, '-', '_', '.', '/'"), scope)
+	}
 	return nil
 }
 
-func (ga *GitAssistant) createBugfixBranch() error {
-	fmt.Print("🐛 Description du bug: ")
-	bug := ga.getUserInput()
-	if bug == "" {
-		return fmt.Errorf("description requise")
+func (cc conventionalCommit) headerTooLong() bool {
+	return utf8.RuneCountInString(cc.header()) > maxCommitHeaderLength
+}
+
+// Pieds de page; contrairement au sujet, ils ne sont pas corrigés après coup
+func (cc conventionalCommit) validateFooters() error {
+	for _, footer := range cc.Footers {
+		if !footerPattern.MatchString(footer) {
+			return fmt.Errorf(tr("pied de page invalide '%s' (attendu 'Jeton: valeur' ou 'Jeton #valeur')"), footer)
+		}
 	}
-	
-	branchName := "bugfix/" + strings.ReplaceAll(strings.ToLower(bug), " ", "-")
-	
-	_, err := ga.runCommand("git", "checkout", "-b", branchName)
-	if err != nil {
-		return err
+	if cc.Breaking && !cc.hasBreakingFooter() {
+		return errors.New(tr("changement incompatible: ajoutez un pied de page 'BREAKING CHANGE: ...'"))
 	}
-	
-	fmt.Printf("✅ Branche '%s' créée et activée!\n", branchName)
-	ga.addToHistory(fmt.Sprintf("Branche de correction créée: %s", branchName))
 	return nil
 }
 
-func (ga *GitAssistant) deleteBranch() error {
-	fmt.Print("🗑️ Nom de la branche à supprimer: ")
-	branchName := ga.getUserInput()
-	if branchName == "" {
-		return fmt.Errorf("nom requis")
+func (cc conventionalCommit) hasBreakingFooter() bool {
+	for _, footer := range cc.Footers {
+		if strings.HasPrefix(footer, "BREAKING CHANGE: ") || strings.HasPrefix(footer, "BREAKING-CHANGE: ") {
+			return true
+		}
+	}
+	return false
+}
+
+// Dossiers trop génériques pour servir de portée: on prend le niveau suivant
+var genericScopeDirs = map[string]bool{"src": true, "lib": true, "pkg": true, "internal": true, "cmd": true, "app": true}
+
+// Portée suggérée: le dossier le plus représenté parmi les chemins indexés
+func suggestScope(paths []string) string {
+	counts := make(map[string]int)
+	for _, path := range paths {
+		parts := strings.Split(filepath.ToSlash(path), "/")
+		if len(parts) < 2 {
+			continue
+		}
+		scope := parts[0]
+		if genericScopeDirs[scope] && len(parts) > 2 {
+			scope = parts[1]
+		}
+		counts[strings.ToLower(scope)]++
 	}
 	
-	current := ga.getCurrentBranch()
-	if branchName == current {
-		fmt.Println("❌ Impossible de supprimer la branche courante")
-		return nil
+	best := ""
+	for scope, count := range counts {
+		if count > counts[best] || (count == counts[best] && scope < best) {
+			best = scope
+		}
 	}
+	if !scopePattern.MatchString(best) {
+		return ""
+	}
+	return best
+}
+
+// Questions successives pour construire un message Conventional Commits
+func (ga *GitAssistant) composeConventionalCommit(paths []string) (string, error) {
+	fmt.Fprintf(ga.out, "\n📐 === %s ===\n", bold("CONVENTIONAL COMMIT"))
+	var cc conventionalCommit
 	
-	fmt.Printf("⚠️ Êtes-vous sûr de vouloir supprimer '%s'? (o/N): ", branchName)
-	if strings.ToLower(ga.getUserInput()) != "o" {
-		fmt.Println("❌ Suppression annulée")
-		return nil
+	for i, t := range conventionalTypes {
+		fmt.Fprintf(ga.out, "%2d. %-9s %s\n", i+1, green(t.Name), tr(t.Description))
+	}
+	for cc.Type == "" {
+		fmt.Fprint(ga.out, cyan(tr("🏷️ Type (numéro ou nom): ")))
+		choice := ga.getUserInput()
+		if ga.inputClosed {
+			return "", errors.New(tr("commit annulé"))
+		}
+		if idx, err := strconv.Atoi(choice); err == nil && idx >= 1 && idx <= len(conventionalTypes) {
+			cc.Type = conventionalTypes[idx-1].Name
+		} else if isConventionalType(choice) {
+			cc.Type = choice
+		} else {
+			fmt.Fprintln(ga.out, red(tr("❌ Type inconnu")))
+		}
 	}
 	
-	_, err := ga.runCommand("git", "branch", "-d", branchName)
-	if err != nil {
-		// Essayer force delete
-		fmt.Print("⚠️ Branche non fusionnée. Forcer la suppression? (o/N): ")
-		if strings.ToLower(ga.getUserInput()) == "o" {
-			_, err = ga.runCommand("git", "branch", "-D", branchName)
+	// Portée vérifiée tout de suite: le sujet ne pourrait pas la corriger
+	suggestion := suggestScope(paths)
+	for {
+		if suggestion != "" {
+			fmt.Fprintf(ga.out, tr("🎯 Portée (Entrée pour '%s', - pour aucune): "), suggestion)
+		} else {
+			fmt.Fprint(ga.out, tr("🎯 Portée (optionnelle): "))
+		}
+		switch scope := ga.getUserInput(); scope {
+		case "":
+			cc.Scope = suggestion
+		case "-":
+			cc.Scope = ""
+		default:
+			cc.Scope = scope
+		}
+		err := validateScope(cc.Scope)
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(ga.out, red("❌ %v\n"), err)
+		if ga.inputClosed {
+			return "", errors.New(tr("commit annulé"))
 		}
 	}
 	
-	if err != nil {
-		return err
+	cc.Breaking = ga.confirm(tr("💥 Changement incompatible?"), false)
+	
+	fmt.Fprint(ga.out, tr("📝 Sujet (impératif, sans point final): "))
+	cc.Subject = ga.getUserInput()
+	
+	fmt.Fprintln(ga.out, tr("📄 Corps (optionnel, ligne vide pour terminer):"))
+	cc.Body = strings.Join(ga.readLines(), "\n")
+	
+	fmt.Fprint(ga.out, tr("🔗 Tickets liés (ex: #12 #34): "))
+	if refs := strings.Fields(ga.getUserInput()); len(refs) > 0 {
+		cc.Footers = append(cc.Footers, "Refs: "+strings.Join(refs, ", "))
+	}
+	if cc.Breaking {
+		fmt.Fprint(ga.out, tr("💥 Description du changement incompatible: "))
+		if description := ga.getUserInput(); description != "" {
+			cc.Footers = append(cc.Footers, "BREAKING CHANGE: "+description)
+		}
 	}
+	fmt.Fprintln(ga.out, tr("🦶 Autres pieds de page ('Jeton: valeur', ligne vide pour terminer):"))
+	cc.Footers = append(cc.Footers, ga.readLines()...)
 	
-	fmt.Printf("✅ Branche '%s' supprimée!\n", branchName)
-	ga.addToHistory(fmt.Sprintf("Branche supprimée: %s", branchName))
-	return nil
+	// Valider avant de proposer le commit. Une première ligne trop longue peut venir
+	// de la portée: elle est redemandée avant le sujet, qui reste la porte de sortie.
+	for {
+		err := cc.validate()
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(ga.out, red("❌ %v\n"), err)
+		if ga.inputClosed || cc.validateFooters() != nil {
+			return "", fmt.Errorf(tr("message invalide: %v"), err)
+		}
+		if cc.Scope != "" && cc.headerTooLong() {
+			fmt.Fprintf(ga.out, tr("🎯 Nouvelle portée (Entrée pour garder '%s', - pour aucune): "), cc.Scope)
+			switch scope := ga.getUserInput(); scope {
+			case "":
+			case "-":
+				cc.Scope = ""
+			default:
+				if err := validateScope(scope); err != nil {
+					fmt.Fprintf(ga.out, red("❌ %v\n"), err)
+					continue
+				}
+				cc.Scope = scope
+			}
+			if cc.validate() == nil {
+				continue
+			}
+		}
+		fmt.Fprint(ga.out, tr("📝 Nouveau sujet (vide pour annuler): "))
+		subject := ga.getUserInput()
+		if subject == "" {
+			return "", errors.New(tr("commit annulé"))
+		}
+		cc.Subject = subject
+	}
+	
+	message := cc.String()
+	fmt.Fprintf(ga.out, "\n%s\n%s\n\n", cyan(tr("Aperçu du message:")), message)
+	if !ga.confirm(tr("✅ Utiliser ce message?"), true) {
+		return "", errors.New(tr("commit annulé"))
+	}
+	return message, nil
 }
 
-func (ga *GitAssistant) mergeBranch() error {
-	current := ga.getCurrentBranch()
-	fmt.Printf("🔀 Fusion vers la branche courante (%s)\n", current)
-	fmt.Print("Nom de la branche à fusionner: ")
-	
-	branchName := ga.getUserInput()
-	if branchName == "" {
-		return fmt.Errorf("nom requis")
+// Lignes saisies jusqu'à la première ligne vide
+func (ga *GitAssistant) readLines() []string {
+	var lines []string
+	for {
+		line := ga.getUserInput()
+		if line == "" || ga.inputClosed {
+			return lines
+		}
+		lines = append(lines, line)
 	}
+}
+
+func (ga *GitAssistant) intelligentBranching() error {
+	fmt.Fprintf(ga.out, "🌿 === %s ===\n", bold(tr("GESTION INTELLIGENTE DES BRANCHES")))
 	
-	_, err := ga.runCommand("git", "merge", branchName)
-	if err != nil {
-		fmt.Println("❌ Conflit détecté! Résolvez manuellement puis recommitez.")
+	if err := ga.printBranches(); err != nil {
 		return err
 	}
 	
-	fmt.Printf("✅ Branche '%s' fusionnée dans '%s'!\n", branchName, current)
-	ga.addToHistory(fmt.Sprintf("Fusion: %s → %s", branchName, current))
-	return nil
-}
-
-func (ga *GitAssistant) showHistory() error {
-	if len(ga.lastActions) == 0 {
-		fmt.Println("📜 Aucune action récente")
-		return nil
+	// Un choix par type de branche configuré, puis les actions communes
+	types := ga.config.BranchTypes
+	fmt.Fprintf(ga.out, "\n%s:\n", cyan(tr("Actions disponibles")))
+	for i, bt := range types {
+		fmt.Fprintf(ga.out, "%d. %s\n", i+1, tr(bt.Label))
 	}
+	n := len(types)
+	fmt.Fprintf(ga.out, tr("%d. 🔄 Changer de branche\n"), n+1)
+	fmt.Fprintf(ga.out, tr("%d. 🗑️ Supprimer une branche\n"), n+2)
+	fmt.Fprintf(ga.out, tr("%d. 🔀 Fusionner une branche\n"), n+3)
+	fmt.Fprintf(ga.out, cyan(tr("\nChoisissez (1-%d): ")), n+3)
 	
-	fmt.Println("📜 === HISTORIQUE DES ACTIONS ===")
-	for i := len(ga.lastActions) - 1; i >= 0; i-- {
-		fmt.Printf("%d. %s\n", len(ga.lastActions)-i, ga.lastActions[i])
+	choice, err := strconv.Atoi(ga.getUserInput())
+	switch {
+	case err != nil || choice < 1 || choice > n+3:
+		fmt.Fprintln(ga.out, red(tr("❌ Choix invalide")))
+	case choice <= n:
+		return ga.createBranchOfType(types[choice-1])
+	case choice == n+1:
+		return ga.switchBranch("")
+	case choice == n+2:
+		return ga.deleteBranch()
+	case choice == n+3:
+		return ga.mergeBranch()
 	}
 	
 	return nil
 }
 
-func (ga *GitAssistant) interactiveLog() error {
-	fmt.Printf("📜 === %s ===\n", bold("HISTORIQUE INTERACTIF"))
-	
-	output, err := ga.runCommand("git", "log", "--oneline", "-15", "--graph", "--decorate")
+func (ga *GitAssistant) printBranches() error {
+	// Lister toutes les branches avec infos
+	branches, err := ga.backend.Branches()
 	if err != nil {
 		return err
 	}
 	
-	// Améliorer l'affichage avec des séparations après chaque commit
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	fmt.Println()
-	for i, line := range lines {
-		fmt.Printf("  %s\n", line)
-		// Ajouter une ligne de séparation après chaque commit (sauf le dernier)
-		if i < len(lines)-1 {
-			fmt.Printf("  %s\n", cyan(strings.Repeat("─", 80)))
+	fmt.Fprintf(ga.out, "%s:\n", cyan(tr("Branches existantes")))
+	for _, line := range formatBranchLines(branches) {
+		if strings.HasPrefix(line, "*") {
+			// Branche active en vert
+			fmt.Fprintf(ga.out, "%s\n", green(line))
+		} else {
+			fmt.Fprintf(ga.out, "%s\n", line)
 		}
 	}
-	fmt.Println()
-	
-	fmt.Printf("%s:\n", cyan("Actions disponibles"))
-	fmt.Println("1. 👀 Voir détails d'un commit")
-	fmt.Println("2. ⏪ Reset vers un commit")
-	fmt.Println("3. 🌱 Créer branche depuis commit")
-	fmt.Println("4. 🔍 Rechercher dans l'historique")
-	fmt.Print(cyan("\nChoisissez (1-4): "))
-	
+	return nil
+}
+
+// Lignes au format de git branch -v
+func formatBranchLines(branches []BranchInfo) []string {
+	width := 0
+	for _, branch := range branches {
+		if len(branch.Name) > width {
+			width = len(branch.Name)
+		}
+	}
+	
+	lines := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		marker := " "
+		if branch.Current {
+			marker = "*"
+		}
+		lines = append(lines, fmt.Sprintf("%s %-*s %s %s", marker, width, branch.Name, branch.Hash, branch.Subject))
+	}
+	return lines
+}
+
+func (ga *GitAssistant) createFeatureBranch() error {
+	fmt.Fprint(ga.out, tr("✨ Nom de la fonctionnalité: "))
+	feature := ga.getUserInput()
+	if feature == "" {
+		return errors.New(tr("nom requis"))
+	}
+	
+	return ga.createTypedBranch(ga.branchPrefix("feature"), feature, tr("Branche créée"))
+}
+
+func (ga *GitAssistant) createBugfixBranch() error {
+	fmt.Fprint(ga.out, tr("🐛 Description du bug: "))
+	bug := ga.getUserInput()
+	if bug == "" {
+		return errors.New(tr("description requise"))
+	}
+	
+	return ga.createTypedBranch(ga.branchPrefix("bugfix"), bug, tr("Branche de correction créée"))
+}
+
+// Les types feature et bugfix gardent leurs questions d'origine
+func (ga *GitAssistant) createBranchOfType(bt branchType) error {
+	switch bt.Name {
+	case "feature":
+		return ga.createFeatureBranch()
+	case "bugfix":
+		return ga.createBugfixBranch()
+	}
+	
+	fmt.Fprintf(ga.out, tr("🌿 Nom de la branche %s: "), bt.Name)
+	name := ga.getUserInput()
+	if name == "" {
+		return errors.New(tr("nom requis"))
+	}
+	return ga.createTypedBranch(bt.Prefix, name, tr("Branche créée"))
+}
+
+// Préfixe configuré d'un type de branche, ou "<type>/" par défaut
+func (ga *GitAssistant) branchPrefix(name string) string {
+	if bt, ok := ga.config.branchType(name); ok {
+		return bt.Prefix
+	}
+	return name + "/"
+}
+
+// Crée et active une branche préfixée (feature/, bugfix/) sans interaction
+func (ga *GitAssistant) createTypedBranch(prefix, description, historyLabel string) (err error) {
+	// Nettoyer le nom
+	branchName := prefix + strings.ReplaceAll(strings.ToLower(strings.TrimSpace(description)), " ", "-")
+	defer ga.journal("branch.create", branchName)(&err)
+	
+	err = ga.checkoutWithAutoStash(branchName, func() error { return ga.backend.CreateBranch(branchName, "") })
+	if err != nil && !errors.Is(err, errStashKept) {
+		return err
+	}
+	
+	fmt.Fprintf(ga.out, tr("✅ Branche '%s' créée et activée!\n"), branchName)
+	ga.addToHistory(fmt.Sprintf("%s: %s", historyLabel, branchName))
+	return err
+}
+
+func (ga *GitAssistant) deleteBranch() error {
+	branchName := ga.pick(tr("🗑️ Nom de la branche à supprimer: "), ga.branchItems(false))
+	if branchName == "" {
+		return errors.New(tr("nom requis"))
+	}
+	
+	current := ga.getCurrentBranch()
+	if branchName == current {
+		fmt.Fprintln(ga.out, tr("❌ Impossible de supprimer la branche courante"))
+		return nil
+	}
+	
+	if !ga.confirm(fmt.Sprintf(tr("⚠️ Êtes-vous sûr de vouloir supprimer '%s'?"), branchName), false) {
+		fmt.Fprintln(ga.out, tr("❌ Suppression annulée"))
+		return nil
+	}
+	
+	err := ga.removeBranch(branchName, false)
+	if err != nil {
+		// Essayer force delete
+		if ga.confirm(tr("⚠️ Branche non fusionnée. Forcer la suppression?"), false) {
+			err = ga.removeBranch(branchName, true)
+		}
+	}
+	
+	return err
+}
+
+func (ga *GitAssistant) removeBranch(branchName string, force bool) (err error) {
+	if branchName == ga.getCurrentBranch() {
+		return errors.New(tr("impossible de supprimer la branche courante"))
+	}
+	defer ga.journal("branch.delete", branchName, optionalFlag(force, "--force"))(&err)
+	
+	if err := ga.recordBackup("delete"); err != nil {
+		return err
+	}
+	if err := ga.backend.DeleteBranch(branchName, force); err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(ga.out, tr("✅ Branche '%s' supprimée!\n"), branchName)
+	ga.addToHistory(fmt.Sprintf(tr("Branche supprimée: %s"), branchName))
+	return nil
+}
+
+// Stratégies de fusion; "merge" laisse Git choisir (fast-forward si possible)
+const (
+	mergeDefault = "merge"
+	mergeFFOnly  = "ff-only"
+	mergeNoFF    = "no-ff"
+	mergeSquash  = "squash"
+	mergeRebase  = "rebase"
+)
+
+var mergeStrategies = []struct{ Name, Label string }{
+	{mergeDefault, "Fusion (fast-forward si possible, sinon commit de fusion)"},
+	{mergeFFOnly, "Fast-forward uniquement"},
+	{mergeNoFF, "Toujours un commit de fusion"},
+	{mergeSquash, "Squash: un seul commit avec tous les changements"},
+	{mergeRebase, "Rebase de la branche puis fast-forward"},
+}
+
+func isMergeStrategy(name string) bool {
+	for _, strategy := range mergeStrategies {
+		if strategy.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (ga *GitAssistant) mergeBranch() error {
+	current := ga.getCurrentBranch()
+	fmt.Fprintf(ga.out, tr("🔀 Fusion vers la branche courante (%s)\n"), current)
+	branchName := ga.pick(tr("Nom de la branche à fusionner: "), ga.branchItems(false))
+	if branchName == "" {
+		return errors.New(tr("nom requis"))
+	}
+	
+	preview, err := ga.backend.PreviewMerge(branchName)
+	if err != nil {
+		fmt.Fprintf(ga.out, tr("⚠️ Aperçu indisponible: %v\n"), err)
+	} else {
+		ga.printMergePreview(branchName, current, preview)
+		if preview.UpToDate {
+			return nil
+		}
+	}
+	
+	fmt.Fprintln(ga.out, tr("\n🔀 Stratégie de fusion:"))
+	for i, strategy := range mergeStrategies {
+		fmt.Fprintf(ga.out, "%d. %-8s %s\n", i+1, strategy.Name, tr(strategy.Label))
+	}
+	fmt.Fprintf(ga.out, tr("\nStratégie (1-%d, défaut %s): "), len(mergeStrategies), ga.config.MergeStrategy)
+	strategy := ga.config.MergeStrategy
+	if choice, err := strconv.Atoi(ga.getUserInput()); err == nil && choice >= 1 && choice <= len(mergeStrategies) {
+		strategy = mergeStrategies[choice-1].Name
+	}
+	if len(preview.Conflicts) > 0 && !ga.confirm(tr("⚠️ Fusionner malgré les conflits prévus?"), true) {
+		fmt.Fprintln(ga.out, tr("❌ Fusion annulée"))
+		return nil
+	}
+	
+	return ga.mergeInto(branchName, strategy)
+}
+
+// Commits qui arrivent, fichiers touchés et conflits prévus par la fusion d'essai
+func (ga *GitAssistant) printMergePreview(branchName, current string, preview MergePreview) {
+	fmt.Fprintf(ga.out, "\n🔍 %s\n", bold(fmt.Sprintf(tr("Aperçu: %s → %s"), branchName, current)))
+	if preview.UpToDate {
+		fmt.Fprintf(ga.out, tr("ℹ️ '%s' est déjà à jour avec '%s'\n"), current, branchName)
+		return
+	}
+	
+	const maxShown = 10
+	fmt.Fprintf(ga.out, tr("📥 %d commit(s) à intégrer:\n"), len(preview.Commits))
+	for i, commit := range preview.Commits {
+		if i == maxShown {
+			fmt.Fprintf(ga.out, tr("  ... et %d autre(s)\n"), len(preview.Commits)-maxShown)
+			break
+		}
+		fmt.Fprintf(ga.out, "  %s %s\n", cyan(commit.ShortHash), commit.Subject)
+	}
+	fmt.Fprintf(ga.out, tr("📄 %d fichier(s) touché(s):\n"), len(preview.Files))
+	for i, file := range preview.Files {
+		if i == maxShown {
+			fmt.Fprintf(ga.out, tr("  ... et %d autre(s)\n"), len(preview.Files)-maxShown)
+			break
+		}
+		fmt.Fprintf(ga.out, "  %s %s\n", file.Status, file.Path)
+	}
+	
+	switch {
+	case preview.FastForward:
+		fmt.Fprintln(ga.out, green(tr("✅ Fast-forward possible, aucun conflit")))
+	case len(preview.Conflicts) == 0:
+		fmt.Fprintln(ga.out, green(tr("✅ Fusion sans conflit")))
+	default:
+		fmt.Fprintf(ga.out, red(tr("⚔️ Conflits prévus dans %d fichier(s):\n")), len(preview.Conflicts))
+		for _, path := range preview.Conflicts {
+			fmt.Fprintf(ga.out, "  %s\n", red(path))
+		}
+	}
+}
+
+// Fusionne une branche dans la branche courante sans interaction
+func (ga *GitAssistant) mergeInto(branchName, strategy string) (err error) {
+	if !isMergeStrategy(strategy) {
+		return fmt.Errorf(tr("stratégie de fusion inconnue: %s (merge, ff-only, no-ff, squash ou rebase)"), strategy)
+	}
+	current := ga.getCurrentBranch()
+	// Avant l'instantané et le journal: une fusion que le backend ne sait pas faire n'est pas tentée
+	if err := ga.backend.CanMerge(branchName, strategy); err != nil {
+		if errors.Is(err, errNativeUnsupported) {
+			return fmt.Errorf(tr("%w (backend exec requis: installez git ou utilisez --backend exec)"), err)
+		}
+		return err
+	}
+	defer ga.journal("merge", branchName, "--"+strategy)(&err)
+	if err := ga.recordBackup("merge"); err != nil {
+		return err
+	}
+	
+	switch strategy {
+	case mergeRebase:
+		err = ga.rebaseThenFastForward(branchName, current)
+	case mergeSquash:
+		err = ga.squashMerge(branchName)
+	default:
+		err = ga.backend.Merge(branchName, strategy)
+		if err != nil {
+			// Seules les entrées non fusionnées de l'index sont des conflits
+			err = ga.handleConflicts(err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(ga.out, tr("✅ Branche '%s' fusionnée dans '%s'!\n"), branchName, current)
+	ga.addToHistory(fmt.Sprintf(tr("Fusion: %s → %s"), branchName, current))
+	return nil
+}
+
+// La branche est rejouée sur la branche courante, qui avance ensuite en fast-forward;
+// l'historique reste linéaire
+func (ga *GitAssistant) rebaseThenFastForward(branchName, current string) error {
+	fmt.Fprintf(ga.out, tr("🔁 Rebase de '%s' sur '%s'...\n"), branchName, current)
+	if err := ga.backend.Rebase(current, branchName); err != nil {
+		if err := ga.handleConflicts(err); err != nil {
+			return err
+		}
+	}
+	if err := ga.backend.Checkout(current); err != nil {
+		return err
+	}
+	return ga.backend.Merge(branchName, mergeFFOnly)
+}
+
+// Git prépare l'index sans commiter; le message reprend les sujets des commits intégrés
+func (ga *GitAssistant) squashMerge(branchName string) error {
+	preview, _ := ga.backend.PreviewMerge(branchName)
+	if err := ga.backend.Merge(branchName, mergeSquash); err != nil {
+		if err := ga.handleConflicts(err); err != nil {
+			return err
+		}
+	}
+	message := fmt.Sprintf(tr("🔀 Squash de la branche '%s'"), branchName)
+	if len(preview.Commits) > 0 {
+		message += "\n"
+		for _, commit := range preview.Commits {
+			message += "\n* " + commit.Subject
+		}
+	}
+	return ga.backend.Commit(message)
+}
+
+func (ga *GitAssistant) interactiveLog() error {
+	fmt.Fprintf(ga.out, "📜 === %s ===\n", bold(tr("HISTORIQUE INTERACTIF")))
+	
+	if err := ga.printLog(ga.config.LogDepth); err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(ga.out, "%s:\n", cyan(tr("Actions disponibles")))
+	fmt.Fprintln(ga.out, tr("1. 👀 Voir détails d'un commit"))
+	fmt.Fprintln(ga.out, tr("2. ⏪ Reset vers un commit"))
+	fmt.Fprintln(ga.out, tr("3. 🌱 Créer branche depuis commit"))
+	fmt.Fprintln(ga.out, tr("4. 🔍 Rechercher dans l'historique"))
+	fmt.Fprintln(ga.out, tr("5. 🍒 Cherry-pick de commits d'une autre branche"))
+	fmt.Fprintln(ga.out, tr("6. ↩️ Revert d'un commit"))
+	fmt.Fprint(ga.out, cyan(tr("\nChoisissez (1-6): ")))
+	
 	choice := ga.getUserInput()
 	
 	switch choice {
@@ -451,44 +1181,97 @@ func (ga *GitAssistant) interactiveLog() error {
 		return ga.createBranchFromCommit()
 	case "4":
 		return ga.searchInHistory()
+	case "5":
+		return ignoreStop(ga.cherryPickCommits())
+	case "6":
+		return ignoreStop(ga.revertCommit())
+	}
+	
+	return nil
+}
+
+func (ga *GitAssistant) printLog(depth int) error {
+	commits, err := ga.backend.Log(depth)
+	if err != nil {
+		return err
 	}
 	
+	// Améliorer l'affichage avec des séparations après chaque commit
+	lines := formatLogLines(commits)
+	fmt.Fprintln(ga.out)
+	for i, line := range lines {
+		fmt.Fprintf(ga.out, "  %s\n", line)
+		// Ajouter une ligne de séparation après chaque commit (sauf le dernier)
+		if i < len(lines)-1 {
+			fmt.Fprintf(ga.out, "  %s\n", cyan(strings.Repeat("─", 80)))
+		}
+	}
+	fmt.Fprintln(ga.out)
 	return nil
 }
 
+// Lignes au format de git log --oneline --decorate
+func formatLogLines(commits []CommitInfo) []string {
+	lines := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		marker := "*"
+		if len(commit.Parents) > 1 {
+			marker = "M"
+		}
+		line := fmt.Sprintf("%s %s", marker, commit.ShortHash)
+		if len(commit.Refs) > 0 {
+			line += " (" + decorateRefs(commit.Refs) + ")"
+		}
+		lines = append(lines, line+" "+commit.Subject)
+	}
+	return lines
+}
+
+// Les tags ressortent parmi les branches: "tag: v1.2.0" devient 🏷️ v1.2.0
+func decorateRefs(refs []string) string {
+	parts := make([]string, len(refs))
+	for i, ref := range refs {
+		parts[i] = ref
+		if strings.HasPrefix(ref, "tag: ") {
+			parts[i] = yellow("🏷️ " + strings.TrimPrefix(ref, "tag: "))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
 func (ga *GitAssistant) showCommitDetails() error {
-	fmt.Print(cyan("🔍 Hash du commit: "))
-	hash := ga.getUserInput()
+	hash := ga.pick(cyan(tr("🔍 Hash du commit: ")), ga.commitItems())
 	
 	if hash == "" {
-		return fmt.Errorf("hash requis")
+		return errors.New(tr("hash requis"))
 	}
 	
+	return ga.printCommitDetails(hash)
+}
+
+// En-tête, statistiques et diff coloré d'un commit
+func (ga *GitAssistant) printCommitDetails(hash string) error {
 	// Afficher les informations générales du commit
-	output, err := ga.runCommand("git", "show", "--stat", "--pretty=format:%h - %s%n%an <%ae>%n%ad%n", hash)
+	output, err := ga.backend.ShowCommit(hash)
 	if err != nil {
 		return err
 	}
 	
-	fmt.Printf("📋 %s:\n", cyan("Détails du commit"))
-	fmt.Println(output)
+	fmt.Fprintf(ga.out, "📋 %s:\n", cyan(tr("Détails du commit")))
+	fmt.Fprintln(ga.out, output)
 	
 	// Afficher directement le diff complet
-	diffOutput, err := ga.runCommand("git", "diff", hash+"^", hash)
+	diffOutput, err := ga.backend.CommitDiff(hash)
 	if err != nil {
-		// Si pas de parent (premier commit), utiliser git show
-		diffOutput, err = ga.runCommand("git", "show", "--format=", hash)
-		if err != nil {
-			return fmt.Errorf("impossible d'obtenir le diff: %v", err)
-		}
+		return fmt.Errorf(tr("impossible d'obtenir le diff: %v"), err)
 	}
 	
 	if strings.TrimSpace(diffOutput) == "" {
-		fmt.Println("Aucun changement de fichier dans ce commit")
+		fmt.Fprintln(ga.out, tr("Aucun changement de fichier dans ce commit"))
 		return nil
 	}
 	
-	fmt.Printf("\n🔍 %s:\n", cyan("Diff complet"))
+	fmt.Fprintf(ga.out, "\n🔍 %s:\n", cyan(tr("Diff complet")))
 	ga.displayColoredDiff(diffOutput)
 	
 	return nil
@@ -502,544 +1285,10953 @@ func (ga *GitAssistant) displayColoredDiff(diffText string) {
 		switch {
 		case strings.HasPrefix(line, "diff --git"):
 			// Headers de diff
-			fmt.Println(bold(line))
+			fmt.Fprintln(ga.out, bold(line))
 		case strings.HasPrefix(line, "index "):
 			// Index des fichiers
-			fmt.Println(cyan(line))
+			fmt.Fprintln(ga.out, cyan(line))
 		case strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---"):
 			// Headers de fichiers
-			fmt.Println(cyan(line))
+			fmt.Fprintln(ga.out, cyan(line))
 		case strings.HasPrefix(line, "@@"):
 			// Numéros de lignes
-			fmt.Println(cyan(line))
+			fmt.Fprintln(ga.out, cyan(line))
 		case strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++"):
 			// Lignes ajoutées
-			fmt.Println(green(line))
+			fmt.Fprintln(ga.out, green(line))
 		case strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---"):
 			// Lignes supprimées
-			fmt.Println(red(line))
+			fmt.Fprintln(ga.out, red(line))
 		case line == "":
 			// Lignes vides
-			fmt.Println()
+			fmt.Fprintln(ga.out)
 		default:
 			// Lignes de contexte (inchangées) - commencent par un espace
-			fmt.Println(line)
+			fmt.Fprintln(ga.out, line)
 		}
 	}
 }
 
 func (ga *GitAssistant) searchInHistory() error {
-	fmt.Print("🔍 Rechercher (message/auteur/fichier): ")
+	fmt.Fprint(ga.out, tr("🔍 Rechercher (message/auteur/fichier): "))
 	query := ga.getUserInput()
 	
 	if query == "" {
-		return fmt.Errorf("terme de recherche requis")
+		return errors.New(tr("terme de recherche requis"))
 	}
 	
 	// Recherche dans les messages
-	output, _ := ga.runCommand("git", "log", "--oneline", "--grep="+query, "-i")
-	if output != "" {
-		fmt.Println("📝 Commits avec ce message:")
-		fmt.Println(output)
+	byMessage, err := ga.backend.SearchCommits(query, false)
+	if err != nil {
+		return err
+	}
+	if len(byMessage) > 0 {
+		fmt.Fprintln(ga.out, tr("📝 Commits avec ce message:"))
+		ga.printOneline(byMessage)
 	}
 	
 	// Recherche par fichier
-	output2, _ := ga.runCommand("git", "log", "--oneline", "--", "*"+query+"*")
-	if output2 != "" {
-		fmt.Println("📁 Commits affectant ce fichier:")
-		fmt.Println(output2)
+	byPath, err := ga.backend.SearchCommits(query, true)
+	if err != nil {
+		return err
+	}
+	if len(byPath) > 0 {
+		fmt.Fprintln(ga.out, tr("📁 Commits affectant ce fichier:"))
+		ga.printOneline(byPath)
 	}
 	
-	if output == "" && output2 == "" {
-		fmt.Println("❌ Aucun résultat trouvé")
+	if len(byMessage) == 0 && len(byPath) == 0 {
+		fmt.Fprintln(ga.out, tr("❌ Aucun résultat trouvé"))
 	}
 	
 	return nil
 }
 
-func (ga *GitAssistant) projectInsights() error {
-	fmt.Printf("📊 === %s ===\n", bold("ANALYSE DU PROJET"))
+// Une ligne par commit, comme git log --oneline
+func (ga *GitAssistant) printOneline(commits []CommitInfo) {
+	for _, commit := range commits {
+		fmt.Fprintf(ga.out, "%s %s\n", commit.ShortHash, commit.Subject)
+	}
+}
+
+func (ga *GitAssistant) projectInsights(churnSince time.Time) error {
+	fmt.Fprintf(ga.out, "📊 === %s ===\n", bold(tr("ANALYSE DU PROJET")))
+	report := ga.buildInsightsReport(churnSince)
 	
 	// Statistiques générales
-	commits, files, branches := ga.getRepoStats()
-	fmt.Printf("📈 Statistiques:\n")
-	fmt.Printf("  • %s commits au total\n", green(strconv.Itoa(commits)))
-	fmt.Printf("  • %s fichiers suivis\n", green(strconv.Itoa(files)))
-	fmt.Printf("  • %s branches\n\n", green(strconv.Itoa(branches)))
+	fmt.Fprint(ga.out, tr("📈 Statistiques:\n"))
+	fmt.Fprintf(ga.out, tr("  • %s commits au total\n"), green(strconv.Itoa(report.Commits)))
+	fmt.Fprintf(ga.out, tr("  • %s fichiers suivis\n"), green(strconv.Itoa(report.Files)))
+	fmt.Fprintf(ga.out, tr("  • %s branches\n\n"), green(strconv.Itoa(len(report.BranchList))))
 	
 	// Liste des branches avec détails
-	fmt.Printf("🌿 %s:\n", cyan("Branches disponibles"))
-	branchesOutput, err := ga.runCommand("git", "branch", "-v")
-	if err == nil && branchesOutput != "" {
-		lines := strings.Split(strings.TrimSpace(branchesOutput), "\n")
+	fmt.Fprintf(ga.out, "🌿 %s:\n", cyan(tr("Branches disponibles")))
+	if len(report.BranchList) > 0 {
+		lines := formatBranchLines(report.BranchList)
 		for _, line := range lines {
 			line = strings.TrimSpace(line)
 			if line != "" {
 				if strings.HasPrefix(line, "*") {
-					fmt.Printf("  → %s %s\n", green(line[2:]), cyan("(branche actuelle)"))
+					fmt.Fprintf(ga.out, "  → %s %s\n", green(line[2:]), cyan(tr("(branche actuelle)")))
 				} else {
-					fmt.Printf("  • %s\n", line)
+					fmt.Fprintf(ga.out, "  • %s\n", line)
 				}
 			}
 		}
 	} else {
-		fmt.Println("  Aucune branche trouvée")
+		fmt.Fprintln(ga.out, tr("  Aucune branche trouvée"))
 	}
-	fmt.Println()
+	fmt.Fprintln(ga.out)
 	
-	// Analyse des extensions
-	output, err := ga.runCommand("git", "ls-files")
-	if err == nil && output != "" {
-		ga.analyzeFileTypes(strings.Split(output, "\n"))
+	// Langages
+	if len(report.Languages) > 0 {
+		ga.printLanguages(report.Languages, report.ExcludedFiles)
 	}
 	
-	// Activité récente
-	recentOutput, err := ga.runCommand("git", "log", "--since=1.week.ago", "--oneline")
-	if err == nil {
-		recentCommits := len(strings.Split(strings.TrimSpace(recentOutput), "\n"))
-		if recentOutput == "" {
-			recentCommits = 0
-		}
-		fmt.Printf("⚡ Activité récente: %s commits cette semaine\n", green(strconv.Itoa(recentCommits)))
+	// Contributeurs et bus factor
+	if len(report.Authors) > 0 {
+		ga.printContributors(report.Authors)
 	}
+	if len(report.BusFactor) > 0 {
+		ga.printBusFactors(report.BusFactor)
+	}
+	if report.Churn != nil {
+		ga.printChurn(*report.Churn)
+	}
+	
+	// Activité récente
+	fmt.Fprintf(ga.out, tr("⚡ Activité récente: %s commits cette semaine\n"), green(strconv.Itoa(report.CommitsLastWeek)))
 	
 	// Taille du dépôt
-	sizeOutput, _ := ga.runCommand("git", "count-objects", "-vH")
-	if sizeOutput != "" {
-		lines := strings.Split(sizeOutput, "\n")
-		for _, line := range lines {
-			if strings.Contains(line, "size-pack") {
-				fmt.Printf("💾 Taille: %s\n", green(strings.Split(line, " ")[1]))
-				break
-			}
-		}
-	}
+	fmt.Fprintf(ga.out, tr("💾 Taille: %s\n"), green(humanSize(report.PackSizeBytes)))
 	
 	return nil
 }
 
-func (ga *GitAssistant) analyzeFileTypes(files []string) {
-	extCount := make(map[string]int)
-	
-	for _, file := range files {
-		if file == "" {
-			continue
-		}
-		ext := filepath.Ext(file)
-		if ext == "" {
-			ext = "sans extension"
-		}
-		extCount[ext]++
+// Taille lisible façon git count-objects -H
+func humanSize(size int64) string {
+	units := []string{"bytes", "KiB", "MiB", "GiB"}
+	value := float64(size)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
 	}
-	
-	// Trier par nombre
-	type kv struct {
-		Key   string
-		Value int
+	if unit == 0 {
+		return fmt.Sprintf("%d %s", size, units[0])
 	}
-	
-	var sorted []kv
-	for k, v := range extCount {
-		sorted = append(sorted, kv{k, v})
+	return fmt.Sprintf("%.2f %s", value, units[unit])
+}
+
+// Langages: lignes de code, de commentaires et vides des fichiers suivis à HEAD
+
+// Fichiers texte dont le langage n'est pas reconnu: clé stable du rapport JSON,
+// affichée « Autre »
+const languageOther = "Other"
+
+// Syntaxe des commentaires d'un langage. Le comptage est ligne par ligne: une ligne
+// est un commentaire si elle commence par un marqueur, sinon du code.
+type languageSyntax struct {
+	Line  []string    // commentaires jusqu'à la fin de la ligne
+	Block [][2]string // commentaires de bloc: début, fin
+}
+
+var (
+	cSyntax    = languageSyntax{Line: []string{"//"}, Block: [][2]string{{"/*", "*/"}}}
+	hashSyntax = languageSyntax{Line: []string{"#"}}
+	xmlSyntax  = languageSyntax{Block: [][2]string{{"<!--", "-->"}}}
+)
+
+var languageSyntaxes = map[string]languageSyntax{
+	"Go":         cSyntax,
+	"C":          cSyntax,
+	"C++":        cSyntax,
+	"C#":         cSyntax,
+	"Java":       cSyntax,
+	"Kotlin":     cSyntax,
+	"Scala":      cSyntax,
+	"Swift":      cSyntax,
+	"Rust":       cSyntax,
+	"JavaScript": cSyntax,
+	"TypeScript": cSyntax,
+	"SCSS":       cSyntax,
+	"CSS":        {Block: [][2]string{{"/*", "*/"}}},
+	"PHP":        {Line: []string{"//", "#"}, Block: [][2]string{{"/*", "*/"}}},
+	"Python":     {Line: []string{"#"}, Block: [][2]string{{`"""`, `"""`}, {"'''", "'''"}}},
+	"Ruby":       {Line: []string{"#"}, Block: [][2]string{{"=begin", "=end"}}},
+	"Lua":        {Line: []string{"--"}, Block: [][2]string{{"--[[", "]]"}}},
+	"SQL":        {Line: []string{"--"}, Block: [][2]string{{"/*", "*/"}}},
+	"Haskell":    {Line: []string{"--"}, Block: [][2]string{{"{-", "-}"}}},
+	"Shell":      hashSyntax,
+	"Perl":       hashSyntax,
+	"R":          hashSyntax,
+	"YAML":       hashSyntax,
+	"TOML":       hashSyntax,
+	"Makefile":   hashSyntax,
+	"Dockerfile": hashSyntax,
+	"CMake":      hashSyntax,
+	"HTML":       xmlSyntax,
+	"XML":        xmlSyntax,
+	"Markdown":   xmlSyntax,
+}
+
+var languageExtensions = map[string]string{
+	".go": "Go", ".c": "C", ".h": "C", ".cpp": "C++", ".cc": "C++", ".cxx": "C++", ".hpp": "C++", ".hh": "C++",
+	".cs": "C#", ".java": "Java", ".kt": "Kotlin", ".kts": "Kotlin", ".scala": "Scala", ".swift": "Swift", ".rs": "Rust",
+	".js": "JavaScript", ".mjs": "JavaScript", ".cjs": "JavaScript", ".jsx": "JavaScript", ".ts": "TypeScript", ".tsx": "TypeScript",
+	".css": "CSS", ".scss": "SCSS", ".php": "PHP", ".py": "Python", ".pyw": "Python", ".rb": "Ruby", ".lua": "Lua",
+	".sql": "SQL", ".hs": "Haskell", ".sh": "Shell", ".bash": "Shell", ".zsh": "Shell", ".pl": "Perl", ".pm": "Perl",
+	".r": "R", ".yml": "YAML", ".yaml": "YAML", ".toml": "TOML", ".mk": "Makefile", ".dockerfile": "Dockerfile",
+	".cmake": "CMake", ".html": "HTML", ".htm": "HTML", ".xml": "XML", ".md": "Markdown", ".markdown": "Markdown",
+	".json": "JSON", ".txt": "Text",
+}
+
+// Fichiers reconnus à leur nom, sans extension ou avec une extension trompeuse
+var languageFilenames = map[string]string{
+	"Makefile": "Makefile", "GNUmakefile": "Makefile", "makefile": "Makefile",
+	"Dockerfile": "Dockerfile", "Containerfile": "Dockerfile", "CMakeLists.txt": "CMake",
+	"Rakefile": "Ruby", "Gemfile": "Ruby", "Vagrantfile": "Ruby",
+	".bashrc": "Shell", ".bash_profile": "Shell", ".profile": "Shell", ".zshrc": "Shell",
+}
+
+// Interpréteurs des shebangs, sans numéro de version (python3.11 → python)
+var languageInterpreters = map[string]string{
+	"sh": "Shell", "bash": "Shell", "zsh": "Shell", "dash": "Shell", "ksh": "Shell",
+	"python": "Python", "node": "JavaScript", "ruby": "Ruby", "perl": "Perl", "php": "PHP", "lua": "Lua", "Rscript": "R",
+}
+
+// Dossiers de dépendances copiées, ignorés sauf -linguist-vendored
+var vendoredDirs = map[string]bool{"vendor": true, "node_modules": true, "third_party": true, "bower_components": true}
+
+// Fichiers de verrouillage produits par les gestionnaires de paquets, ignorés sauf -linguist-generated
+var generatedFilenames = map[string]bool{
+	"go.sum": true, "package-lock.json": true, "yarn.lock": true, "pnpm-lock.yaml": true,
+	"Cargo.lock": true, "composer.lock": true, "poetry.lock": true, "Gemfile.lock": true,
+}
+
+type LanguageStats struct {
+	Language string `json:"language"`
+	Files    int    `json:"files"`
+	Code     int    `json:"code"`
+	Comments int    `json:"comments"`
+	Blank    int    `json:"blank"`
+}
+
+func baseName(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// Interpréteur d'un shebang: "#!/usr/bin/env -S python3 -u" donne "python"
+func shebangInterpreter(data []byte) string {
+	if !bytes.HasPrefix(data, []byte("#!")) {
+		return ""
 	}
-	
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Value > sorted[j].Value
-	})
-	
-	fmt.Println("📂 Types de fichiers:")
-	for i, kv := range sorted {
-		if i >= 5 {
-			break
+	line, _, _ := bytes.Cut(data[2:], []byte("\n"))
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	name := baseName(fields[0])
+	if name == "env" {
+		name = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") {
+				name = field
+				break
+			}
 		}
-		fmt.Printf("  • %s: %d fichiers\n", kv.Key, kv.Value)
 	}
-	fmt.Println()
+	return strings.TrimRight(name, "0123456789.")
 }
 
-// Fonctions existantes simplifiées
-func (ga *GitAssistant) initRepo() error {
-	fmt.Println("🔧 Initialisation du dépôt Git...")
-	_, err := ga.runCommand("git", "init")
-	if err != nil {
-		return fmt.Errorf("erreur lors de l'initialisation: %v", err)
+// Langage d'un fichier: nom connu, puis extension, puis shebang
+func detectLanguage(path string, data []byte) string {
+	name := baseName(path)
+	if language, ok := languageFilenames[name]; ok {
+		return language
 	}
-	fmt.Println("✅ Dépôt Git initialisé avec succès!")
-	ga.addToHistory("Dépôt initialisé")
-	return nil
+	if strings.HasPrefix(name, "Dockerfile.") {
+		return "Dockerfile"
+	}
+	if language, ok := languageExtensions[strings.ToLower(filepath.Ext(name))]; ok {
+		return language
+	}
+	if language, ok := languageInterpreters[shebangInterpreter(data)]; ok {
+		return language
+	}
+	return languageOther
 }
 
-func (ga *GitAssistant) getStatus() (string, error) {
-	return ga.runCommand("git", "status", "--porcelain")
+// Même règle que Git: un octet nul dans les 8000 premiers octets
+func isBinaryContent(data []byte) bool {
+	if len(data) > 8000 {
+		data = data[:8000]
+	}
+	return bytes.IndexByte(data, 0) >= 0
 }
 
-func (ga *GitAssistant) addAll() error {
-	fmt.Println("📝 Ajout de tous les fichiers...")
-	_, err := ga.runCommand("git", "add", ".")
-	if err != nil {
-		return fmt.Errorf("erreur lors de l'ajout des fichiers: %v", err)
+func isVendoredPath(path string) bool {
+	segments := strings.Split(path, "/")
+	for _, segment := range segments[:len(segments)-1] {
+		if vendoredDirs[segment] {
+			return true
+		}
 	}
-	fmt.Println("✅ Fichiers ajoutés!")
-	return nil
+	return strings.HasSuffix(path, ".min.js") || strings.HasSuffix(path, ".min.css")
 }
 
-func (ga *GitAssistant) commit(message string) error {
-	if message == "" {
-		message = fmt.Sprintf("Auto-commit: %s", time.Now().Format("2006-01-02 15:04:05"))
+// Fichier de verrouillage, ou en-tête "Code generated ... DO NOT EDIT." (Go) ou "@generated"
+func isGeneratedFile(path string, data []byte) bool {
+	if generatedFilenames[baseName(path)] {
+		return true
 	}
-	
-	fmt.Printf("💾 Commit avec le message: %s\n", message)
-	_, err := ga.runCommand("git", "commit", "-m", message)
-	if err != nil {
-		return fmt.Errorf("erreur lors du commit: %v", err)
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
 	}
-	fmt.Println("✅ Commit effectué!")
-	return nil
+	return bytes.Contains(head, []byte("@generated")) ||
+		(bytes.Contains(head, []byte("Code generated")) && bytes.Contains(head, []byte("DO NOT EDIT")))
 }
 
-func (ga *GitAssistant) autoSync() error {
-	fmt.Println("🔄 Synchronisation automatique...")
-	
-	status, err := ga.getStatus()
+type lineCounts struct {
+	Code, Comments, Blank int
+}
+
+func countLines(data []byte, syntax languageSyntax) lineCounts {
+	var counts lineCounts
+	if len(data) == 0 {
+		return counts
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	closing := "" // fin du commentaire de bloc en cours
+	for _, line := range lines {
+		text := strings.TrimSpace(line)
+		switch {
+		case text == "":
+			counts.Blank++
+		case closing != "":
+			counts.Comments++
+			if strings.Contains(text, closing) {
+				closing = ""
+			}
+		default:
+			comment := false
+			for _, block := range syntax.Block {
+				if strings.HasPrefix(text, block[0]) {
+					comment = true
+					if !strings.Contains(text[len(block[0]):], block[1]) {
+						closing = block[1]
+					}
+					break
+				}
+			}
+			for _, marker := range syntax.Line {
+				comment = comment || strings.HasPrefix(text, marker)
+			}
+			if comment {
+				counts.Comments++
+			} else {
+				counts.Code++
+			}
+		}
+	}
+	return counts
+}
+
+// Règle d'un fichier .gitattributes, limitée aux attributs linguist-vendored et
+// linguist-generated: "true", "false", ou "" pour revenir au comportement par défaut (!attr)
+type attributeRule struct {
+	Dir     string // dossier du .gitattributes, "" à la racine
+	Pattern string
+	Values  map[string]string
+}
+
+func parseGitattributes(dir, content string) []attributeRule {
+	var rules []attributeRule
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		rule := attributeRule{Dir: dir, Pattern: fields[0], Values: make(map[string]string)}
+		for _, attr := range fields[1:] {
+			value := "true"
+			switch {
+			case strings.HasPrefix(attr, "-"):
+				attr, value = attr[1:], "false"
+			case strings.HasPrefix(attr, "!"):
+				attr, value = attr[1:], ""
+			case strings.Contains(attr, "="):
+				attr, value, _ = strings.Cut(attr, "=")
+				if value != "false" {
+					value = "true"
+				}
+			}
+			if attr == "linguist-vendored" || attr == "linguist-generated" {
+				rule.Values[attr] = value
+			}
+		}
+		if len(rule.Values) > 0 {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Motifs de .gitattributes: sans "/", le motif porte sur le nom du fichier à toute
+// profondeur; avec "/", sur le chemin relatif au dossier du .gitattributes ("**" compris)
+func (rule attributeRule) matches(path string) bool {
+	if rule.Dir != "" {
+		if !strings.HasPrefix(path, rule.Dir+"/") {
+			return false
+		}
+		path = path[len(rule.Dir)+1:]
+	}
+	if !strings.Contains(rule.Pattern, "/") {
+		matched, _ := filepath.Match(rule.Pattern, baseName(path))
+		return matched
+	}
+	return matchPathSegments(strings.Split(strings.TrimPrefix(rule.Pattern, "/"), "/"), strings.Split(path, "/"))
+}
+
+func matchPathSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(path); i >= 0; i-- {
+				if matchPathSegments(pattern[1:], path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(path) == 0 {
+			return false
+		}
+		if matched, _ := filepath.Match(pattern[0], path[0]); !matched {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0
+}
+
+// Lignes par langage des fichiers texte suivis à HEAD, du plus de code au moins de code.
+// Les fichiers vendored ou générés sont comptés à part dans excluded.
+func (ga *GitAssistant) languageBreakdown() (languages []LanguageStats, excluded int, err error) {
+	type fileLines struct {
+		Path, Language string
+		Generated      bool
+		lineCounts
+	}
+	var files []fileLines
+	var rules []attributeRule
+	err = ga.backend.WalkHeadBlobs(func(path string, data []byte) error {
+		if baseName(path) == ".gitattributes" {
+			rules = append(rules, parseGitattributes(strings.TrimSuffix(strings.TrimSuffix(path, ".gitattributes"), "/"), string(data))...)
+		}
+		if !isBinaryContent(data) {
+			language := detectLanguage(path, data)
+			files = append(files, fileLines{path, language, isGeneratedFile(path, data), countLines(data, languageSyntaxes[language])})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	
+	// Les .gitattributes des sous-dossiers l'emportent sur ceux de la racine,
+	// et dans un même fichier la dernière ligne l'emporte
+	depth := func(dir string) int {
+		if dir == "" {
+			return 0
+		}
+		return strings.Count(dir, "/") + 1
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return depth(rules[i].Dir) < depth(rules[j].Dir) })
+	
+	byLanguage := make(map[string]*LanguageStats)
+	for _, file := range files {
+		vendored, generated := isVendoredPath(file.Path), file.Generated
+		for _, rule := range rules {
+			if !rule.matches(file.Path) {
+				continue
+			}
+			if value, ok := rule.Values["linguist-vendored"]; ok {
+				vendored = value == "true" || (value == "" && isVendoredPath(file.Path))
+			}
+			if value, ok := rule.Values["linguist-generated"]; ok {
+				generated = value == "true" || (value == "" && file.Generated)
+			}
+		}
+		if vendored || generated {
+			excluded++
+			continue
+		}
+		stats := byLanguage[file.Language]
+		if stats == nil {
+			stats = &LanguageStats{Language: file.Language}
+			byLanguage[file.Language] = stats
+		}
+		stats.Files++
+		stats.Code += file.Code
+		stats.Comments += file.Comments
+		stats.Blank += file.Blank
+	}
+	
+	languages = []LanguageStats{}
+	for _, stats := range byLanguage {
+		languages = append(languages, *stats)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].Code != languages[j].Code {
+			return languages[i].Code > languages[j].Code
+		}
+		return languages[i].Language < languages[j].Language
+	})
+	return languages, excluded, nil
+}
+
+func (ga *GitAssistant) printLanguages(languages []LanguageStats, excluded int) {
+	fmt.Fprintf(ga.out, "📂 %s\n", cyan(tr("Langages (fichiers suivis à HEAD):")))
+	fmt.Fprintf(ga.out, "  %-12s %8s %9s %13s %8s\n", tr("Langage"), tr("Fichiers"), tr("Code"), tr("Commentaires"), tr("Vides"))
+	for i, stats := range languages {
+		if i >= 10 {
+			fmt.Fprintf(ga.out, tr("  … et %d autres\n"), len(languages)-i)
+			break
+		}
+		name := stats.Language
+		if name == languageOther {
+			name = tr("Autre")
+		}
+		fmt.Fprintf(ga.out, "  %-12s %8d %s %13d %8d\n", name, stats.Files, green(fmt.Sprintf("%9d", stats.Code)), stats.Comments, stats.Blank)
+	}
+	if excluded > 0 {
+		fmt.Fprintf(ga.out, tr("  ℹ️ %d fichier(s) vendored ou générés ignorés (.gitattributes: linguist-vendored, linguist-generated)\n"), excluded)
+	}
+	fmt.Fprintln(ga.out)
+}
+
+// Contributeurs
+
+// Activité d'un auteur. Le backend fusionne déjà les identités déclarées dans .mailmap;
+// les commits restants sont regroupés par adresse e-mail, sans tenir compte de la casse.
+type AuthorStats struct {
+	Name    string    `json:"name"`
+	Email   string    `json:"email"`
+	Commits int       `json:"commits"`
+	Added   int       `json:"added"`
+	Deleted int       `json:"deleted"`
+	First   time.Time `json:"first_commit"`
+	Last    time.Time `json:"last_commit"`
+}
+
+// Lignes écrites par un auteur dans un dossier
+type OwnerShare struct {
+	Name  string `json:"name"`
+	Lines int    `json:"lines"`
+}
+
+// Bus factor d'un dossier de premier niveau: nombre minimal d'auteurs qui ont écrit
+// plus de la moitié de ses lignes (Owners). "." désigne les fichiers à la racine.
+type DirectoryOwnership struct {
+	Path      string       `json:"path"`
+	Lines     int          `json:"lines"`
+	BusFactor int          `json:"bus_factor"`
+	Owners    []OwnerShare `json:"owners"`
+}
+
+func authorKey(commit CommitStats) string {
+	if commit.Email == "" {
+		return strings.ToLower(commit.Author)
+	}
+	return strings.ToLower(commit.Email)
+}
+
+// Auteurs du plus actif au moins actif. L'historique va du plus récent au plus ancien:
+// le nom retenu pour une adresse est le dernier utilisé.
+func aggregateAuthors(history []CommitStats) []AuthorStats {
+	index := make(map[string]int)
+	authors := []AuthorStats{}
+	for _, commit := range history {
+		key := authorKey(commit)
+		i, ok := index[key]
+		if !ok {
+			i = len(authors)
+			index[key] = i
+			authors = append(authors, AuthorStats{Name: commit.Author, Email: commit.Email, First: commit.Date, Last: commit.Date})
+		}
+		author := &authors[i]
+		author.Commits++
+		for _, file := range commit.Files {
+			author.Added += file.Added
+			author.Deleted += file.Deleted
+		}
+		if commit.Date.Before(author.First) {
+			author.First = commit.Date
+		}
+		if commit.Date.After(author.Last) {
+			author.Last = commit.Date
+		}
+	}
+	sort.SliceStable(authors, func(i, j int) bool {
+		if authors[i].Commits != authors[j].Commits {
+			return authors[i].Commits > authors[j].Commits
+		}
+		return authors[i].Added > authors[j].Added
+	})
+	return authors
+}
+
+func topLevelDir(path string) string {
+	if dir, _, ok := strings.Cut(path, "/"); ok {
+		return dir
+	}
+	return "."
+}
+
+// Bus factor des dossiers de premier niveau encore suivis (files). Les lignes comptées
+// sont les lignes ajoutées par chaque auteur; un fichier renommé compte pour celui qui l'a déplacé.
+// Les dossiers les plus fragiles viennent en premier.
+func busFactors(history []CommitStats, files []string) []DirectoryOwnership {
+	tracked := make(map[string]bool)
+	for _, file := range files {
+		if file != "" {
+			tracked[topLevelDir(file)] = true
+		}
+	}
+	names := make(map[string]string)
+	lines := make(map[string]map[string]int)
+	for _, commit := range history {
+		key := authorKey(commit)
+		if _, ok := names[key]; !ok {
+			names[key] = commit.Author
+		}
+		for _, file := range commit.Files {
+			dir := topLevelDir(file.Path)
+			if !tracked[dir] || file.Added == 0 {
+				continue
+			}
+			if lines[dir] == nil {
+				lines[dir] = make(map[string]int)
+			}
+			lines[dir][key] += file.Added
+		}
+	}
+	
+	dirs := []DirectoryOwnership{}
+	for dir, byAuthor := range lines {
+		ownership := DirectoryOwnership{Path: dir}
+		shares := make([]OwnerShare, 0, len(byAuthor))
+		for key, count := range byAuthor {
+			shares = append(shares, OwnerShare{names[key], count})
+			ownership.Lines += count
+		}
+		sort.Slice(shares, func(i, j int) bool {
+			if shares[i].Lines != shares[j].Lines {
+				return shares[i].Lines > shares[j].Lines
+			}
+			return shares[i].Name < shares[j].Name
+		})
+		written := 0
+		for _, share := range shares {
+			ownership.Owners = append(ownership.Owners, share)
+			ownership.BusFactor++
+			if written += share.Lines; written*2 > ownership.Lines {
+				break
+			}
+		}
+		dirs = append(dirs, ownership)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i].BusFactor != dirs[j].BusFactor {
+			return dirs[i].BusFactor < dirs[j].BusFactor
+		}
+		if dirs[i].Lines != dirs[j].Lines {
+			return dirs[i].Lines > dirs[j].Lines
+		}
+		return dirs[i].Path < dirs[j].Path
+	})
+	return dirs
+}
+
+func (ga *GitAssistant) printContributors(authors []AuthorStats) {
+	fmt.Fprintf(ga.out, "👥 %s:\n", cyan(fmt.Sprintf(tr("Contributeurs (%d)"), len(authors))))
+	for i, author := range authors {
+		if i >= 10 {
+			fmt.Fprintf(ga.out, tr("  … et %d autres\n"), len(authors)-i)
+			break
+		}
+		fmt.Fprintf(ga.out, tr("  • %s <%s>: %s commits, %s %s lignes, du %s au %s\n"),
+			author.Name, author.Email, green(strconv.Itoa(author.Commits)),
+			green("+"+strconv.Itoa(author.Added)), red("-"+strconv.Itoa(author.Deleted)),
+			author.First.Local().Format("2006-01-02"), author.Last.Local().Format("2006-01-02"))
+	}
+	fmt.Fprintln(ga.out)
+}
+
+func (ga *GitAssistant) printBusFactors(dirs []DirectoryOwnership) {
+	fmt.Fprintf(ga.out, "🚌 %s\n", cyan(tr("Bus factor par dossier (auteurs qui ont écrit plus de la moitié des lignes):")))
+	for _, dir := range dirs {
+		path := dir.Path + "/"
+		if dir.Path == "." {
+			path = tr("(racine)")
+		}
+		owners := make([]string, len(dir.Owners))
+		for i, owner := range dir.Owners {
+			owners[i] = fmt.Sprintf("%s %d%%", owner.Name, owner.Lines*100/dir.Lines)
+		}
+		factor := green(strconv.Itoa(dir.BusFactor))
+		if dir.BusFactor == 1 {
+			factor = red("1") + " ⚠️"
+		}
+		fmt.Fprintf(ga.out, "  • %s: %s (%s)\n", path, factor, strings.Join(owners, ", "))
+	}
+	fmt.Fprintln(ga.out)
+}
+
+// Points chauds: fichiers les plus modifiés et fichiers modifiés ensemble
+
+// Lignes des tableaux de points chauds
+const churnTableSize = 10
+
+// Au-delà, un commit (reformatage, déplacement de dossier) ne compte pas pour le couplage
+const couplingMaxFiles = 30
+
+type FileChurn struct {
+	Path    string `json:"path"`
+	Commits int    `json:"commits"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+}
+
+func (f FileChurn) lines() int { return f.Added + f.Deleted }
+
+// Deux fichiers modifiés dans les mêmes commits. Degree est le pourcentage de commits
+// communs par rapport à la moyenne des commits de chacun.
+type CoChange struct {
+	First    string `json:"first"`
+	Second   string `json:"second"`
+	Together int    `json:"together"`
+	Degree   int    `json:"degree"`
+}
+
+// Since est nul (null en JSON) quand tout l'historique est analysé
+type ChurnReport struct {
+	Since       *time.Time  `json:"since"`
+	Commits     int         `json:"commits"`
+	MostChanged []FileChurn `json:"most_changed"`
+	MostChurned []FileChurn `json:"most_churned"`
+	Coupled     []CoChange  `json:"coupled"`
+}
+
+// Début de la fenêtre des points chauds d'après insights.churn_days
+func (ga *GitAssistant) churnSince() time.Time {
+	if ga.config.ChurnDays == 0 {
+		return time.Time{}
+	}
+	return time.Now().AddDate(0, 0, -ga.config.ChurnDays)
+}
+
+// Classements limités aux fichiers encore suivis (files); un couple doit apparaître
+// dans au moins deux commits pour être retenu
+func analyzeChurn(history []CommitStats, files []string, since time.Time) ChurnReport {
+	report := ChurnReport{Commits: len(history), MostChanged: []FileChurn{}, MostChurned: []FileChurn{}, Coupled: []CoChange{}}
+	if !since.IsZero() {
+		report.Since = &since
+	}
+	tracked := make(map[string]bool, len(files))
+	for _, file := range files {
+		tracked[file] = true
+	}
+	
+	stats := make(map[string]*FileChurn)
+	pairs := make(map[[2]string]int)
+	for _, commit := range history {
+		var changed []string
+		for _, file := range commit.Files {
+			if !tracked[file.Path] {
+				continue
+			}
+			stat := stats[file.Path]
+			if stat == nil {
+				stat = &FileChurn{Path: file.Path}
+				stats[file.Path] = stat
+			}
+			stat.Commits++
+			stat.Added += file.Added
+			stat.Deleted += file.Deleted
+			changed = append(changed, file.Path)
+		}
+		if len(changed) > couplingMaxFiles {
+			continue
+		}
+		sort.Strings(changed)
+		for i := range changed {
+			for j := i + 1; j < len(changed); j++ {
+				pairs[[2]string{changed[i], changed[j]}]++
+			}
+		}
+	}
+	
+	for _, stat := range stats {
+		report.MostChanged = append(report.MostChanged, *stat)
+		if stat.lines() > 0 {
+			report.MostChurned = append(report.MostChurned, *stat)
+		}
+	}
+	sort.Slice(report.MostChanged, func(i, j int) bool {
+		a, b := report.MostChanged[i], report.MostChanged[j]
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		return a.Path < b.Path
+	})
+	sort.Slice(report.MostChurned, func(i, j int) bool {
+		a, b := report.MostChurned[i], report.MostChurned[j]
+		if a.lines() != b.lines() {
+			return a.lines() > b.lines()
+		}
+		return a.Path < b.Path
+	})
+	
+	for pair, together := range pairs {
+		if together < 2 {
+			continue
+		}
+		average := float64(stats[pair[0]].Commits+stats[pair[1]].Commits) / 2
+		degree := int(float64(together)*100/average + 0.5)
+		report.Coupled = append(report.Coupled, CoChange{pair[0], pair[1], together, degree})
+	}
+	sort.Slice(report.Coupled, func(i, j int) bool {
+		a, b := report.Coupled[i], report.Coupled[j]
+		if a.Together != b.Together {
+			return a.Together > b.Together
+		}
+		if a.Degree != b.Degree {
+			return a.Degree > b.Degree
+		}
+		return a.First+"\x00"+a.Second < b.First+"\x00"+b.Second
+	})
+	
+	if len(report.MostChanged) > churnTableSize {
+		report.MostChanged = report.MostChanged[:churnTableSize]
+	}
+	if len(report.MostChurned) > churnTableSize {
+		report.MostChurned = report.MostChurned[:churnTableSize]
+	}
+	if len(report.Coupled) > churnTableSize {
+		report.Coupled = report.Coupled[:churnTableSize]
+	}
+	return report
+}
+
+// Largeur de la colonne des chemins, bornée pour les chemins très longs
+func pathColumnWidth(paths []string) int {
+	width := 0
+	for _, path := range paths {
+		if length := len([]rune(path)); length > width {
+			width = length
+		}
+	}
+	if width > 60 {
+		width = 60
+	}
+	return width
+}
+
+func (ga *GitAssistant) printChurn(report ChurnReport) {
+	window := tr("tout l'historique")
+	if report.Since != nil {
+		window = fmt.Sprintf(tr("depuis le %s"), report.Since.Local().Format("2006-01-02"))
+	}
+	fmt.Fprintf(ga.out, "🔥 %s\n", cyan(fmt.Sprintf(tr("Points chauds (%s, %d commits)"), window, report.Commits)))
+	if len(report.MostChanged) == 0 {
+		fmt.Fprintln(ga.out, tr("  Aucun fichier suivi modifié sur la période"))
+		fmt.Fprintln(ga.out)
+		return
+	}
+	
+	var paths []string
+	for _, file := range report.MostChanged {
+		paths = append(paths, file.Path)
+	}
+	for _, file := range report.MostChurned {
+		paths = append(paths, file.Path)
+	}
+	width := pathColumnWidth(paths)
+	
+	fmt.Fprintln(ga.out, bold(tr("  Les plus souvent modifiés:")))
+	for i, file := range report.MostChanged {
+		fmt.Fprintf(ga.out, tr("  %2d. %-*s %4d commits  %s %s\n"), i+1, width, file.Path, file.Commits,
+			green(fmt.Sprintf("+%d", file.Added)), red(fmt.Sprintf("-%d", file.Deleted)))
+	}
+	if len(report.MostChurned) > 0 {
+		fmt.Fprintln(ga.out, bold(tr("  Le plus de lignes changées:")))
+		for i, file := range report.MostChurned {
+			fmt.Fprintf(ga.out, tr("  %2d. %-*s %6d lignes   %s %s\n"), i+1, width, file.Path, file.lines(),
+				green(fmt.Sprintf("+%d", file.Added)), red(fmt.Sprintf("-%d", file.Deleted)))
+		}
+	}
+	if len(report.Coupled) > 0 {
+		fmt.Fprintln(ga.out, bold(tr("  Souvent modifiés ensemble:")))
+		for i, pair := range report.Coupled {
+			fmt.Fprintf(ga.out, tr("  %2d. %s ↔ %s: %d commits communs (%d%%)\n"), i+1, pair.First, pair.Second, pair.Together, pair.Degree)
+		}
+	}
+	fmt.Fprintln(ga.out)
+}
+
+// Indexation sélective
+
+// Case à cocher d'un fichier: [x] indexé, [~] en partie, [!] en conflit
+func stagingMark(entry StatusEntry) string {
+	switch {
+	case entry.Conflict:
+		return red("[!]")
+	case entry.Index == '?' || entry.Index == ' ':
+		return "[ ]"
+	case entry.Worktree != ' ':
+		return cyan("[~]")
+	default:
+		return green("[x]")
+	}
+}
+
+func isFullyStaged(entry StatusEntry) bool {
+	return !entry.Conflict && entry.Index != '?' && entry.Index != ' ' && entry.Worktree == ' '
+}
+
+func (ga *GitAssistant) stagingScreen() error {
+	for {
+		status, err := ga.getStatus()
+		if err != nil {
+			return err
+		}
+		if len(status) == 0 {
+			fmt.Fprintln(ga.out, tr("ℹ️ Aucun changement à indexer"))
+			return nil
+		}
+		
+		fmt.Fprintf(ga.out, "\n📋 === %s ===\n", bold(tr("INDEXATION SÉLECTIVE")))
+		for i, entry := range status {
+			path := entry.Path
+			if entry.OrigPath != "" {
+				path = entry.OrigPath + " → " + entry.Path
+			}
+			fmt.Fprintf(ga.out, "%s %2d. %c%c %s\n", stagingMark(entry), i+1, entry.Index, entry.Worktree, path)
+		}
+		
+		fmt.Fprintf(ga.out, "\n%s:\n", cyan(tr("Commandes")))
+		fmt.Fprintln(ga.out, tr("  <n°> [n°...]  basculer des fichiers    <dossier>/  basculer un dossier"))
+		fmt.Fprintln(ga.out, tr("  a  tout indexer    n  tout désindexer"))
+		fmt.Fprintln(ga.out, tr("  p <n°>  indexer par morceaux    u <n°>  désindexer par morceaux"))
+		fmt.Fprintln(ga.out, tr("  c  commiter la sélection    q  quitter"))
+		fmt.Fprint(ga.out, cyan("\nAction: "))
+		
+		input := ga.getUserInput()
+		if ga.inputClosed {
+			return nil
+		}
+		command, arg, _ := strings.Cut(input, " ")
+		
+		switch {
+		case input == "" || input == "q":
+			return nil
+		case input == "a":
+			err = ga.backend.Add(".")
+		case input == "n":
+			err = ga.backend.Unstage()
+		case input == "c":
+			if len(categorizeChanges(status).Staged) == 0 {
+				fmt.Fprintln(ga.out, red(tr("❌ Aucun fichier indexé")))
+				continue
+			}
+			message, err := ga.chooseCommitMessage(categorizeChanges(status).Staged)
+			if err != nil {
+				return err
+			}
+			return ga.commitSelectionWithMessage(message)
+		case command == "p" || command == "u":
+			entry, ok := pickEntry(status, arg)
+			if !ok {
+				fmt.Fprintln(ga.out, red(tr("❌ Numéro de fichier invalide")))
+				continue
+			}
+			err = ga.stageHunks(entry, command == "u")
+		default:
+			err = ga.toggleEntries(status, input)
+		}
+		if err != nil {
+			fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+		}
+	}
+}
+
+func pickEntry(status []StatusEntry, number string) (StatusEntry, bool) {
+	idx, err := strconv.Atoi(strings.TrimSpace(number))
+	if err != nil || idx < 1 || idx > len(status) {
+		return StatusEntry{}, false
+	}
+	return status[idx-1], true
+}
+
+// Bascule une liste de numéros ou un dossier: tout est désindexé si tout
+// était déjà indexé, sinon tout est indexé
+func (ga *GitAssistant) toggleEntries(status []StatusEntry, selection string) error {
+	var group []StatusEntry
+	for _, field := range strings.FieldsFunc(selection, func(r rune) bool { return r == ' ' || r == ',' }) {
+		if entry, ok := pickEntry(status, field); ok {
+			group = append(group, entry)
+			continue
+		}
+		dir := strings.TrimSuffix(filepath.ToSlash(field), "/")
+		matched := false
+		for _, entry := range status {
+			if dir == "." || entry.Path == dir || strings.HasPrefix(entry.Path, dir+"/") {
+				group = append(group, entry)
+				matched = true
+			}
+		}
+		if !matched {
+			return fmt.Errorf(tr("aucun fichier ne correspond à '%s'"), field)
+		}
+	}
+	if len(group) == 0 {
+		return nil
+	}
+	
+	allStaged := true
+	var paths []string
+	for _, entry := range group {
+		allStaged = allStaged && isFullyStaged(entry)
+		paths = append(paths, entry.Path)
+		// Un renommage se désindexe avec son chemin d'origine
+		if entry.OrigPath != "" {
+			paths = append(paths, entry.OrigPath)
+		}
+	}
+	
+	if allStaged {
+		if err := ga.backend.Unstage(paths...); err != nil {
+			return err
+		}
+		fmt.Fprintf(ga.out, tr("➖ %d fichier(s) désindexé(s)\n"), len(group))
+		return nil
+	}
+	if err := ga.backend.Add(paths...); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("➕ %d fichier(s) indexé(s)\n"), len(group))
+	return nil
+}
+
+// Propose chaque morceau du diff d'un fichier, à la manière de git add -p
+func (ga *GitAssistant) stageHunks(entry StatusEntry, unstage bool) error {
+	if entry.Index == '?' {
+		return errors.New(tr("fichier non suivi: indexez-le en entier"))
+	}
+	diff, err := ga.backend.Diff(entry.Path, unstage)
 	if err != nil {
 		return err
 	}
+	patch := parseFilePatch(diff)
+	if len(patch.Hunks) == 0 {
+		fmt.Fprintln(ga.out, tr("ℹ️ Aucun morceau texte pour ce fichier"))
+		return nil
+	}
 	
-	if strings.TrimSpace(status) == "" {
-		fmt.Println("ℹ️ Aucun changement détecté")
+	question := tr("Indexer ce morceau?")
+	if unstage {
+		question = tr("Désindexer ce morceau?")
+	}
+	question = fmt.Sprintf("%s (%s/q): ", question, yesNoHint(false))
+	var selected []diffHunk
+	for i, hunk := range patch.Hunks {
+		fmt.Fprintf(ga.out, tr("\n🧩 Morceau %d/%d de %s\n"), i+1, len(patch.Hunks), entry.Path)
+		ga.displayColoredDiff(strings.Join(hunk.Lines, "\n"))
+		fmt.Fprint(ga.out, cyan(question))
+		answer := strings.ToLower(ga.getUserInput())
+		if answer == "q" || ga.inputClosed {
+			break
+		}
+		if isYes(answer) {
+			selected = append(selected, hunk)
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Fprintln(ga.out, tr("ℹ️ Aucun morceau sélectionné"))
 		return nil
 	}
 	
-	if err := ga.addAll(); err != nil {
+	if err := ga.backend.ApplyToIndex(patch.build(selected), unstage); err != nil {
 		return err
 	}
-	
-	if err := ga.commit(""); err != nil {
-		return err
+	fmt.Fprintf(ga.out, tr("✅ %d morceau(x) appliqué(s) à l'index\n"), len(selected))
+	return nil
+}
+
+// Commite uniquement l'index, sans rien ajouter
+func (ga *GitAssistant) commitSelectionWithMessage(message string) (err error) {
+	defer ga.journal("commit.staged", message)(&err)
+	status, err := ga.getStatus()
+	if err != nil {
+		return err
+	}
+	staged := categorizeChanges(status).Staged
+	if len(staged) == 0 {
+		return errors.New(tr("aucun fichier indexé"))
+	}
+	
+	fmt.Fprintf(ga.out, tr("📥 Fichiers sélectionnés (%d): %s\n"), len(staged), strings.Join(staged, ", "))
+	if err := ga.commit(message); err != nil {
+		return err
+	}
+	
+	ga.addToHistory(fmt.Sprintf(tr("Commit sélectif (%d fichiers): %s"), len(staged), message))
+	return nil
+}
+
+// Diff unifié d'un fichier découpé en morceaux
+type diffHunk struct {
+	Lines []string
+}
+
+type filePatch struct {
+	Header []string
+	Hunks  []diffHunk
+}
+
+func parseFilePatch(diff string) filePatch {
+	var patch filePatch
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			patch.Hunks = append(patch.Hunks, diffHunk{Lines: []string{line}})
+		case len(patch.Hunks) > 0:
+			last := &patch.Hunks[len(patch.Hunks)-1]
+			last.Lines = append(last.Lines, line)
+		case line != "":
+			patch.Header = append(patch.Header, line)
+		}
+	}
+	return patch
+}
+
+// Patch ne contenant que les morceaux choisis; git apply recale les
+// positions décalées par les morceaux écartés
+func (p filePatch) build(hunks []diffHunk) string {
+	lines := append([]string{}, p.Header...)
+	for _, hunk := range hunks {
+		lines = append(lines, hunk.Lines...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Fonctions existantes simplifiées
+func (ga *GitAssistant) initRepo() (err error) {
+	defer ga.journal("init")(&err)
+	fmt.Fprintln(ga.out, tr("🔧 Initialisation du dépôt Git..."))
+	err = ga.backend.Init()
+	if err != nil {
+		return fmt.Errorf(tr("erreur lors de l'initialisation: %v"), err)
+	}
+	fmt.Fprintln(ga.out, tr("✅ Dépôt Git initialisé avec succès!"))
+	ga.addToHistory(tr("Dépôt initialisé"))
+	return nil
+}
+
+func (ga *GitAssistant) getStatus() ([]StatusEntry, error) {
+	status, err := ga.backend.Status()
+	return status.Entries, err
+}
+
+func (ga *GitAssistant) addAll() error {
+	fmt.Fprintln(ga.out, tr("📝 Ajout de tous les fichiers..."))
+	err := ga.backend.Add(".")
+	if err != nil {
+		return fmt.Errorf(tr("erreur lors de l'ajout des fichiers: %v"), err)
+	}
+	fmt.Fprintln(ga.out, tr("✅ Fichiers ajoutés!"))
+	return nil
+}
+
+func (ga *GitAssistant) commit(message string) error {
+	if message == "" {
+		message = fmt.Sprintf("Auto-commit: %s", time.Now().Format("2006-01-02 15:04:05"))
+	}
+	
+	fmt.Fprintf(ga.out, tr("💾 Commit avec le message: %s\n"), message)
+	err := ga.backend.Commit(message)
+	if err != nil {
+		return fmt.Errorf(tr("erreur lors du commit: %v"), err)
+	}
+	fmt.Fprintln(ga.out, tr("✅ Commit effectué!"))
+	return nil
+}
+
+// Étape de la synchronisation et son résultat, pour le résumé final
+type syncStep struct {
+	Name   string
+	Status string
+	Detail string
+}
+
+const (
+	syncDone       = "✅"
+	syncSkipped    = "⏭️"
+	syncFailed     = "❌"
+	syncRolledBack = "↩️"
+)
+
+// Synchronisation complète: ajout, commit (message généré), pull --rebase, push.
+// En cas d'échec, le dépôt est remis dans l'état d'avant la synchronisation.
+func (ga *GitAssistant) autoSync() error {
+	return ga.syncWithMessage("")
+}
+
+func (ga *GitAssistant) syncWithMessage(message string) (err error) {
+	defer ga.journal("sync", message)(&err)
+	fmt.Fprintf(ga.out, "🔄 === %s ===\n", bold(tr("SYNCHRONISATION AUTOMATIQUE")))
+	
+	status, err := ga.getStatus()
+	if err != nil {
+		return err
+	}
+	remotes, err := ga.backend.Remotes()
+	if err != nil {
+		return err
+	}
+	upstream, err := ga.backend.Upstream()
+	if err != nil {
+		return err
+	}
+	
+	// État de départ pour l'annulation
+	previousHead := ga.headHash()
+	syncCommit := ""
+	staged := false
+	
+	steps := []syncStep{
+		{Name: tr("Ajout des fichiers")},
+		{Name: "Commit"},
+		{Name: "Pull (rebase)"},
+		{Name: "Push"},
+	}
+	
+	var failure error
+	failedStep := -1
+	fail := func(index int, err error) {
+		steps[index].Status = syncFailed
+		steps[index].Detail = err.Error()
+		failure = err
+		failedStep = index
+	}
+	
+	if len(status) == 0 {
+		steps[0].Status, steps[0].Detail = syncSkipped, tr("aucun changement")
+		steps[1].Status, steps[1].Detail = syncSkipped, tr("aucun changement")
+	} else if err := ga.addAll(); err != nil {
+		fail(0, err)
+	} else {
+		staged = true
+		steps[0].Status = syncDone
+		steps[0].Detail = fmt.Sprintf(tr("%d fichier(s)"), len(status))
+		
+		// Relire le statut: une fois ajoutés, tous les changements sont dans l'index
+		if message == "" {
+			if stagedStatus, err := ga.getStatus(); err == nil {
+				status = stagedStatus
+			}
+			message = generateSyncMessage(categorizeChanges(status))
+		}
+		if err := ga.commit(message); err != nil {
+			fail(1, err)
+		} else {
+			syncCommit = ga.headHash()
+			steps[1].Status, steps[1].Detail = syncDone, message
+		}
+	}
+	
+	if failure == nil {
+		switch {
+		case len(remotes) == 0:
+			steps[2].Status, steps[2].Detail = syncSkipped, tr("aucun dépôt distant")
+		case upstream.Name == "":
+			steps[2].Status, steps[2].Detail = syncSkipped, tr("aucune branche suivie")
+		default:
+			if err := ga.pullCurrentBranch("", true); err != nil {
+				fail(2, err)
+			} else {
+				steps[2].Status, steps[2].Detail = syncDone, upstream.Name
+			}
+		}
+	}
+	
+	if failure == nil {
+		if len(remotes) == 0 {
+			steps[3].Status, steps[3].Detail = syncSkipped, tr("aucun dépôt distant")
+		} else if err := ga.pushCurrentBranch("", upstream.Name == ""); err != nil {
+			fail(3, err)
+		} else {
+			steps[3].Status = syncDone
+			if current, err := ga.backend.Upstream(); err == nil {
+				steps[3].Detail = current.Name
+			}
+		}
+	}
+	
+	if failure != nil {
+		for i := failedStep + 1; i < len(steps); i++ {
+			steps[i].Status, steps[i].Detail = syncSkipped, tr("interrompu")
+		}
+		ga.rollbackSync(failedStep == 2, previousHead, syncCommit, staged)
+		for i := 0; i < failedStep; i++ {
+			if steps[i].Status == syncDone {
+				steps[i].Status = syncRolledBack
+			}
+		}
+	}
+	
+	fmt.Fprintf(ga.out, "\n📋 %s:\n", bold(tr("Résumé de la synchronisation")))
+	for i, step := range steps {
+		detail := ""
+		if step.Detail != "" {
+			detail = " - " + step.Detail
+		}
+		fmt.Fprintf(ga.out, "  %d. %s %s%s\n", i+1, step.Status, step.Name, detail)
+	}
+	
+	if failure != nil {
+		fmt.Fprintln(ga.out, tr("↩️ Dépôt restauré dans son état d'avant la synchronisation"))
+		return fmt.Errorf(tr("synchronisation interrompue (%s): %v"), strings.ToLower(steps[failedStep].Name), failure)
+	}
+	
+	if len(remotes) == 0 {
+		fmt.Fprintln(ga.out, tr("🎉 Synchronisation locale terminée (aucun dépôt distant)!"))
+	} else {
+		fmt.Fprintln(ga.out, tr("🎉 Synchronisation terminée!"))
+	}
+	ga.addToHistory(tr("Synchronisation automatique"))
+	return nil
+}
+
+// Hash du commit HEAD, vide si le dépôt n'a pas encore de commit
+func (ga *GitAssistant) headHash() string {
+	commits, err := ga.backend.Log(1)
+	if err != nil || len(commits) == 0 {
+		return ""
+	}
+	return commits[0].Hash
+}
+
+// Remet HEAD, l'index et l'arbre de travail dans l'état d'avant la synchronisation:
+// les changements locaux redeviennent non commités, rien n'est perdu
+func (ga *GitAssistant) rollbackSync(rebaseStarted bool, previousHead, syncCommit string, staged bool) {
+	fmt.Fprintln(ga.out, tr("↩️ Annulation des étapes effectuées..."))
+	if rebaseStarted {
+		if err := ga.backend.AbortRebase(); err != nil {
+			fmt.Fprintf(ga.out, red(tr("⚠️ Abandon du rebase impossible: %v\n")), err)
+		}
+	}
+	
+	if previousHead == "" {
+		if syncCommit != "" {
+			fmt.Fprintln(ga.out, red(tr("⚠️ Premier commit du dépôt conservé: annulation impossible")))
+		}
+		return
+	}
+	
+	if syncCommit != "" {
+		// Le pull a pu réécrire le commit: revenir d'abord à celui créé par la synchronisation
+		if err := ga.backend.Reset("hard", syncCommit); err != nil {
+			fmt.Fprintf(ga.out, red(tr("⚠️ Restauration du commit de synchronisation impossible: %v\n")), err)
+			return
+		}
+	}
+	if syncCommit != "" || staged {
+		if err := ga.backend.Reset("mixed", previousHead); err != nil {
+			fmt.Fprintf(ga.out, red(tr("⚠️ Restauration de HEAD impossible: %v\n")), err)
+		}
+	}
+}
+
+func renamedPaths(renamed []RenamedFile) []string {
+	paths := make([]string, 0, len(renamed))
+	for _, file := range renamed {
+		paths = append(paths, file.To)
+	}
+	return paths
+}
+
+// Message de commit décrivant les changements synchronisés
+func generateSyncMessage(summary ChangeSummary) string {
+	var parts []string
+	var files []string
+	for _, group := range []struct {
+		label string
+		paths []string
+	}{
+		{tr("ajouté(s)"), append(append([]string{}, summary.Added...), summary.Untracked...)},
+		{tr("modifié(s)"), summary.Modified},
+		{tr("renommé(s)"), renamedPaths(summary.Renamed)},
+		{tr("supprimé(s)"), summary.Deleted},
+	} {
+		if len(group.paths) > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", len(group.paths), group.label))
+			files = append(files, group.paths...)
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("🔄 Sync: %s", time.Now().Format("2006-01-02 15:04:05"))
+	}
+	
+	const maxFiles = 3
+	names := make([]string, 0, maxFiles)
+	for i, file := range files {
+		if i == maxFiles {
+			names = append(names, fmt.Sprintf("+%d", len(files)-maxFiles))
+			break
+		}
+		names = append(names, filepath.Base(file))
+	}
+	return fmt.Sprintf("🔄 Sync: %s (%s)", strings.Join(parts, ", "), strings.Join(names, ", "))
+}
+
+// Dépôts distants
+
+// Branches de travail poussées automatiquement avec --set-upstream
+// (types de branches configurés avec upstream = true)
+func (ga *GitAssistant) isWorkBranch(branch string) bool {
+	for _, bt := range ga.config.BranchTypes {
+		if bt.Upstream && strings.HasPrefix(branch, bt.Prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ga *GitAssistant) remoteMenu() error {
+	fmt.Fprintf(ga.out, "🌐 === %s ===\n", bold(tr("DÉPÔTS DISTANTS")))
+	
+	if err := ga.printRemotes(); err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(ga.out, "\n%s:\n", cyan(tr("Actions disponibles")))
+	fmt.Fprintln(ga.out, tr("1. ➕ Ajouter un dépôt distant"))
+	fmt.Fprintln(ga.out, tr("2. 📥 Récupérer (fetch)"))
+	fmt.Fprintln(ga.out, tr("3. ⬇️ Tirer (pull)"))
+	fmt.Fprintln(ga.out, tr("4. ⬆️ Pousser (push)"))
+	fmt.Fprint(ga.out, cyan(tr("\nChoisissez (1-4): ")))
+	
+	switch ga.getUserInput() {
+	case "1":
+		fmt.Fprint(ga.out, tr("🏷️ Nom du dépôt distant (origin): "))
+		name := ga.getUserInput()
+		if name == "" {
+			name = "origin"
+		}
+		fmt.Fprint(ga.out, "🔗 URL: ")
+		url := ga.getUserInput()
+		if url == "" {
+			return errors.New(tr("URL requise"))
+		}
+		return ga.addRemote(name, url)
+	case "2":
+		return ga.fetchRemote("")
+	case "3":
+		fmt.Fprintln(ga.out, tr("1. 🔀 Fusion (merge)"))
+		fmt.Fprintln(ga.out, "2. 📐 Rebase")
+		fmt.Fprint(ga.out, cyan("Mode (1-2): "))
+		return ga.pullCurrentBranch("", ga.getUserInput() == "2")
+	case "4":
+		return ga.pushCurrentBranch("", false)
+	default:
+		fmt.Fprintln(ga.out, red(tr("❌ Choix invalide")))
+	}
+	return nil
+}
+
+func (ga *GitAssistant) printRemotes() error {
+	remotes, err := ga.backend.Remotes()
+	if err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(ga.out, "%s:\n", cyan(tr("Dépôts distants")))
+	if len(remotes) == 0 {
+		fmt.Fprintln(ga.out, tr("  Aucun dépôt distant configuré"))
+	}
+	for _, remote := range remotes {
+		fmt.Fprintf(ga.out, "  • %s → %s\n", green(remote.Name), remote.URL)
+	}
+	
+	if upstream, err := ga.backend.Upstream(); err == nil && upstream.Name != "" {
+		fmt.Fprintf(ga.out, tr("🔗 Branche suivie: %s (%s)\n"), upstream.Name, formatAheadBehind(upstream))
+	}
+	return nil
+}
+
+func formatAheadBehind(upstream UpstreamInfo) string {
+	if upstream.Ahead == 0 && upstream.Behind == 0 {
+		return tr("à jour")
+	}
+	return fmt.Sprintf("↑%d ↓%d", upstream.Ahead, upstream.Behind)
+}
+
+// Dépôt distant à utiliser: celui demandé, "origin", ou l'unique configuré
+func (ga *GitAssistant) defaultRemote(requested string) (string, error) {
+	if requested != "" {
+		return requested, nil
+	}
+	remotes, err := ga.backend.Remotes()
+	if err != nil {
+		return "", err
+	}
+	if len(remotes) == 0 {
+		return "", errors.New(tr("aucun dépôt distant configuré"))
+	}
+	for _, remote := range remotes {
+		if remote.Name == "origin" {
+			return remote.Name, nil
+		}
+	}
+	if len(remotes) == 1 {
+		return remotes[0].Name, nil
+	}
+	return "", errors.New(tr("plusieurs dépôts distants: précisez lequel utiliser"))
+}
+
+func (ga *GitAssistant) addRemote(name, url string) (err error) {
+	defer ga.journal("remote.add", name, url)(&err)
+	if err := ga.backend.AddRemote(name, url); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ Dépôt distant '%s' ajouté (%s)\n"), name, url)
+	ga.addToHistory(fmt.Sprintf(tr("Dépôt distant ajouté: %s"), name))
+	return nil
+}
+
+func (ga *GitAssistant) fetchRemote(remote string) (err error) {
+	defer ga.journal("fetch", remote)(&err)
+	label := remote
+	if label == "" {
+		label = tr("tous les dépôts distants")
+	}
+	fmt.Fprintf(ga.out, tr("📥 Récupération depuis %s...\n"), label)
+	if err := ga.backend.Fetch(remote); err != nil {
+		return err
+	}
+	fmt.Fprintln(ga.out, tr("✅ Récupération terminée!"))
+	if upstream, err := ga.backend.Upstream(); err == nil && upstream.Name != "" {
+		fmt.Fprintf(ga.out, "🔗 %s: %s\n", upstream.Name, formatAheadBehind(upstream))
+	}
+	ga.addToHistory(fmt.Sprintf("Fetch: %s", label))
+	return nil
+}
+
+func (ga *GitAssistant) pullCurrentBranch(remote string, rebase bool) (err error) {
+	defer ga.journal("pull", remote, optionalFlag(rebase, "--rebase"))(&err)
+	upstream, err := ga.backend.Upstream()
+	if err != nil {
+		return err
+	}
+	
+	// Sans branche suivie, tirer la branche du même nom
+	branch := ""
+	if upstream.Name == "" || remote != "" {
+		if remote, err = ga.defaultRemote(remote); err != nil {
+			return err
+		}
+		branch = ga.getCurrentBranch()
+	}
+	
+	mode := tr("fusion")
+	if rebase {
+		mode = "rebase"
+	}
+	fmt.Fprintf(ga.out, tr("⬇️ Pull (%s)...\n"), mode)
+	if err := ga.backend.Pull(remote, branch, rebase); err != nil {
+		return err
+	}
+	fmt.Fprintln(ga.out, tr("✅ Branche à jour!"))
+	ga.addToHistory(fmt.Sprintf("Pull (%s)", mode))
+	return nil
+}
+
+// Pousse la branche courante; les branches de travail sans branche suivie
+// reçoivent automatiquement --set-upstream
+func (ga *GitAssistant) pushCurrentBranch(remote string, setUpstream bool) (err error) {
+	defer ga.journal("push", remote, optionalFlag(setUpstream, "--set-upstream"))(&err)
+	branch := ga.getCurrentBranch()
+	if branch == "" {
+		return errors.New(tr("HEAD détachée: aucune branche à pousser"))
+	}
+	upstream, err := ga.backend.Upstream()
+	if err != nil {
+		return err
+	}
+	
+	if upstream.Name == "" && ga.isWorkBranch(branch) {
+		setUpstream = true
+	}
+	if upstream.Name == "" || setUpstream || remote != "" {
+		if remote, err = ga.defaultRemote(remote); err != nil {
+			return err
+		}
+	} else {
+		branch = ""
+	}
+	
+	fmt.Fprintf(ga.out, tr("⬆️ Push de %s...\n"), ga.getCurrentBranch())
+	if err := ga.backend.Push(remote, branch, setUpstream); err != nil {
+		return err
+	}
+	if setUpstream {
+		fmt.Fprintf(ga.out, tr("🔗 Branche suivie: %s/%s\n"), remote, branch)
+	}
+	fmt.Fprintln(ga.out, tr("✅ Push effectué!"))
+	ga.addToHistory(fmt.Sprintf("Push: %s", ga.getCurrentBranch()))
+	return nil
+}
+
+func (ga *GitAssistant) switchBranch(name string) (err error) {
+	if name == "" {
+		name = ga.pick(tr("🔄 Nom de la branche: "), ga.branchItems(false))
+	}
+	
+	if name == "" {
+		return errors.New(tr("nom de branche requis"))
+	}
+	
+	defer ga.journal("checkout", name)(&err)
+	fmt.Fprintf(ga.out, tr("🔄 Changement vers la branche: %s\n"), name)
+	err = ga.checkoutWithAutoStash(name, func() error { return ga.backend.Checkout(name) })
+	if err != nil && !errors.Is(err, errStashKept) {
+		return fmt.Errorf(tr("erreur lors du changement de branche: %v"), err)
+	}
+	fmt.Fprintln(ga.out, tr("✅ Branche changée!"))
+	ga.addToHistory(fmt.Sprintf(tr("Changement vers: %s"), name))
+	return err
+}
+
+func (ga *GitAssistant) printRecentCommits(limit int) error {
+	commits, err := ga.backend.Log(limit)
+	if err != nil {
+		return err
+	}
+	ga.printOneline(commits)
+	return nil
+}
+
+func (ga *GitAssistant) resetToCommit() (err error) {
+	fmt.Fprintln(ga.out, tr("📜 Historique récent:"))
+	if err := ga.printRecentCommits(10); err != nil {
+		return err
+	}
+	
+	fmt.Fprintln(ga.out, tr("\n🔄 Types de reset:"))
+	fmt.Fprintln(ga.out, tr("1. 🟢 SOFT - Garde les changements dans le staging"))
+	fmt.Fprintln(ga.out, tr("2. 🟡 MIXED - Garde les changements mais pas dans le staging"))
+	fmt.Fprintln(ga.out, tr("3. 🔴 HARD - Supprime TOUS les changements"))
+	fmt.Fprintf(ga.out, tr("\nType (1-3, défaut %s): "), strings.ToUpper(ga.config.ResetMode))
+	
+	resetType := ga.getUserInput()
+	var resetFlag string
+	
+	switch resetType {
+	case "1":
+		resetFlag = "--soft"
+	case "2":
+		resetFlag = "--mixed"
+	case "3":
+		resetFlag = "--hard"
+	default:
+		resetFlag = "--" + ga.config.ResetMode
+	}
+	
+	commitHash := ga.pick(tr("🎯 Hash du commit (ou HEAD~n): "), ga.commitItems())
+	
+	if commitHash == "" {
+		return errors.New(tr("hash requis"))
+	}
+	
+	defer ga.journal("reset", resetFlag, commitHash)(&err)
+	if err := ga.recordBackup("reset"); err != nil {
+		return err
+	}
+	err = ga.backend.Reset(strings.TrimPrefix(resetFlag, "--"), commitHash)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Fprintln(ga.out, tr("✅ Reset effectué!"))
+	ga.addToHistory(fmt.Sprintf(tr("Reset %s vers %s"), resetFlag, commitHash))
+	return nil
+}
+
+func (ga *GitAssistant) createBranchFromCommit() (err error) {
+	fmt.Fprintln(ga.out, tr("📜 Historique récent:"))
+	if err := ga.printRecentCommits(10); err != nil {
+		return err
+	}
+	
+	commitHash := ga.pick(tr("\n🎯 Hash du commit: "), ga.commitItems())
+	
+	fmt.Fprint(ga.out, tr("🌱 Nom de la branche: "))
+	branchName := ga.getUserInput()
+	
+	if commitHash == "" || branchName == "" {
+		return errors.New(tr("hash et nom requis"))
+	}
+	
+	defer ga.journal("branch.create", branchName, commitHash)(&err)
+	err = ga.backend.CreateBranch(branchName, commitHash)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Fprintln(ga.out, tr("✅ Branche créée et activée!"))
+	ga.addToHistory(fmt.Sprintf(tr("Branche %s depuis %s"), branchName, commitHash))
+	return nil
+}
+
+// Les commits proposés sont ceux de la branche source absents de la branche courante
+func (ga *GitAssistant) cherryPickCommits() error {
+	source := ga.pick(tr("🍒 Branche source: "), ga.branchItems(false))
+	if source == "" {
+		return errors.New(tr("nom requis"))
+	}
+	commits, err := ga.backend.LogRange("HEAD", source)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		fmt.Fprintf(ga.out, tr("ℹ️ Tous les commits de '%s' sont déjà dans la branche courante\n"), source)
+		return nil
+	}
+	// Liste limitée comme l'historique (log.depth); les plus anciens restent choisissables par hash
+	shown := commits
+	if len(shown) > ga.config.LogDepth {
+		shown = shown[:ga.config.LogDepth]
+	}
+	for i, line := range formatLogLines(shown) {
+		fmt.Fprintf(ga.out, "%2d. %s\n", i+1, line)
+	}
+	if hidden := len(commits) - len(shown); hidden > 0 {
+		fmt.Fprintf(ga.out, tr("    … %d commit(s) plus ancien(s) non affiché(s): indiquez leur hash\n"), hidden)
+	}
+	
+	fmt.Fprint(ga.out, cyan(tr("\nCommits à appliquer (n° ou hash, séparés par des espaces): ")))
+	selected := make(map[string]bool)
+	var others []string
+	for _, field := range strings.Fields(ga.getUserInput()) {
+		if idx, err := strconv.Atoi(field); err == nil && idx >= 1 && idx <= len(shown) {
+			selected[shown[idx-1].Hash] = true
+		} else if hash := matchCommitHash(commits, field); hash != "" {
+			selected[hash] = true
+		} else {
+			others = append(others, field)
+		}
+	}
+	// Git les applique dans l'ordre donné: ceux de la liste du plus ancien au plus
+	// récent, puis les autres références telles que saisies
+	var hashes []string
+	for i := len(commits) - 1; i >= 0; i-- {
+		if selected[commits[i].Hash] {
+			hashes = append(hashes, commits[i].Hash)
+		}
+	}
+	hashes = append(hashes, others...)
+	if len(hashes) == 0 {
+		return errors.New(tr("aucun commit choisi"))
+	}
+	
+	recordOrigin := ga.confirm(tr("📝 Noter la provenance dans le message (-x)?"), false)
+	return ga.cherryPick(hashes, recordOrigin)
+}
+
+// Hash complet du seul commit de la liste qui commence par prefix (4 caractères au moins)
+func matchCommitHash(commits []CommitInfo, prefix string) string {
+	prefix = strings.ToLower(prefix)
+	if len(prefix) < 4 {
+		return ""
+	}
+	match := ""
+	for _, commit := range commits {
+		if strings.HasPrefix(commit.Hash, prefix) {
+			if match != "" {
+				return ""
+			}
+			match = commit.Hash
+		}
+	}
+	return match
+}
+
+func (ga *GitAssistant) cherryPick(hashes []string, recordOrigin bool) (err error) {
+	defer ga.journal("cherry-pick", append([]string{optionalFlag(recordOrigin, "-x")}, hashes...)...)(&err)
+	if err := ga.recordBackup("cherry-pick"); err != nil {
+		return err
+	}
+	err = ga.backend.CherryPick(hashes, recordOrigin)
+	if err = ga.handleStop(opCherryPick, err); err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(ga.out, tr("✅ %d commit(s) appliqué(s) sur '%s'!\n"), len(hashes), ga.getCurrentBranch())
+	ga.addToHistory(fmt.Sprintf(tr("Cherry-pick: %s"), strings.Join(hashes, " ")))
+	return nil
+}
+
+// Pour un commit de fusion, le parent choisi est la version de référence: ce que
+// la fusion a apporté par rapport à lui est annulé
+func (ga *GitAssistant) revertCommit() error {
+	hash := ga.pick(tr("↩️ Commit à annuler: "), ga.commitItems())
+	if hash == "" {
+		return errors.New(tr("hash requis"))
+	}
+	commit, err := ga.backend.ReadCommit(hash)
+	if err != nil {
+		return err
+	}
+	
+	mainline := 0
+	if len(commit.Parents) > 1 {
+		fmt.Fprintf(ga.out, tr("🔀 %s est un commit de fusion. Parents:\n"), commit.ShortHash)
+		for i, parent := range commit.Parents {
+			subject := ""
+			if info, err := ga.backend.ReadCommit(parent); err == nil {
+				subject = info.Subject
+			}
+			fmt.Fprintf(ga.out, "%d. %s %s\n", i+1, shortHash(parent), subject)
+		}
+		fmt.Fprintf(ga.out, tr("Parent principal (1-%d, défaut 1, la branche qui a reçu la fusion): "), len(commit.Parents))
+		mainline = 1
+		if choice, err := strconv.Atoi(ga.getUserInput()); err == nil && choice >= 1 && choice <= len(commit.Parents) {
+			mainline = choice
+		}
+	}
+	if !ga.confirm(fmt.Sprintf(tr("↩️ Annuler « %s » par un nouveau commit?"), commit.Subject), true) {
+		fmt.Fprintln(ga.out, tr("❌ Revert annulé"))
+		return nil
+	}
+	return ga.revert(commit.Hash, mainline)
+}
+
+func (ga *GitAssistant) revert(hash string, mainline int) (err error) {
+	args := []string{hash}
+	if mainline > 0 {
+		args = append(args, "-m", strconv.Itoa(mainline))
+	}
+	defer ga.journal("revert", args...)(&err)
+	if err := ga.recordBackup("revert"); err != nil {
+		return err
+	}
+	err = ga.backend.Revert(hash, mainline)
+	if err = ga.handleStop(opRevert, err); err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(ga.out, tr("✅ Commit %s annulé!\n"), shortHash(hash))
+	ga.addToHistory(fmt.Sprintf(tr("Revert: %s"), shortHash(hash)))
+	return nil
+}
+
+// Annulation: avant chaque action destructrice (reset, suppression de branche, fusion,
+// rebase, cherry-pick, revert), HEAD, les pointes de branches et les changements
+// locaux sont gardés sous refs/gitctrl/backup/<horodatage>-<action>/, ce qui les
+// protège aussi du ramasse-miettes.
+
+const backupRefPrefix = "refs/gitctrl/backup/"
+
+// Instantanés conservés; les plus anciens sont supprimés
+const maxBackups = 20
+
+type backupSnapshot struct {
+	ID       string // <horodatage en ns>-<action>
+	Action   string // reset, delete, merge, rebase, cherry-pick, revert ou undo
+	Time     time.Time
+	Head     string
+	Branch   string            // branche active, vide si HEAD détachée
+	Branches map[string]string // branche → commit
+	Worktree string            // commit de stash des changements locaux, vide si propre
+}
+
+var backupActionLabels = map[string]string{
+	"reset":       "Reset",
+	"delete":      "Suppression de branche",
+	"merge":       "Fusion",
+	"rebase":      "Rebase",
+	"cherry-pick": "Cherry-pick",
+	"revert":      "Revert",
+	"undo":        "Annulation",
+}
+
+func (b backupSnapshot) describe() string {
+	where := b.Branch
+	if where == "" {
+		where = tr("HEAD détachée")
+	}
+	short := b.Head
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	text := fmt.Sprintf(tr("%s · %s @ %s · %d branche(s)"), tr(backupActionLabels[b.Action]), where, short, len(b.Branches))
+	if b.Worktree != "" {
+		text += tr(" · changements locaux")
+	}
+	return text
+}
+
+// Enregistre l'état actuel avant une action destructrice
+func (ga *GitAssistant) recordBackup(action string) error {
+	head := ga.headHash()
+	if head == "" {
+		// Dépôt sans commit: rien à perdre
+		return nil
+	}
+	branches, err := ga.backend.Refs("refs/heads/")
+	if err != nil {
+		return err
+	}
+	current, _ := ga.backend.CurrentBranch()
+	
+	refs := map[string]string{"head": head}
+	if current != "" {
+		refs["branch/"+current] = head
+	}
+	for name, hash := range branches {
+		refs["heads/"+strings.TrimPrefix(name, "refs/heads/")] = hash
+	}
+	worktree, snapshotErr := ga.backend.SnapshotWorktree()
+	if snapshotErr != nil {
+		// Sans instantané, l'annulation ne rendrait pas les changements locaux
+		fmt.Fprintf(ga.out, red(tr("⚠️ Changements locaux non sauvegardés: %v\n")), snapshotErr)
+		if !ga.confirm(tr("⚠️ Continuer sans pouvoir les restaurer?"), false) {
+			return errOperationAborted
+		}
+	}
+	if worktree != "" {
+		refs["worktree"] = worktree
+	}
+	
+	prefix := fmt.Sprintf("%s%d-%s/", backupRefPrefix, time.Now().UnixNano(), action)
+	for name, hash := range refs {
+		if err := ga.backend.UpdateRef(prefix+name, hash); err != nil {
+			return err
+		}
+	}
+	if snapshotErr != nil {
+		fmt.Fprintln(ga.out, tr("💾 Branches sauvegardées: « Annuler la dernière action » ne restaurera pas les changements locaux"))
+	} else {
+		fmt.Fprintln(ga.out, tr("💾 État sauvegardé: « Annuler la dernière action » le restaure"))
+	}
+	return ga.pruneBackups()
+}
+
+// Instantanés du plus récent au plus ancien
+func (ga *GitAssistant) loadBackups() ([]backupSnapshot, error) {
+	refs, err := ga.backend.Refs(backupRefPrefix)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*backupSnapshot)
+	for name, hash := range refs {
+		id, rest, ok := strings.Cut(strings.TrimPrefix(name, backupRefPrefix), "/")
+		if !ok {
+			continue
+		}
+		b, exists := byID[id]
+		if !exists {
+			stamp, action, _ := strings.Cut(id, "-")
+			nanos, err := strconv.ParseInt(stamp, 10, 64)
+			if err != nil {
+				continue
+			}
+			b = &backupSnapshot{ID: id, Action: action, Time: time.Unix(0, nanos), Branches: make(map[string]string)}
+			byID[id] = b
+		}
+		switch {
+		case rest == "head":
+			b.Head = hash
+		case rest == "worktree":
+			b.Worktree = hash
+		case strings.HasPrefix(rest, "branch/"):
+			b.Branch = strings.TrimPrefix(rest, "branch/")
+		case strings.HasPrefix(rest, "heads/"):
+			b.Branches[strings.TrimPrefix(rest, "heads/")] = hash
+		}
+	}
+	
+	backups := make([]backupSnapshot, 0, len(byID))
+	for _, b := range byID {
+		if b.Head != "" {
+			backups = append(backups, *b)
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Time.After(backups[j].Time) })
+	return backups, nil
+}
+
+func (ga *GitAssistant) deleteBackup(b backupSnapshot) error {
+	refs, err := ga.backend.Refs(backupRefPrefix + b.ID + "/")
+	if err != nil {
+		return err
+	}
+	for name := range refs {
+		if err := ga.backend.DeleteRef(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ga *GitAssistant) pruneBackups() error {
+	backups, err := ga.loadBackups()
+	if err != nil {
+		return err
+	}
+	for i := maxBackups; i < len(backups); i++ {
+		if err := ga.deleteBackup(backups[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remet les branches, HEAD et les changements locaux dans l'état de l'instantané.
+// L'état actuel est sauvegardé à son tour: annuler deux fois rétablit l'action.
+func (ga *GitAssistant) restoreBackup(b backupSnapshot) error {
+	if err := ga.recordBackup("undo"); err != nil {
+		return err
+	}
+	for name, hash := range b.Branches {
+		if err := ga.backend.UpdateRef("refs/heads/"+name, hash); err != nil {
+			return err
+		}
+	}
+	// Les changements actuels viennent d'être sauvegardés: l'arbre peut être nettoyé
+	if err := ga.backend.Reset("hard", "HEAD"); err != nil {
+		return err
+	}
+	target := b.Head
+	if b.Branch != "" {
+		target = b.Branch
+	}
+	if err := ga.backend.Checkout(target); err != nil {
+		return err
+	}
+	if err := ga.backend.Reset("hard", b.Head); err != nil {
+		return err
+	}
+	if b.Worktree != "" {
+		if err := ga.backend.RestoreSnapshot(b.Worktree); err != nil {
+			return err
+		}
+	}
+	return ga.deleteBackup(b)
+}
+
+func (ga *GitAssistant) undoMenu() error {
+	backups, err := ga.loadBackups()
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		fmt.Fprintln(ga.out, tr("ℹ️ Aucun instantané à restaurer"))
+		return nil
+	}
+	
+	fmt.Fprintf(ga.out, "↩️ === %s ===\n", bold(tr("ANNULER LA DERNIÈRE ACTION")))
+	ga.printBackups(backups)
+	fmt.Fprint(ga.out, cyan(tr("\nInstantané à restaurer (Entrée pour le plus récent): ")))
+	
+	choice := 1
+	if input := ga.getUserInput(); input != "" {
+		choice, err = strconv.Atoi(input)
+		if err != nil || choice < 1 || choice > len(backups) {
+			fmt.Fprintln(ga.out, red(tr("❌ Choix invalide")))
+			return nil
+		}
+	}
+	b := backups[choice-1]
+	if !ga.confirm(fmt.Sprintf(tr("↩️ Restaurer l'état d'avant « %s » (%s)?"), tr(backupActionLabels[b.Action]), relativeTime(b.Time)), true) {
+		fmt.Fprintln(ga.out, tr("❌ Annulation abandonnée"))
+		return nil
+	}
+	return ga.undo(b)
+}
+
+func (ga *GitAssistant) printBackups(backups []backupSnapshot) {
+	for i, b := range backups {
+		fmt.Fprintf(ga.out, "%2d. %-20s %s\n", i+1, relativeTime(b.Time), b.describe())
+	}
+}
+
+func (ga *GitAssistant) undo(b backupSnapshot) (err error) {
+	defer ga.journal("undo", b.ID)(&err)
+	if err := ga.restoreBackup(b); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ État restauré: %s\n"), b.describe())
+	ga.addToHistory(fmt.Sprintf(tr("Annulation: %s"), tr(backupActionLabels[b.Action])))
+	return nil
+}
+
+// Journal des actions: une ligne JSON par action dans .git/gitctrl/journal.jsonl.
+// Il survit aux sessions et au changement de répertoire, et garde la trace de ce qu'ont
+// fait le menu, la ligne de commande et le mode plein écran.
+
+const (
+	originMenu = "menu"
+	originCLI  = "cli"
+	originTUI  = "tui"
+)
+
+const (
+	journalOK    = "ok"
+	journalError = "error"
+)
+
+// Entrées affichées par le menu; la ligne de commande et l'export n'ont pas de limite
+const journalDisplayLimit = 50
+
+// Branche, HEAD et références au moment d'une action. Dans une entrée, Refs ne contient
+// que les références créées, déplacées ou supprimées par l'action.
+type JournalState struct {
+	Branch string            `json:"branch"`
+	Head   string            `json:"head"`
+	Refs   map[string]string `json:"refs"`
+}
+
+type JournalEntry struct {
+	Time    time.Time    `json:"time"`
+	Action  string       `json:"action"`
+	Args    []string     `json:"args"`
+	Summary string       `json:"summary,omitempty"`
+	Origin  string       `json:"origin"`
+	Before  JournalState `json:"before"`
+	After   JournalState `json:"after"`
+	Result  string       `json:"result"`
+	Error   string       `json:"error,omitempty"`
+}
+
+type JournalReport struct {
+	Schema  string         `json:"schema"`
+	Entries []JournalEntry `json:"entries"`
+}
+
+// Ouvre une entrée du journal; la fonction retournée, appelée en defer avec l'erreur
+// de l'action, l'écrit. Une action lancée par une autre (pull et push pendant sync)
+// n'a pas d'entrée à elle.
+func (ga *GitAssistant) journal(action string, args ...string) func(*error) {
+	ga.journalDepth++
+	if ga.journalDepth > 1 {
+		return func(*error) { ga.journalDepth-- }
+	}
+	
+	ga.journalNote = ""
+	entry := JournalEntry{Time: time.Now(), Action: action, Args: []string{}, Origin: ga.origin, Before: ga.journalState()}
+	for _, arg := range args {
+		if arg != "" {
+			entry.Args = append(entry.Args, arg)
+		}
+	}
+	return func(errp *error) {
+		ga.journalDepth--
+		entry.After = ga.journalState()
+		entry.Before.Refs, entry.After.Refs = changedRefs(entry.Before.Refs, entry.After.Refs)
+		entry.Result = journalOK
+		if *errp != nil {
+			entry.Result, entry.Error = journalError, (*errp).Error()
+		} else {
+			entry.Summary = ga.journalNote
+		}
+		if err := ga.appendJournal(entry); err != nil {
+			fmt.Fprintf(ga.out, red(tr("⚠️ Journal non enregistré: %v\n")), err)
+		}
+	}
+}
+
+// Option ajoutée aux arguments journalisés seulement si elle est active
+func optionalFlag(set bool, flag string) string {
+	if set {
+		return flag
+	}
+	return ""
+}
+
+// Les instantanés d'annulation ne sont pas des références de l'utilisateur
+func (ga *GitAssistant) journalState() JournalState {
+	state := JournalState{Head: ga.headHash(), Refs: map[string]string{}}
+	state.Branch, _ = ga.backend.CurrentBranch()
+	refs, _ := ga.backend.Refs("refs/")
+	for name, hash := range refs {
+		if !strings.HasPrefix(name, backupRefPrefix) {
+			state.Refs[name] = hash
+		}
+	}
+	return state
+}
+
+func changedRefs(before, after map[string]string) (map[string]string, map[string]string) {
+	oldRefs, newRefs := map[string]string{}, map[string]string{}
+	for name, hash := range before {
+		if after[name] != hash {
+			oldRefs[name] = hash
+		}
+	}
+	for name, hash := range after {
+		if before[name] != hash {
+			newRefs[name] = hash
+		}
+	}
+	return oldRefs, newRefs
+}
+
+// Dans le répertoire Git commun: les worktrees d'un dépôt partagent le même journal
+func (ga *GitAssistant) journalPath() (string, error) {
+	_, commonDir, err := gitDirs(findRepoRoot(ga.workingDir))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, "gitctrl", "journal.jsonl"), nil
+}
+
+func (ga *GitAssistant) appendJournal(entry JournalEntry) error {
+	// Initialisation ratée: pas de dépôt où écrire
+	if !ga.isGitRepo() {
+		return nil
+	}
+	path, err := ga.journalPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if err := encodeJSON(file, entry, false); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Entrées dans l'ordre chronologique; une ligne illisible (écriture interrompue) est ignorée
+func (ga *GitAssistant) loadJournal() ([]JournalEntry, error) {
+	path, err := ga.journalPath()
+	if err != nil {
+		return nil, err
+	}
+	entries := []JournalEntry{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		var entry JournalEntry
+		if strings.TrimSpace(line) == "" || json.Unmarshal([]byte(line), &entry) != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+type journalFilter struct {
+	Action string
+	Since  time.Time
+}
+
+// Un type retient aussi ses sous-types: "branch" garde branch.create et branch.delete
+func (f journalFilter) match(entry JournalEntry) bool {
+	if f.Action != "" && entry.Action != f.Action && !strings.HasPrefix(entry.Action, f.Action+".") {
+		return false
+	}
+	return f.Since.IsZero() || !entry.Time.Before(f.Since)
+}
+
+func filterJournal(entries []JournalEntry, filter journalFilter) []JournalEntry {
+	selected := []JournalEntry{}
+	for _, entry := range entries {
+		if filter.match(entry) {
+			selected = append(selected, entry)
+		}
+	}
+	return selected
+}
+
+var journalDurationUnits = map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+
+// Date de début du filtre: AAAA-MM-JJ (minuit, heure locale), RFC 3339,
+// ou durée écoulée depuis maintenant (90m, 12h, 7d, 2w)
+func parseJournalDate(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if len(value) > 1 {
+		if unit, ok := journalDurationUnits[value[len(value)-1]]; ok {
+			if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n >= 0 {
+				return now.Add(-time.Duration(n) * unit), nil
+			}
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf(tr("date invalide: %s (AAAA-MM-JJ, RFC 3339 ou durée comme 7d)"), value)
+}
+
+// Types présents dans le journal avec leur nombre d'entrées: "commit (3), merge (1)"
+func journalActionCounts(entries []JournalEntry) string {
+	counts := map[string]int{}
+	for _, entry := range entries {
+		counts[entry.Action]++
+	}
+	actions := make([]string, 0, len(counts))
+	for action := range counts {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for i, action := range actions {
+		actions[i] = fmt.Sprintf("%s (%d)", action, counts[action])
+	}
+	return strings.Join(actions, ", ")
+}
+
+// Branche et commit de HEAD: "master @ 1a2b3c4"
+func (s JournalState) describe() string {
+	head := s.Head
+	if len(head) > 7 {
+		head = head[:7]
+	}
+	switch {
+	case s.Branch == "":
+		return head
+	case head == "":
+		return s.Branch
+	}
+	return s.Branch + " @ " + head
+}
+
+// Du plus récent au plus ancien
+func (ga *GitAssistant) printJournal(entries []JournalEntry) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		icon := green("✅")
+		if entry.Result == journalError {
+			icon = red("❌")
+		}
+		text := entry.Summary
+		if text == "" {
+			text = strings.Join(entry.Args, " ")
+		}
+		move := ""
+		if before, after := entry.Before.describe(), entry.After.describe(); before != after {
+			move = fmt.Sprintf(" · %s → %s", before, after)
+		}
+		origin := ""
+		if entry.Origin != originMenu {
+			origin = cyan(" [" + entry.Origin + "]")
+		}
+		fmt.Fprintf(ga.out, "%s %s %-14s %s%s%s\n", entry.Time.Local().Format("2006-01-02 15:04"), icon, entry.Action, text, move, origin)
+		if entry.Error != "" {
+			fmt.Fprintf(ga.out, "                    %s\n", red(strings.Join(strings.Fields(entry.Error), " ")))
+		}
+	}
+}
+
+func (ga *GitAssistant) showHistory() error {
+	entries, err := ga.loadJournal()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(ga.out, tr("📜 Aucune action enregistrée dans ce dépôt"))
+		return nil
+	}
+	
+	fmt.Fprintf(ga.out, "🕘 === %s ===\n", bold(tr("JOURNAL DES ACTIONS")))
+	fmt.Fprintf(ga.out, tr("Types: %s\n"), journalActionCounts(entries))
+	fmt.Fprint(ga.out, cyan(tr("Type d'action (Entrée pour tous): ")))
+	filter := journalFilter{Action: ga.getUserInput()}
+	fmt.Fprint(ga.out, cyan(tr("Depuis (AAAA-MM-JJ ou durée comme 7d, Entrée pour tout): ")))
+	if since := ga.getUserInput(); since != "" {
+		if filter.Since, err = parseJournalDate(since, time.Now()); err != nil {
+			return err
+		}
+	}
+	
+	selected := filterJournal(entries, filter)
+	if len(selected) == 0 {
+		fmt.Fprintln(ga.out, tr("ℹ️ Aucune action ne correspond au filtre"))
+		return nil
+	}
+	fmt.Fprintln(ga.out)
+	shown := selected
+	if len(shown) > journalDisplayLimit {
+		shown = shown[len(shown)-journalDisplayLimit:]
+	}
+	ga.printJournal(shown)
+	if hidden := len(selected) - len(shown); hidden > 0 {
+		fmt.Fprintf(ga.out, tr("… %d entrée(s) plus ancienne(s): filtrez ou exportez pour tout voir\n"), hidden)
+	}
+	
+	fmt.Fprint(ga.out, cyan(tr("\n📤 Exporter en JSON (chemin du fichier, Entrée pour ignorer): ")))
+	path := ga.getUserInput()
+	if path == "" {
+		return nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(ga.workingDir, path)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := encodeJSON(file, JournalReport{Schema: "gitctrl.journal/v1", Entries: selected}, true); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ %d entrée(s) exportée(s) vers %s\n"), len(selected), path)
+	return nil
+}
+
+// Stash: changements mis de côté, réappliqués ou transformés en branche.
+// Le changement de branche s'en sert aussi quand des changements locaux le bloquent.
+
+// Réponse au changement de branche bloqué par des changements locaux
+const (
+	autoStashAsk    = ""       // menu: demander
+	autoStashAlways = "always" // --autostash, mode plein écran
+	autoStashNever  = "never"  // ligne de commande sans --autostash
+)
+
+// Change de branche (ou en crée une) avec checkout; si des changements locaux
+// seraient écrasés, les met de côté, recommence puis les réapplique
+func (ga *GitAssistant) checkoutWithAutoStash(target string, checkout func() error) error {
+	err := checkout()
+	if !errors.Is(err, errLocalChanges) {
+		return err
+	}
+	switch ga.autoStash {
+	case autoStashNever:
+		return fmt.Errorf(tr("%v (relancez avec --autostash pour les mettre de côté)"), err)
+	case autoStashAsk:
+		fmt.Fprintln(ga.out, red(tr("⚠️ Des changements locaux seraient écrasés par le changement de branche")))
+		if !ga.confirm(tr("📦 Les mettre de côté, changer de branche puis les réappliquer?"), true) {
+			return err
+		}
+	}
+	
+	// stash@{0} doit être l'entrée créée ici, pas une plus ancienne
+	before, _ := ga.backend.Refs("refs/stash")
+	if err := ga.backend.StashSave(fmt.Sprintf(tr("gitctrl: avant le passage à %s"), target), true); err != nil {
+		return err
+	}
+	if after, _ := ga.backend.Refs("refs/stash"); after["refs/stash"] == before["refs/stash"] {
+		return err
+	}
+	fmt.Fprintln(ga.out, tr("📦 Changements mis de côté dans stash@{0}"))
+	if err := checkout(); err != nil {
+		// Rien n'a changé: remettre les changements en place
+		if popErr := ga.backend.StashApply("stash@{0}", true); popErr != nil {
+			return fmt.Errorf(tr("%v (changements conservés dans stash@{0}: %v)"), err, popErr)
+		}
+		return err
+	}
+	if err := ga.backend.StashApply("stash@{0}", true); err != nil {
+		// La branche a bien changé: seul le retour des changements reste à faire
+		return fmt.Errorf(tr("%w: ils restent dans %s (%v)"), errStashKept, "stash@{0}", err)
+	}
+	fmt.Fprintln(ga.out, tr("📥 Changements réappliqués"))
+	return nil
+}
+
+// Rendue par checkoutWithAutoStash quand la branche a changé mais pas le stash pop
+var errStashKept error = localizedError("branche changée, mais les changements mis de côté n'ont pas pu être réappliqués")
+
+// Entrée désignée par son numéro ("2") ou sa référence ("stash@{2}")
+func stashRef(input string) string {
+	if _, err := strconv.Atoi(input); err == nil {
+		return "stash@{" + input + "}"
+	}
+	return input
+}
+
+func describeStash(stash StashInfo) string {
+	return fmt.Sprintf("%-10s %-20s %s: %s", stash.Ref, relativeTime(stash.Date), stash.Branch, stash.Message)
+}
+
+func (ga *GitAssistant) stashItems(stashes []StashInfo) []pickerItem {
+	items := make([]pickerItem, 0, len(stashes))
+	for _, stash := range stashes {
+		ref := stash.Ref
+		items = append(items, pickerItem{Key: ref, Label: describeStash(stash), Preview: func() string {
+			diff, err := ga.backend.StashShow(ref)
+			if err != nil {
+				return err.Error()
+			}
+			return diff
+		}})
+	}
+	return items
+}
+
+func (ga *GitAssistant) printStashes(stashes []StashInfo) {
+	if len(stashes) == 0 {
+		fmt.Fprintln(ga.out, tr("ℹ️ Aucune entrée mise de côté"))
+	}
+	for _, stash := range stashes {
+		fmt.Fprintln(ga.out, "  "+describeStash(stash))
+	}
+}
+
+func (ga *GitAssistant) stashMenu() error {
+	stashes, err := ga.backend.Stashes()
+	if err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(ga.out, "📦 === %s ===\n", bold("STASH"))
+	ga.printStashes(stashes)
+	fmt.Fprintln(ga.out, tr("\n1. 💾 Mettre de côté les changements"))
+	fmt.Fprintln(ga.out, tr("2. 👀 Voir le diff d'une entrée"))
+	fmt.Fprintln(ga.out, tr("3. 📥 Appliquer une entrée (la garder)"))
+	fmt.Fprintln(ga.out, tr("4. 📤 Réappliquer et retirer une entrée (pop)"))
+	fmt.Fprintln(ga.out, tr("5. 🗑️ Supprimer une entrée"))
+	fmt.Fprintln(ga.out, tr("6. 🌱 Créer une branche depuis une entrée"))
+	fmt.Fprint(ga.out, cyan(tr("\nChoisissez (1-6): ")))
+	
+	choice := ga.getUserInput()
+	switch choice {
+	case "1":
+		fmt.Fprint(ga.out, tr("💬 Message (Entrée pour le message par défaut): "))
+		message := ga.getUserInput()
+		untracked := ga.confirm(tr("Inclure les fichiers non suivis?"), false)
+		return ga.saveStash(message, untracked)
+	case "2", "3", "4", "5", "6":
+	default:
+		fmt.Fprintln(ga.out, red(tr("❌ Choix invalide")))
+		return nil
+	}
+	if len(stashes) == 0 {
+		return nil
+	}
+	
+	ref := stashRef(ga.pick(tr("📦 Entrée (n° ou stash@{n}): "), ga.stashItems(stashes)))
+	if ref == "" {
+		return errors.New(tr("entrée requise"))
+	}
+	switch choice {
+	case "2":
+		diff, err := ga.backend.StashShow(ref)
+		if err != nil {
+			return err
+		}
+		ga.displayColoredDiff(diff)
+	case "3":
+		return ga.applyStash(ref, false)
+	case "4":
+		return ga.applyStash(ref, true)
+	case "5":
+		if !ga.confirm(fmt.Sprintf(tr("⚠️ Supprimer %s? Ses changements seront perdus"), ref), false) {
+			fmt.Fprintln(ga.out, tr("❌ Suppression annulée"))
+			return nil
+		}
+		return ga.dropStash(ref)
+	case "6":
+		fmt.Fprint(ga.out, tr("🌱 Nom de la branche: "))
+		name := ga.getUserInput()
+		if name == "" {
+			return errors.New(tr("nom requis"))
+		}
+		return ga.branchFromStash(name, ref)
+	}
+	return nil
+}
+
+// Met de côté les changements suivis, et les fichiers non suivis si demandé
+func (ga *GitAssistant) saveStash(message string, includeUntracked bool) (err error) {
+	status, err := ga.getStatus()
+	if err != nil {
+		return err
+	}
+	changes := 0
+	for _, entry := range status {
+		if includeUntracked || entry.Index != '?' {
+			changes++
+		}
+	}
+	if changes == 0 {
+		return errors.New(tr("aucun changement à mettre de côté"))
+	}
+	
+	defer ga.journal("stash.save", message, optionalFlag(includeUntracked, "--include-untracked"))(&err)
+	if err = ga.backend.StashSave(message, includeUntracked); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ %d changement(s) mis de côté dans stash@{0}\n"), changes)
+	ga.addToHistory(fmt.Sprintf(tr("Stash: %d changement(s)"), changes))
+	return nil
+}
+
+// Réapplique une entrée; pop la retire ensuite, sauf en cas de conflit
+func (ga *GitAssistant) applyStash(ref string, pop bool) (err error) {
+	action := "stash.apply"
+	if pop {
+		action = "stash.pop"
+	}
+	defer ga.journal(action, ref)(&err)
+	if err = ga.backend.StashApply(ref, pop); err != nil {
+		if status, statusErr := ga.backend.Status(); statusErr == nil && len(categorizeChanges(status.Entries).Conflicted) > 0 {
+			fmt.Fprintf(ga.out, red(tr("⚠️ Conflits en réappliquant %s: résolvez-les puis indexez les fichiers; l'entrée est conservée\n")), ref)
+		}
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ %s réappliquée\n"), ref)
+	if pop {
+		fmt.Fprintf(ga.out, tr("🗑️ %s retirée de la pile\n"), ref)
+	}
+	ga.addToHistory(fmt.Sprintf("%s: %s", action, ref))
+	return nil
+}
+
+func (ga *GitAssistant) dropStash(ref string) (err error) {
+	hash := ""
+	if stashes, err := ga.backend.Stashes(); err == nil {
+		for _, stash := range stashes {
+			if stash.Ref == ref {
+				hash = stash.Hash
+			}
+		}
+	}
+	
+	defer ga.journal("stash.drop", ref, hash)(&err)
+	if err = ga.backend.StashDrop(ref); err != nil {
+		return err
+	}
+	// Le hash permet encore de récupérer l'entrée (git stash apply <hash>)
+	fmt.Fprintf(ga.out, tr("✅ %s supprimée (%s)\n"), ref, hash)
+	ga.addToHistory(fmt.Sprintf(tr("Stash supprimé: %s"), ref))
+	return nil
+}
+
+// Crée une branche sur le commit d'origine de l'entrée et y réapplique ses changements
+func (ga *GitAssistant) branchFromStash(name, ref string) (err error) {
+	defer ga.journal("stash.branch", name, ref)(&err)
+	if err = ga.backend.StashBranch(name, ref); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ Branche '%s' créée depuis %s et activée!\n"), name, ref)
+	ga.addToHistory(fmt.Sprintf(tr("Branche %s depuis %s"), name, ref))
+	return nil
+}
+
+// Conflits: fichiers non fusionnés de l'index (étages 1 base, 2 nous, 3 eux), résolus
+// un par un, puis poursuite ou abandon de l'opération interrompue.
+
+// Opérations que Git interrompt sur un conflit; le nom est aussi celui de la commande git
+const (
+	opMerge      = "merge"
+	opRebase     = "rebase"
+	opCherryPick = "cherry-pick"
+	opRevert     = "revert"
+)
+
+var (
+	errOperationAborted = localizedError("opération abandonnée")
+	errConflictsPending = localizedError("conflits non résolus")
+)
+
+// Opération en cours d'après les fichiers d'état de Git, vide s'il n'y en a pas
+// (un stash pop en conflit, par exemple)
+func (ga *GitAssistant) pendingOperation() string {
+	gitDir, _, err := gitDirs(findRepoRoot(ga.workingDir))
+	if err != nil {
+		return ""
+	}
+	markers := []struct{ file, op string }{
+		{"rebase-merge", opRebase},
+		{"rebase-apply", opRebase},
+		{"MERGE_HEAD", opMerge},
+		{"CHERRY_PICK_HEAD", opCherryPick},
+		{"REVERT_HEAD", opRevert},
+	}
+	for _, marker := range markers {
+		if _, err := os.Stat(filepath.Join(gitDir, marker.file)); err == nil {
+			return marker.op
+		}
+	}
+	return ""
+}
+
+func (ga *GitAssistant) conflictedFiles() ([]ConflictedFile, error) {
+	status, err := ga.getStatus()
+	if err != nil {
+		return nil, err
+	}
+	return categorizeChanges(status).Conflicted, nil
+}
+
+func (ga *GitAssistant) printConflicts(conflicts []ConflictedFile) {
+	for i, conflict := range conflicts {
+		fmt.Fprintf(ga.out, "%2d. %s %s (%s)\n", i+1, red(conflict.State), conflict.Path, conflictLabel(conflict.State))
+	}
+}
+
+// Après l'échec d'une opération: sans fichier en conflit, l'erreur est rendue telle quelle;
+// sinon les fichiers sont listés et, dans le menu, la résolution est proposée
+func (ga *GitAssistant) handleConflicts(err error) error {
+	conflicts, statusErr := ga.conflictedFiles()
+	if statusErr != nil || len(conflicts) == 0 {
+		return err
+	}
+	fmt.Fprintf(ga.out, red(tr("⚔️ Conflits dans %d fichier(s):\n")), len(conflicts))
+	ga.printConflicts(conflicts)
+	if ga.origin == originMenu && ga.confirm(tr("⚔️ Résoudre les conflits maintenant?"), true) {
+		return ga.resolveConflicts()
+	}
+	fmt.Fprintln(ga.out, tr("→ Résolvez-les avec « gitctrl conflicts » ou l'entrée 16 du menu"))
+	return errConflictsPending
+}
+
+// Après une opération qui rejoue des commits (rebase, cherry-pick, revert) et qui reste
+// en cours: conflits à résoudre, ou commit à modifier (edit) ou devenu vide, à poursuivre
+// ou à passer. Sans opération en cours, l'erreur est rendue telle quelle.
+func (ga *GitAssistant) handleStop(op string, err error) error {
+	if ga.pendingOperation() != op {
+		return err
+	}
+	if conflicts, _ := ga.conflictedFiles(); len(conflicts) > 0 {
+		return ga.handleConflicts(err)
+	}
+	if err != nil {
+		fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+	}
+	if ga.origin == originMenu {
+		return ga.resolveConflicts()
+	}
+	fmt.Fprintf(ga.out, tr("⏸️ Opération arrêtée (%s): poursuivez avec « gitctrl conflicts continue », skip ou abort\n"), op)
+	return errConflictsPending
+}
+
+// Écran de résolution. Retourne nil quand tout est résolu et l'opération terminée,
+// errOperationAborted si elle a été abandonnée, errConflictsPending si l'utilisateur s'arrête avant.
+func (ga *GitAssistant) resolveConflicts() error {
+	for {
+		op := ga.pendingOperation()
+		conflicts, err := ga.conflictedFiles()
+		if err != nil {
+			return err
+		}
+		
+		fmt.Fprintf(ga.out, "\n⚔️ === %s ===\n", bold(tr("RÉSOLUTION DES CONFLITS")))
+		if op != "" {
+			fmt.Fprintf(ga.out, tr("Opération en cours: %s\n"), op)
+		}
+		// Une fusion se poursuit ou s'abandonne en bloc; les autres opérations rejouent
+		// des commits un par un et peuvent passer celui qui bloque
+		actions := tr("a: abandonner, Entrée: plus tard: ")
+		if op != "" && op != opMerge {
+			actions = tr("s: passer ce commit, ") + actions
+		}
+		if len(conflicts) == 0 {
+			if op == "" {
+				fmt.Fprintln(ga.out, green(tr("✅ Aucun conflit")))
+				return nil
+			}
+			if stopped := ga.rebaseEditStop(); stopped != "" {
+				fmt.Fprintf(ga.out, tr("⏸️ Arrêt pour modifier le commit %s: changez les fichiers et indexez-les (11), ils seront ajoutés au commit\n"), stopped)
+			} else {
+				fmt.Fprintln(ga.out, green(tr("✅ Tous les conflits sont résolus")))
+			}
+			fmt.Fprint(ga.out, cyan(tr("c: continuer, ")+actions))
+		} else {
+			ga.printConflicts(conflicts)
+			if op != "" {
+				fmt.Fprint(ga.out, cyan(tr("\nFichier à résoudre (n°), ")+actions))
+			} else {
+				fmt.Fprint(ga.out, cyan(tr("\nFichier à résoudre (n°), Entrée: plus tard: ")))
+			}
+		}
+		
+		input := ga.getUserInput()
+		switch {
+		case input == "" || ga.inputClosed:
+			return errConflictsPending
+		case input == "a" && op != "":
+			if err := ga.abortOperation(op); err != nil {
+				return err
+			}
+			return errOperationAborted
+		case input == "s" && op != "" && op != opMerge:
+			if err := ga.skipOperation(op); err != nil && !errors.Is(err, errConflictsPending) {
+				fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+			}
+			if ga.pendingOperation() == "" {
+				return nil
+			}
+		case input == "c" && op != "" && len(conflicts) == 0:
+			if err := ga.continueOperation(op); err != nil && !errors.Is(err, errConflictsPending) {
+				// Des changements non indexés après un arrêt "edit", par exemple: on reste ici
+				fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+			}
+			// Un rebase peut s'arrêter de nouveau sur le commit suivant
+			if ga.pendingOperation() == "" {
+				return nil
+			}
+		default:
+			idx, err := strconv.Atoi(input)
+			if err != nil || idx < 1 || idx > len(conflicts) {
+				fmt.Fprintln(ga.out, red(tr("❌ Choix invalide")))
+				continue
+			}
+			if err := ga.resolveConflictFile(conflicts[idx-1]); err != nil {
+				fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+			}
+		}
+	}
+}
+
+// Commit sur lequel un rebase interactif s'est arrêté pour "edit" (hash abrégé),
+// vide s'il s'est arrêté sur un conflit ou s'il n'y a pas de rebase
+func (ga *GitAssistant) rebaseEditStop() string {
+	gitDir, _, err := gitDirs(findRepoRoot(ga.workingDir))
+	if err != nil {
+		return ""
+	}
+	state := filepath.Join(gitDir, "rebase-merge")
+	if _, err := os.Stat(filepath.Join(state, "amend")); err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(state, "stopped-sha"))
+	hash := strings.TrimSpace(string(data))
+	if err != nil || hash == "" {
+		return ""
+	}
+	if len(hash) > 7 {
+		hash = hash[:7]
+	}
+	return hash
+}
+
+// Côtés proposés pour un fichier en conflit
+const (
+	sideOurs   = "ours"
+	sideTheirs = "theirs"
+	sideBoth   = "both"
+)
+
+// Portion d'un fusionné diff3: lignes communes (dans Ours) ou conflit
+type mergeHunk struct {
+	Conflict           bool
+	Ours, Base, Theirs []string
+}
+
+// Découpe la sortie de git merge-file --diff3 (marqueurs <<<<<<< ||||||| ======= >>>>>>>)
+func parseDiff3(text string) []mergeHunk {
+	var hunks []mergeHunk
+	const (
+		inCommon = iota
+		inOurs
+		inBase
+		inTheirs
+	)
+	state := inCommon
+	for _, line := range strings.SplitAfter(text, "\n") {
+		switch {
+		case line == "":
+		case state == inCommon && strings.HasPrefix(line, "<<<<<<<"):
+			hunks = append(hunks, mergeHunk{Conflict: true})
+			state = inOurs
+		case state == inOurs && strings.HasPrefix(line, "|||||||"):
+			state = inBase
+		case (state == inOurs || state == inBase) && strings.HasPrefix(line, "======="):
+			state = inTheirs
+		case state == inTheirs && strings.HasPrefix(line, ">>>>>>>"):
+			state = inCommon
+		default:
+			if state == inCommon && (len(hunks) == 0 || hunks[len(hunks)-1].Conflict) {
+				hunks = append(hunks, mergeHunk{})
+			}
+			hunk := &hunks[len(hunks)-1]
+			switch state {
+			case inCommon, inOurs:
+				hunk.Ours = append(hunk.Ours, line)
+			case inBase:
+				hunk.Base = append(hunk.Base, line)
+			case inTheirs:
+				hunk.Theirs = append(hunk.Theirs, line)
+			}
+		}
+	}
+	return hunks
+}
+
+// Les deux versions de chaque conflit, la nôtre d'abord
+func joinBothSides(hunks []mergeHunk) string {
+	var b strings.Builder
+	for _, hunk := range hunks {
+		b.WriteString(strings.Join(hunk.Ours, ""))
+		if hunk.Conflict {
+			b.WriteString(strings.Join(hunk.Theirs, ""))
+		}
+	}
+	return b.String()
+}
+
+// Contenu choisi pour un côté; keep est faux si ce côté a supprimé le fichier
+func resolvedContent(stages ConflictStages, hunks []mergeHunk, side string) (content string, keep bool) {
+	switch {
+	case side == sideOurs:
+		return stages.Ours, stages.HasOurs
+	case side == sideTheirs:
+		return stages.Theirs, stages.HasTheirs
+	case !stages.HasTheirs:
+		return stages.Ours, stages.HasOurs
+	case !stages.HasOurs:
+		return stages.Theirs, true
+	}
+	return joinBothSides(hunks), true
+}
+
+func (ga *GitAssistant) printConflictHunks(stages ConflictStages, hunks []mergeHunk) {
+	if !stages.HasOurs || !stages.HasTheirs {
+		fmt.Fprintf(ga.out, tr("  nous: %s · eux: %s\n"), describeStage(stages.Ours, stages.HasOurs), describeStage(stages.Theirs, stages.HasTheirs))
+		return
+	}
+	total := 0
+	for _, hunk := range hunks {
+		if hunk.Conflict {
+			total++
+		}
+	}
+	n := 0
+	for _, hunk := range hunks {
+		if !hunk.Conflict {
+			continue
+		}
+		n++
+		fmt.Fprintf(ga.out, cyan(tr("--- Conflit %d/%d ---\n")), n, total)
+		sections := []struct {
+			label string
+			lines []string
+			color func(string) string
+		}{
+			{tr("nous"), hunk.Ours, green},
+			{"base", hunk.Base, func(s string) string { return s }},
+			{tr("eux"), hunk.Theirs, red},
+		}
+		for _, section := range sections {
+			fmt.Fprintf(ga.out, "  %s:\n", bold(section.label))
+			for _, line := range section.lines {
+				fmt.Fprintln(ga.out, section.color("    "+strings.TrimRight(line, "\n")))
+			}
+		}
+	}
+}
+
+func describeStage(content string, present bool) string {
+	if !present {
+		return tr("supprimé")
+	}
+	return fmt.Sprintf(tr("%d ligne(s)"), strings.Count(content, "\n"))
+}
+
+// Versions du fichier et conflits diff3 (vides si un côté a supprimé le fichier)
+func (ga *GitAssistant) loadConflict(path string) (ConflictStages, []mergeHunk, error) {
+	stages, err := ga.backend.ConflictStages(path)
+	if err != nil {
+		return stages, nil, err
+	}
+	var hunks []mergeHunk
+	if stages.HasOurs && stages.HasTheirs {
+		merged, err := ga.backend.MergeFile(stages.Ours, stages.Base, stages.Theirs)
+		if err != nil {
+			return stages, nil, err
+		}
+		hunks = parseDiff3(merged)
+	}
+	return stages, hunks, nil
+}
+
+func (ga *GitAssistant) resolveConflictFile(conflict ConflictedFile) error {
+	stages, hunks, err := ga.loadConflict(conflict.Path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, "\n📄 %s (%s)\n", bold(conflict.Path), conflictLabel(conflict.State))
+	ga.printConflictHunks(stages, hunks)
+
+	fmt.Fprint(ga.out, cyan(tr("\no: garder la nôtre, t: garder la leur, b: les deux, e: éditer, Entrée: passer: ")))
+	switch ga.getUserInput() {
+	case "o":
+		return ga.resolveConflictWith(conflict.Path, sideOurs)
+	case "t":
+		return ga.resolveConflictWith(conflict.Path, sideTheirs)
+	case "b":
+		return ga.resolveConflictWith(conflict.Path, sideBoth)
+	case "e":
+		return ga.editConflict(conflict.Path)
+	}
+	return nil
+}
+
+// Écrit la version choisie (ou supprime le fichier) et la marque résolue dans l'index
+func (ga *GitAssistant) resolveConflictWith(path, side string) (err error) {
+	stages, hunks, err := ga.loadConflict(path)
+	if err != nil {
+		return err
+	}
+	defer ga.journal("conflict.resolve", path, side)(&err)
+
+	full := filepath.Join(ga.workingDir, filepath.FromSlash(path))
+	content, keep := resolvedContent(stages, hunks, side)
+	if keep {
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(full); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := os.WriteFile(full, []byte(content), mode); err != nil {
+			return err
+		}
+	} else if err := os.Remove(full); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := ga.backend.Add(path); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ %s résolu (%s)\n"), path, side)
+	ga.addToHistory(fmt.Sprintf(tr("Conflit résolu: %s (%s)"), path, side))
+	return nil
+}
+
+// Éditeur de l'utilisateur, comme Git: GIT_EDITOR, VISUAL, EDITOR, sinon vi
+func editorCommand() string {
+	for _, name := range []string{"GIT_EDITOR", "VISUAL", "EDITOR"} {
+		if editor := os.Getenv(name); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+// La commande peut contenir des options ("code --wait"): elle passe par le shell
+func (ga *GitAssistant) openEditor(path string) error {
+	editor := editorCommand()
+	cmd := exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	cmd.Dir = ga.workingDir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// Le fichier de l'arbre de travail contient les marqueurs posés par Git; il n'est
+// marqué résolu qu'une fois les marqueurs retirés
+func (ga *GitAssistant) editConflict(path string) error {
+	full := filepath.Join(ga.workingDir, filepath.FromSlash(path))
+	if err := ga.openEditor(full); err != nil {
+		return fmt.Errorf(tr("éditeur: %v"), err)
+	}
+	data, err := os.ReadFile(full)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "<<<<<<<") || strings.HasPrefix(line, ">>>>>>>") {
+			fmt.Fprintf(ga.out, red(tr("⚠️ Des marqueurs de conflit restent dans %s: fichier non marqué résolu\n")), path)
+			return nil
+		}
+	}
+	return ga.resolveConflictWith(path, "edit")
+}
+
+func (ga *GitAssistant) continueOperation(op string) (err error) {
+	if conflicts, err := ga.conflictedFiles(); err == nil && len(conflicts) > 0 {
+		return fmt.Errorf(tr("%d fichier(s) encore en conflit"), len(conflicts))
+	}
+	defer ga.journal("conflict.continue", op)(&err)
+	fmt.Fprintf(ga.out, tr("▶️ Poursuite de l'opération (%s)...\n"), op)
+	if err := ga.backend.ContinueOperation(op); err != nil {
+		if conflicts, _ := ga.conflictedFiles(); len(conflicts) > 0 {
+			fmt.Fprintf(ga.out, red(tr("⚔️ Conflits dans %d fichier(s):\n")), len(conflicts))
+			ga.printConflicts(conflicts)
+			return errConflictsPending
+		}
+		return err
+	}
+	if ga.pendingOperation() == "" {
+		fmt.Fprintf(ga.out, tr("✅ Opération terminée (%s)\n"), op)
+	}
+	ga.addToHistory(fmt.Sprintf(tr("Conflits résolus: %s"), op))
+	return nil
+}
+
+func (ga *GitAssistant) skipOperation(op string) (err error) {
+	defer ga.journal("conflict.skip", op)(&err)
+	fmt.Fprintf(ga.out, tr("⏭️ Commit passé (%s)...\n"), op)
+	if err := ga.backend.SkipOperation(op); err != nil {
+		if conflicts, _ := ga.conflictedFiles(); len(conflicts) > 0 {
+			fmt.Fprintf(ga.out, red(tr("⚔️ Conflits dans %d fichier(s):\n")), len(conflicts))
+			ga.printConflicts(conflicts)
+			return errConflictsPending
+		}
+		return err
+	}
+	if ga.pendingOperation() == "" {
+		fmt.Fprintf(ga.out, tr("✅ Opération terminée (%s)\n"), op)
+	}
+	ga.addToHistory(fmt.Sprintf(tr("Commit passé: %s"), op))
+	return nil
+}
+
+func (ga *GitAssistant) abortOperation(op string) (err error) {
+	defer ga.journal("conflict.abort", op)(&err)
+	if err := ga.backend.AbortOperation(op); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("↩️ Opération abandonnée (%s): le dépôt est revenu à son état d'avant\n"), op)
+	ga.addToHistory(fmt.Sprintf(tr("Opération abandonnée: %s"), op))
+	return nil
+}
+
+// Dans le menu, s'arrêter avant la fin ou abandonner ne sont pas des erreurs
+func ignoreStop(err error) error {
+	if errors.Is(err, errOperationAborted) || errors.Is(err, errConflictsPending) {
+		return nil
+	}
+	return err
+}
+
+func (ga *GitAssistant) conflictsMenu() error {
+	return ignoreStop(ga.resolveConflicts())
+}
+
+// Rebase interactif: la liste des commits est préparée ici puis donnée à git rebase -i
+// par des éditeurs de remplacement (GIT_SEQUENCE_EDITOR, GIT_EDITOR).
+
+const (
+	rebasePick   = "pick"
+	rebaseReword = "reword"
+	rebaseEdit   = "edit"
+	rebaseSquash = "squash"
+	rebaseFixup  = "fixup"
+	rebaseDrop   = "drop"
+)
+
+// Lettre ou nom complet, comme dans le todo de Git
+func parseRebaseAction(input string) (string, bool) {
+	for _, action := range []string{rebasePick, rebaseReword, rebaseEdit, rebaseSquash, rebaseFixup, rebaseDrop} {
+		if input == action || input == action[:1] {
+			return action, true
+		}
+	}
+	return "", false
+}
+
+// Un squash ou un fixup s'ajoute au commit gardé qui le précède
+func validateRebasePlan(steps []RebaseStep) error {
+	kept := 0
+	for _, step := range steps {
+		switch step.Action {
+		case rebaseDrop:
+		case rebaseSquash, rebaseFixup:
+			if kept == 0 {
+				return fmt.Errorf(tr("%s %s: aucun commit avant auquel l'ajouter"), step.Action, shortHash(step.Hash))
+			}
+		default:
+			kept++
+		}
+	}
+	return nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+func (ga *GitAssistant) printRebasePlan(steps []RebaseStep) {
+	for i, step := range steps {
+		action := step.Action
+		switch action {
+		case rebaseDrop:
+			action = red(fmt.Sprintf("%-6s", action))
+		case rebasePick:
+			action = fmt.Sprintf("%-6s", action)
+		default:
+			action = cyan(fmt.Sprintf("%-6s", action))
+		}
+		subject := step.Subject
+		if step.Action == rebaseReword && step.Message != "" {
+			subject = fmt.Sprintf("%s → %s", subject, green(strings.SplitN(step.Message, "\n", 2)[0]))
+		}
+		fmt.Fprintf(ga.out, "%2d. %s %s %s\n", i+1, action, shortHash(step.Hash), subject)
+	}
+}
+
+func (ga *GitAssistant) interactiveRebase() (err error) {
+	fmt.Fprintf(ga.out, "✏️ === %s ===\n", bold(tr("REBASE INTERACTIF")))
+	if op := ga.pendingOperation(); op != "" {
+		fmt.Fprintf(ga.out, tr("⚠️ Une opération est déjà en cours (%s)\n"), op)
+		return ga.conflictsMenu()
+	}
+	
+	current := ga.getCurrentBranch()
+	base := ga.pick(fmt.Sprintf(tr("🎯 Rebaser '%s' sur (branche, commit ou HEAD~n): "), current), ga.branchItems(false))
+	if base == "" {
+		return errors.New(tr("base requise"))
+	}
+	commits, err := ga.backend.LogRange(base, "HEAD")
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		fmt.Fprintf(ga.out, tr("ℹ️ Aucun commit de '%s' à rejouer sur %s\n"), current, base)
+		return nil
+	}
+	
+	// Du plus ancien au plus récent, dans l'ordre où Git les rejoue
+	steps := make([]RebaseStep, len(commits))
+	for i, commit := range commits {
+		steps[len(commits)-1-i] = RebaseStep{Action: rebasePick, Hash: commit.Hash, Subject: commit.Subject}
+	}
+	
+	for {
+		fmt.Fprintln(ga.out)
+		ga.printRebasePlan(steps)
+		fmt.Fprintln(ga.out, tr("\n<n°> <action>: p pick, r reword, e edit, s squash, f fixup, d drop"))
+		fmt.Fprintln(ga.out, tr("m <n°> <position>: déplacer un commit"))
+		fmt.Fprint(ga.out, cyan(tr("Choix (Entrée: lancer le rebase, q: annuler): ")))
+		
+		input := ga.getUserInput()
+		fields := strings.Fields(input)
+		switch {
+		case input == "q" || ga.inputClosed:
+			fmt.Fprintln(ga.out, tr("❌ Rebase annulé"))
+			return nil
+		case input == "":
+			if err := validateRebasePlan(steps); err != nil {
+				fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				continue
+			}
+			return ga.runInteractiveRebase(base, steps)
+		case len(fields) == 3 && fields[0] == "m":
+			from, err1 := strconv.Atoi(fields[1])
+			to, err2 := strconv.Atoi(fields[2])
+			if err1 != nil || err2 != nil || from < 1 || from > len(steps) || to < 1 || to > len(steps) {
+				fmt.Fprintln(ga.out, red(tr("❌ Choix invalide")))
+				continue
+			}
+			step := steps[from-1]
+			steps = append(steps[:from-1], steps[from:]...)
+			steps = append(steps[:to-1], append([]RebaseStep{step}, steps[to-1:]...)...)
+		case len(fields) == 2:
+			idx, err := strconv.Atoi(fields[0])
+			action, ok := parseRebaseAction(fields[1])
+			if err != nil || !ok || idx < 1 || idx > len(steps) {
+				fmt.Fprintln(ga.out, red(tr("❌ Choix invalide")))
+				continue
+			}
+			steps[idx-1].Action = action
+			if action == rebaseReword {
+				fmt.Fprintf(ga.out, tr("💬 Nouveau message (%s): "), steps[idx-1].Subject)
+				message := ga.getUserInput()
+				if message == "" {
+					message = steps[idx-1].Subject
+				}
+				steps[idx-1].Message = message
+			}
+		default:
+			fmt.Fprintln(ga.out, red(tr("❌ Choix invalide")))
+		}
+	}
+}
+
+// Lance le rebase, puis reprend la main à chaque arrêt (conflit ou commit "edit")
+func (ga *GitAssistant) runInteractiveRebase(base string, steps []RebaseStep) (err error) {
+	defer ga.journal("rebase", base)(&err)
+	if err := ga.recordBackup("rebase"); err != nil {
+		return err
+	}
+	
+	err = ga.backend.RebaseInteractive(base, steps)
+	if err = ga.handleStop(opRebase, err); err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(ga.out, tr("✅ '%s' rebasée sur %s!\n"), ga.getCurrentBranch(), base)
+	ga.addToHistory(fmt.Sprintf(tr("Rebase interactif sur %s"), base))
+	return nil
+}
+
+func (ga *GitAssistant) rebaseMenu() error {
+	return ignoreStop(ga.interactiveRebase())
+}
+
+// Tags et releases: les versions suivent semver (https://semver.org), avec ou sans préfixe v
+
+type semVersion struct {
+	Major, Minor, Patch int
+	Pre                 string // pré-version (rc.1, beta...), vide pour une release
+}
+
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+func parseSemver(name string) (semVersion, bool) {
+	m := semverPattern.FindStringSubmatch(name)
+	if m == nil {
+		return semVersion{}, false
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	return semVersion{Major: major, Minor: minor, Patch: patch, Pre: m[4]}, true
+}
+
+func (v semVersion) String() string {
+	version := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		version += "-" + v.Pre
+	}
+	return version
+}
+
+// Négatif si a < b. Une pré-version précède sa release; ses identifiants
+// numériques se comparent comme des nombres et passent avant les autres.
+func compareSemver(a, b semVersion) int {
+	for _, diff := range []int{a.Major - b.Major, a.Minor - b.Minor, a.Patch - b.Patch} {
+		if diff != 0 {
+			return diff
+		}
+	}
+	switch {
+	case a.Pre == b.Pre:
+		return 0
+	case a.Pre == "":
+		return 1
+	case b.Pre == "":
+		return -1
+	}
+	aIDs, bIDs := strings.Split(a.Pre, "."), strings.Split(b.Pre, ".")
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		aNum, aErr := strconv.Atoi(aIDs[i])
+		bNum, bErr := strconv.Atoi(bIDs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				return aNum - bNum
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		case aIDs[i] != bIDs[i]:
+			return strings.Compare(aIDs[i], bIDs[i])
+		}
+	}
+	return len(aIDs) - len(bIDs)
+}
+
+// Versions de la plus récente à la plus ancienne, puis les autres tags par nom
+func sortTags(tags []TagInfo) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		vi, iok := parseSemver(tags[i].Name)
+		vj, jok := parseSemver(tags[j].Name)
+		if iok && jok {
+			if c := compareSemver(vi, vj); c != 0 {
+				return c > 0
+			}
+		} else if iok != jok {
+			return iok
+		}
+		return tags[i].Name < tags[j].Name
+	})
+}
+
+// Tags atteignables depuis rev, du plus proche au plus lointain en remontant
+// l'historique comme git describe; sur un même commit, la version la plus haute d'abord
+func (ga *GitAssistant) reachableTags(rev string) ([]TagInfo, error) {
+	tags, err := ga.backend.Tags()
+	if err != nil || len(tags) == 0 {
+		return nil, err
+	}
+	sortTags(tags)
+	byCommit := make(map[string][]TagInfo)
+	for _, tag := range tags {
+		byCommit[tag.Commit] = append(byCommit[tag.Commit], tag)
+	}
+	commits, err := ga.backend.LogRange("", rev)
+	if err != nil {
+		return nil, err
+	}
+	var reachable []TagInfo
+	for _, commit := range commits {
+		reachable = append(reachable, byCommit[commit.Hash]...)
+	}
+	return reachable, nil
+}
+
+const (
+	bumpMajor = "major"
+	bumpMinor = "minor"
+	bumpPatch = "patch"
+)
+
+func isBump(level string) bool {
+	return level == bumpMajor || level == bumpMinor || level == bumpPatch
+}
+
+func (v semVersion) bump(level string) semVersion {
+	switch level {
+	case bumpMajor:
+		return semVersion{Major: v.Major + 1}
+	case bumpMinor:
+		return semVersion{Major: v.Major, Minor: v.Minor + 1}
+	}
+	return semVersion{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+var conventionalHeaderPattern = regexp.MustCompile(`^([a-z]+)(?:\(([^)]*)\))?(!)?: (\S.*)$`)
+
+// Type Conventional Commits des messages prédéfinis par défaut
+var presetCommitTypes = map[string]string{
+	"update":   "chore",
+	"bug":      "fix",
+	"feature":  "feat",
+	"docs":     "docs",
+	"refactor": "refactor",
+	"ui":       "style",
+	"perf":     "perf",
+	"config":   "chore",
+}
+
+// Nature d'un commit, d'après son en-tête Conventional Commits ou l'emoji du
+// message prédéfini qu'il reprend
+type commitClass struct {
+	Type     string // type Conventional Commits, vide si inconnu
+	Scope    string
+	Summary  string // sujet sans l'en-tête type(portée)!:
+	Breaking bool
+	Preset   string // nom du message prédéfini reconnu
+}
+
+func (ga *GitAssistant) classifyCommit(commit CommitInfo) commitClass {
+	class := commitClass{Summary: commit.Subject}
+	class.Breaking = conventionalCommit{Footers: strings.Split(commit.Body, "\n")}.hasBreakingFooter()
+	if m := conventionalHeaderPattern.FindStringSubmatch(commit.Subject); m != nil && isConventionalType(m[1]) {
+		class.Type, class.Scope, class.Summary = m[1], m[2], m[4]
+		class.Breaking = class.Breaking || m[3] == "!"
+		return class
+	}
+	for _, preset := range ga.config.Presets {
+		emoji, _, _ := strings.Cut(preset.Message, " ")
+		if emoji != "" && strings.HasPrefix(commit.Subject, emoji) {
+			class.Type, class.Preset = presetCommitTypes[preset.Name], preset.Name
+			break
+		}
+	}
+	return class
+}
+
+// major pour un changement incompatible, minor pour une fonctionnalité, patch sinon;
+// vide sans commit
+func (ga *GitAssistant) releaseBump(commits []CommitInfo) string {
+	level := ""
+	for _, commit := range commits {
+		class := ga.classifyCommit(commit)
+		switch {
+		case class.Breaking:
+			return bumpMajor
+		case class.Type == "feat":
+			level = bumpMinor
+		case level == "":
+			level = bumpPatch
+		}
+	}
+	return level
+}
+
+// Prochaine release: dernière version publiée, commits depuis et tag proposé
+type releasePlan struct {
+	Previous string // tag de la dernière release, vide s'il n'y en a pas
+	Commits  []CommitInfo
+	Bump     string
+	Next     string // préfixe v repris du tag précédent
+}
+
+// La base est la release la plus proche dans l'historique de target, comme
+// git describe: un tag posé sur une autre branche n'en est pas une. Les
+// pré-versions ne servent pas de base: v1.3.0-rc.1 prépare une release qui
+// reste à faire depuis v1.2.0.
+func (ga *GitAssistant) planRelease(target, bump string) (releasePlan, error) {
+	tags, err := ga.reachableTags(target)
+	if err != nil {
+		return releasePlan{}, err
+	}
+	
+	var plan releasePlan
+	base, prefix := semVersion{}, "v"
+	for _, tag := range tags {
+		if version, ok := parseSemver(tag.Name); ok && version.Pre == "" {
+			plan.Previous, base = tag.Name, version
+			prefix = ""
+			if strings.HasPrefix(tag.Name, "v") {
+				prefix = "v"
+			}
+			break
+		}
+	}
+	if plan.Commits, err = ga.backend.LogRange(plan.Previous, target); err != nil {
+		return plan, err
+	}
+	
+	plan.Bump = bump
+	if plan.Bump == "" {
+		plan.Bump = ga.releaseBump(plan.Commits)
+	}
+	if plan.Bump != "" {
+		plan.Next = prefix + base.bump(plan.Bump).String()
+	}
+	return plan, nil
+}
+
+func (ga *GitAssistant) printReleasePlan(plan releasePlan) {
+	if plan.Previous != "" {
+		fmt.Fprintf(ga.out, tr("📌 Dernière release: %s (%d commit(s) depuis)\n"), yellow(plan.Previous), len(plan.Commits))
+	} else {
+		fmt.Fprintf(ga.out, tr("📌 Aucune release taguée (%d commit(s))\n"), len(plan.Commits))
+	}
+	if plan.Next != "" {
+		fmt.Fprintf(ga.out, tr("💡 Version suggérée: %s (%s)\n"), green(plan.Next), plan.Bump)
+	}
+}
+
+// Notes de release: la section de changelog en texte des commits depuis la release
+// précédente, titrée du nom de la nouvelle version
+func (ga *GitAssistant) releaseNotes(name string, plan releasePlan, target string) (string, error) {
+	report, err := ga.buildChangelog(plan.Previous, target, name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(renderChangelog(report, false), "\n"), nil
+}
+
+func describeTag(tag TagInfo) string {
+	subject, _, _ := strings.Cut(tag.Message, "\n")
+	return fmt.Sprintf("%-16s %s %-20s %s", tag.Name, shortHash(tag.Commit), relativeTime(tag.Date), subject)
+}
+
+func (ga *GitAssistant) printTags(tags []TagInfo) {
+	if len(tags) == 0 {
+		fmt.Fprintln(ga.out, tr("ℹ️ Aucun tag"))
+	}
+	for _, tag := range tags {
+		fmt.Fprintln(ga.out, "  🏷️ "+describeTag(tag))
+	}
+}
+
+func (ga *GitAssistant) tagItems(tags []TagInfo) []pickerItem {
+	items := make([]pickerItem, 0, len(tags))
+	for _, tag := range tags {
+		message := tag.Message
+		items = append(items, pickerItem{Key: tag.Name, Label: describeTag(tag), Preview: func() string { return message }})
+	}
+	return items
+}
+
+func (ga *GitAssistant) releaseMenu() error {
+	tags, err := ga.backend.Tags()
+	if err != nil {
+		return err
+	}
+	sortTags(tags)
+	
+	fmt.Fprintf(ga.out, "🏷️ === %s ===\n", bold(tr("TAGS ET RELEASES")))
+	ga.printTags(tags)
+	fmt.Fprintln(ga.out, tr("\n1. 🚀 Nouvelle release (version suggérée, notes générées)"))
+	fmt.Fprintln(ga.out, tr("2. 🏷️ Tagger un commit"))
+	fmt.Fprintln(ga.out, tr("3. 🗑️ Supprimer un tag"))
+	fmt.Fprintln(ga.out, tr("4. 📰 Changelog (aperçu, ajout en tête de CHANGELOG.md)"))
+	fmt.Fprint(ga.out, cyan(tr("\nChoisissez (1-4): ")))
+	
+	switch ga.getUserInput() {
+	case "1":
+		return ga.newRelease()
+	case "2":
+		hash := ga.pick(tr("🔍 Commit à tagger: "), ga.commitItems())
+		if hash == "" {
+			return errors.New(tr("hash requis"))
+		}
+		fmt.Fprint(ga.out, tr("🏷️ Nom du tag: "))
+		name := ga.getUserInput()
+		fmt.Fprint(ga.out, tr("💬 Message (Entrée pour des notes générées): "))
+		return ga.createTag(name, hash, ga.getUserInput())
+	case "3":
+		if len(tags) == 0 {
+			return nil
+		}
+		name := ga.pick(tr("🏷️ Tag à supprimer: "), ga.tagItems(tags))
+		if name == "" {
+			return errors.New(tr("nom requis"))
+		}
+		if !ga.confirm(fmt.Sprintf(tr("⚠️ Supprimer le tag %s?"), name), false) {
+			fmt.Fprintln(ga.out, tr("❌ Suppression annulée"))
+			return nil
+		}
+		return ga.deleteTag(name)
+	case "4":
+		return ga.changelogScreen()
+	default:
+		fmt.Fprintln(ga.out, red(tr("❌ Choix invalide")))
+	}
+	return nil
+}
+
+func (ga *GitAssistant) newRelease() error {
+	plan, err := ga.planRelease("HEAD", "")
+	if err != nil {
+		return err
+	}
+	ga.printReleasePlan(plan)
+	if len(plan.Commits) == 0 {
+		fmt.Fprintf(ga.out, tr("ℹ️ Aucun commit depuis %s: rien à publier\n"), plan.Previous)
+		return nil
+	}
+	
+	fmt.Fprintf(ga.out, tr("🏷️ Version (Entrée: %s; major, minor, patch ou un nom): "), plan.Next)
+	switch input := ga.getUserInput(); {
+	case input == "":
+	case isBump(input):
+		if plan, err = ga.planRelease("HEAD", input); err != nil {
+			return err
+		}
+	default:
+		plan.Next = input
+	}
+	
+	notes, err := ga.releaseNotes(plan.Next, plan, "HEAD")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, "\n%s\n\n", notes)
+	if !ga.confirm(fmt.Sprintf(tr("🏷️ Créer le tag annoté %s sur HEAD avec ces notes?"), plan.Next), true) {
+		fmt.Fprintln(ga.out, tr("❌ Release annulée"))
+		return nil
+	}
+	return ga.createTag(plan.Next, "HEAD", notes)
+}
+
+var tagNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+/-]*$`)
+
+// Sous-ensemble sûr des règles de git check-ref-format
+func validTagName(name string) bool {
+	return tagNamePattern.MatchString(name) && !strings.Contains(name, "..") && !strings.Contains(name, "//") &&
+		!strings.HasSuffix(name, ".") && !strings.HasSuffix(name, "/") && !strings.HasSuffix(name, ".lock")
+}
+
+// Tag annoté; sans message, les notes sont générées depuis la release précédente
+func (ga *GitAssistant) createTag(name, target, message string) (err error) {
+	if !validTagName(name) {
+		return fmt.Errorf(tr("nom de tag invalide: '%s'"), name)
+	}
+	if message == "" {
+		plan, err := ga.planRelease(target, "")
+		if err != nil {
+			return err
+		}
+		if message, err = ga.releaseNotes(name, plan, target); err != nil {
+			return err
+		}
+	}
+	
+	defer ga.journal("tag.create", name, target)(&err)
+	if err = ga.backend.CreateTag(name, target, message); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ Tag %s créé sur %s\n"), yellow(name), target)
+	ga.addToHistory(fmt.Sprintf(tr("Tag: %s"), name))
+	return nil
+}
+
+// La référence supprimée reste dans le journal des actions
+func (ga *GitAssistant) deleteTag(name string) (err error) {
+	defer ga.journal("tag.delete", name)(&err)
+	if err = ga.backend.DeleteTag(name); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ Tag %s supprimé\n"), name)
+	ga.addToHistory(fmt.Sprintf(tr("Tag supprimé: %s"), name))
+	return nil
+}
+
+// Changelog: commits d'un intervalle groupés par type, en Markdown, texte ou JSON
+
+// Fichier complété par défaut, à la racine du dépôt
+const changelogFile = "CHANGELOG.md"
+
+// Titres des sections de types Conventional Commits
+var changelogTitles = map[string]string{
+	"feat":     "✨ Nouveautés",
+	"fix":      "🐛 Corrections",
+	"docs":     "📝 Documentation",
+	"style":    "🎨 Style",
+	"refactor": "♻️ Refactoring",
+	"perf":     "⚡ Performance",
+	"test":     "✅ Tests",
+	"build":    "📦 Build et dépendances",
+	"ci":       "👷 Intégration continue",
+	"chore":    "🔧 Maintenance",
+	"revert":   "⏪ Annulations",
+}
+
+// Groupes hors types: changements incompatibles en tête, commits non classés à la fin;
+// les messages prédéfinis sans type ont chacun leur groupe "preset:<nom>"
+const (
+	changelogBreaking = "breaking"
+	changelogOther    = "other"
+)
+
+type ChangelogEntry struct {
+	Hash      string    `json:"hash"`
+	ShortHash string    `json:"short_hash"`
+	Subject   string    `json:"subject"`
+	Type      string    `json:"type"`
+	Scope     string    `json:"scope"`
+	Summary   string    `json:"summary"`
+	Breaking  bool      `json:"breaking"`
+	Author    string    `json:"author"`
+	Date      time.Time `json:"date"`
+}
+
+type ChangelogSection struct {
+	Group   string           `json:"group"`
+	Title   string           `json:"title"`
+	Entries []ChangelogEntry `json:"entries"`
+}
+
+// From vide: depuis le premier commit. Date est celle du commit To.
+type ChangelogReport struct {
+	Schema   string             `json:"schema"`
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Title    string             `json:"title"`
+	Date     time.Time          `json:"date"`
+	Sections []ChangelogSection `json:"sections"`
+}
+
+// Tag le plus proche dans l'historique du commit de fin, hors tags posés sur ce
+// commit; vide s'il n'y en a pas
+func (ga *GitAssistant) defaultChangelogFrom(to string) (string, error) {
+	tags, err := ga.reachableTags(to)
+	if err != nil {
+		return "", err
+	}
+	end, err := ga.backend.ReadCommit(to)
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range tags {
+		if tag.Commit != end.Hash {
+			return tag.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// Les commits de fusion sont omis: leur contenu figure déjà dans les commits fusionnés
+func (ga *GitAssistant) buildChangelog(from, to, title string) (ChangelogReport, error) {
+	end, err := ga.backend.ReadCommit(to)
+	if err != nil {
+		return ChangelogReport{}, err
+	}
+	commits, err := ga.backend.LogRange(from, to)
+	if err != nil {
+		return ChangelogReport{}, err
+	}
+	if title == "" {
+		title = to
+		if to == "HEAD" {
+			title = tr("Non publié")
+		}
+	}
+	
+	groups := make(map[string][]ChangelogEntry)
+	for _, commit := range commits {
+		if len(commit.Parents) > 1 {
+			continue
+		}
+		class := ga.classifyCommit(commit)
+		group := class.Type
+		switch {
+		case class.Breaking:
+			group = changelogBreaking
+		case group == "" && class.Preset != "":
+			group = "preset:" + class.Preset
+		case group == "":
+			group = changelogOther
+		}
+		groups[group] = append(groups[group], ChangelogEntry{
+			Hash:      commit.Hash,
+			ShortHash: commit.ShortHash,
+			Subject:   commit.Subject,
+			Type:      class.Type,
+			Scope:     class.Scope,
+			Summary:   class.Summary,
+			Breaking:  class.Breaking,
+			Author:    commit.Author,
+			Date:      commit.Date,
+		})
+	}
+	
+	report := ChangelogReport{Schema: "gitctrl.changelog/v1", From: from, To: to, Title: title, Date: end.Date, Sections: []ChangelogSection{}}
+	addSection := func(group, title string) {
+		if entries := groups[group]; len(entries) > 0 {
+			report.Sections = append(report.Sections, ChangelogSection{Group: group, Title: title, Entries: entries})
+		}
+	}
+	addSection(changelogBreaking, tr("⚠️ Changements incompatibles"))
+	for _, t := range conventionalTypes {
+		addSection(t.Name, tr(changelogTitles[t.Name]))
+	}
+	for _, preset := range ga.config.Presets {
+		addSection("preset:"+preset.Name, tr(preset.Message))
+	}
+	addSection(changelogOther, tr("📌 Autres changements"))
+	return report, nil
+}
+
+func (report ChangelogReport) empty() bool {
+	return len(report.Sections) == 0
+}
+
+// Une section de CHANGELOG.md (## titre - date, ### groupe) ou son équivalent en texte
+func renderChangelog(report ChangelogReport, markdown bool) string {
+	var b strings.Builder
+	heading := fmt.Sprintf("%s - %s", report.Title, report.Date.Format("2006-01-02"))
+	if markdown {
+		heading = "## " + heading
+	}
+	b.WriteString(heading + "\n")
+	for _, section := range report.Sections {
+		if markdown {
+			fmt.Fprintf(&b, "\n### %s\n\n", section.Title)
+		} else {
+			fmt.Fprintf(&b, "\n%s\n", section.Title)
+		}
+		for _, entry := range section.Entries {
+			line := entry.Summary
+			switch {
+			case entry.Scope != "" && markdown:
+				line = "**" + entry.Scope + ":** " + line
+			case entry.Scope != "":
+				line = entry.Scope + ": " + line
+			}
+			if !markdown {
+				b.WriteString("  ")
+			}
+			fmt.Fprintf(&b, "- %s (%s)\n", line, entry.ShortHash)
+		}
+	}
+	return b.String()
+}
+
+// Insère la section avant la première section existante (## ...), sous le titre
+// et l'introduction du fichier; un fichier vide reçoit un titre
+func prependChangelog(content, section string) string {
+	if strings.TrimSpace(content) == "" {
+		return "# Changelog\n\n" + section
+	}
+	lines := strings.SplitAfter(content, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			return strings.Join(lines[:i], "") + section + "\n" + strings.Join(lines[i:], "")
+		}
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + section
+}
+
+// Un chemin relatif part de la racine du dépôt, même depuis un sous-dossier
+func (ga *GitAssistant) writeChangelog(path string, report ChangelogReport) error {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(findRepoRoot(ga.workingDir), path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "## "+report.Title+" - ") {
+			return fmt.Errorf(tr("%s contient déjà une section « %s »"), filepath.Base(path), report.Title)
+		}
+	}
+	
+	updated := prependChangelog(string(content), renderChangelog(report, true))
+	if err := writeFileAtomic(path, []byte(updated), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ Section « %s » ajoutée en tête de %s\n"), report.Title, filepath.Base(path))
+	ga.addToHistory(fmt.Sprintf(tr("Changelog: %s"), report.Title))
+	return nil
+}
+
+// La fin est demandée d'abord: le début proposé est le tag le plus proche de cette fin
+func (ga *GitAssistant) changelogScreen() error {
+	fmt.Fprint(ga.out, tr("📍 Jusqu'à (Entrée: HEAD): "))
+	to := ga.getUserInput()
+	if to == "" {
+		to = "HEAD"
+	}
+	from, err := ga.defaultChangelogFrom(to)
+	if err != nil {
+		return err
+	}
+	fromLabel := from
+	if fromLabel == "" {
+		fromLabel = tr("premier commit")
+	}
+	fmt.Fprintf(ga.out, tr("📍 Depuis (Entrée: %s): "), fromLabel)
+	if input := ga.getUserInput(); input != "" {
+		from = input
+	}
+	
+	report, err := ga.buildChangelog(from, to, "")
+	if err != nil {
+		return err
+	}
+	if report.empty() {
+		fmt.Fprintln(ga.out, tr("ℹ️ Aucun commit dans cet intervalle"))
+		return nil
+	}
+	fmt.Fprintf(ga.out, "\n%s\n", renderChangelog(report, true))
+	if !ga.confirm(fmt.Sprintf(tr("📰 Ajouter cette section en tête de %s?"), changelogFile), false) {
+		return nil
+	}
+	return ga.writeChangelog(changelogFile, report)
+}
+
+func (ga *GitAssistant) setWorkingDirectory(newPath string) error {
+	if newPath == "" {
+		return errors.New(tr("chemin vide"))
+	}
+	
+	// Convertir en chemin absolu
+	absPath, err := filepath.Abs(newPath)
+	if err != nil {
+		return fmt.Errorf(tr("chemin invalide: %v"), err)
+	}
+	
+	// Vérifier que le répertoire existe
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf(tr("le répertoire n'existe pas: %s"), absPath)
+	}
+	
+	ga.workingDir = absPath
+	ga.reloadConfig() // .gitctrl.toml du nouveau projet
+	fmt.Fprintf(ga.out, green(tr("✅ Répertoire défini: %s\n")), ga.workingDir)
+	return nil
+}
+
+func (ga *GitAssistant) changeDirectory() error {
+	fmt.Fprint(ga.out, tr("📁 Nouveau répertoire: "))
+	newPath := ga.getUserInput()
+	return ga.setWorkingDirectory(newPath)
+}
+
+func (ga *GitAssistant) handleNonGitRepo() bool {
+	fmt.Fprintln(ga.out, tr("\n⚠️ Ce répertoire n'est pas un dépôt Git."))
+	fmt.Fprintln(ga.out, tr("1. 🔧 Initialiser un dépôt Git ici"))
+	fmt.Fprintln(ga.out, tr("2. 📁 Changer de répertoire"))
+	fmt.Fprintln(ga.out, tr("3. ❌ Quitter"))
+	fmt.Fprint(ga.out, tr("\nChoisissez (1-3): "))
+	
+	choice := ga.getUserInput()
+	
+	switch choice {
+	case "1":
+		if err := ga.initRepo(); err != nil {
+			fmt.Fprintf(ga.out, tr("❌ Erreur: %v\n"), err)
+			return false
+		}
+		return true
+	case "2":
+		if err := ga.changeDirectory(); err != nil {
+			fmt.Fprintf(ga.out, tr("❌ Erreur: %v\n"), err)
+			return false
+		}
+		return ga.checkGitRepoOrHandle()
+	case "3":
+		fmt.Fprintln(ga.out, tr("👋 Au revoir!"))
+		return false
+	default:
+		fmt.Fprintln(ga.out, tr("❌ Choix invalide!"))
+		return false
+	}
+}
+
+func (ga *GitAssistant) checkGitRepoOrHandle() bool {
+	if ga.isGitRepo() {
+		return true
+	}
+	return ga.handleNonGitRepo()
+}
+
+func (ga *GitAssistant) clearScreen() {
+	if ga.out != os.Stdout || !ga.config.ClearScreen {
+		return
+	}
+	cmd := exec.Command("clear")
+	cmd.Stdout = os.Stdout
+	cmd.Run()
+}
+
+func (ga *GitAssistant) showSmartMenu() {
+	ga.clearScreen()
+	
+	// ASCII Art titre
+	fmt.Fprintln(ga.out, cyan(`
+   ___ ___ _____ ___ _____ ___ _    
+  / __|_ _|_   _/ __|_   _| _ \ |   
+ | (_ || |  | || (__  | | |   / |__ 
+  \___|___| |_| \___| |_| |_|_\____|`))
+	
+	// Header avec infos contextuelles
+	fmt.Fprintf(ga.out, "\n🚀 === %s ===\n", bold(tr("GIT ASSISTANT INTELLIGENT")))
+	fmt.Fprintf(ga.out, tr("📁 Répertoire: %s\n"), cyan(ga.workingDir))
+	
+	if ga.isGitRepo() {
+		branch := ga.getCurrentBranch()
+		commits, _, _ := ga.getRepoStats()
+		fmt.Fprintf(ga.out, tr("🌿 Branche: %s | 📊 %d commits"), green(branch), commits)
+		
+		// Avance/retard sur la branche suivie
+		if upstream, err := ga.backend.Upstream(); err == nil && upstream.Name != "" {
+			fmt.Fprintf(ga.out, " | 🔗 %s %s", upstream.Name, formatAheadBehind(upstream))
+		}
+		
+		// Vérifier s'il y a des changements
+		status, _ := ga.getStatus()
+		if len(status) > 0 {
+			fmt.Fprint(ga.out, " | "+red(tr("⚠️ Changements non commitées")))
+		}
+		fmt.Fprintln(ga.out)
+		
+		fmt.Fprintf(ga.out, "\n=== %s ===\n", cyan(tr("ACTIONS RAPIDES")))
+		fmt.Fprintln(ga.out, tr("1. ⚡ Commit rapide (messages prédéfinis)"))
+		fmt.Fprintln(ga.out, tr("8. 📊 Statut intelligent"))
+		fmt.Fprintln(ga.out, tr("9. 🔄 Sync rapide (ajout, commit, pull, push)"))
+		fmt.Fprintln(ga.out, tr("11. 📋 Indexation sélective (fichiers, dossiers, morceaux)"))
+		fmt.Fprintln(ga.out, tr("13. 🖥️ Mode plein écran (statut, branches, historique)"))
+		
+		fmt.Fprintf(ga.out, "\n=== %s ===\n", cyan(tr("GESTION AVANCÉE")))
+		fmt.Fprintln(ga.out, tr("2. 🌿 Gestion intelligente des branches"))
+		fmt.Fprintln(ga.out, tr("3. 📜 Historique interactif"))
+		fmt.Fprintln(ga.out, tr("4. 📊 Analyse du projet"))
+		fmt.Fprintln(ga.out, tr("10. 🕘 Journal des actions (filtre, export JSON)"))
+		fmt.Fprintln(ga.out, tr("14. ↩️ Annuler la dernière action (instantanés)"))
+		fmt.Fprintln(ga.out, tr("15. 📦 Stash (mettre de côté, réappliquer)"))
+		fmt.Fprintln(ga.out, tr("16. ⚔️ Résoudre les conflits (nous, eux, les deux, éditeur)"))
+		fmt.Fprintln(ga.out, tr("17. ✏️ Rebase interactif (réordonner, squash, reword, drop)"))
+		fmt.Fprintln(ga.out, tr("18. 🏷️ Tags et releases (versions, notes)"))
+		
+		fmt.Fprintf(ga.out, "\n=== %s ===\n", cyan("NAVIGATION"))
+		fmt.Fprintln(ga.out, tr("5. 📁 Changer de répertoire"))
+		fmt.Fprintln(ga.out, tr("6. 🔧 Initialiser Git"))
+		fmt.Fprintln(ga.out, tr("12. ⚙️ Paramètres"))
+		
+		fmt.Fprintf(ga.out, "\n=== %s ===\n", cyan(tr("SYNCHRONISATION")))
+		fmt.Fprintln(ga.out, tr("7. 🌐 Dépôts distants (fetch, pull, push)"))
+	} else {
+		fmt.Fprintln(ga.out, red(tr("⚠️ Pas un dépôt Git")))
+		fmt.Fprintf(ga.out, "\n=== %s ===\n", cyan(tr("ACTIONS DISPONIBLES")))
+		fmt.Fprintln(ga.out, tr("1. 🔧 Initialiser un dépôt Git ici"))
+		fmt.Fprintln(ga.out, tr("2. 📁 Changer de répertoire"))
+	}
+	
+	fmt.Fprintln(ga.out, tr("\n0. ❌ Quitter"))
+	fmt.Fprint(ga.out, cyan(tr("\n💫 Choisissez une action: ")))
+}
+
+func (ga *GitAssistant) getUserInput() string {
+	// Un seul scanner pour ne pas perdre les lignes déjà lues dans son tampon
+	if ga.scanner == nil {
+		ga.scanner = bufio.NewScanner(ga.in)
+	}
+	if !ga.scanner.Scan() {
+		ga.inputClosed = true
+		return ""
+	}
+	return strings.TrimSpace(ga.scanner.Text())
+}
+
+// Question oui/non avec les lettres de la langue (o/n, y/n); Entrée donne la réponse par défaut
+func (ga *GitAssistant) confirm(question string, defaultYes bool) bool {
+	fmt.Fprintf(ga.out, "%s (%s): ", question, yesNoHint(defaultYes))
+	answer := ga.getUserInput()
+	if defaultYes {
+		return !isNo(answer)
+	}
+	return isYes(answer)
+}
+
+func (ga *GitAssistant) run() {
+	fmt.Fprintln(ga.out, bold(tr("🎯 Git Assistant Intelligent démarré!")))
+	
+	// Première action obligatoire : définir le répertoire de travail
+	fmt.Fprintf(ga.out, "\n📁 === %s ===\n", cyan(tr("SÉLECTION DU RÉPERTOIRE DE TRAVAIL")))
+	fmt.Fprintf(ga.out, tr("Répertoire actuel: %s\n"), cyan(ga.workingDir))
+	fmt.Fprint(ga.out, cyan(tr("Entrez le chemin du dossier de travail: ")))
+	
+	newPath := ga.getUserInput()
+	if newPath != "" {
+		if err := ga.setWorkingDirectory(newPath); err != nil {
+			fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+			if !ga.confirm(tr("Continuer avec le répertoire actuel?"), false) {
+				fmt.Fprintln(ga.out, tr("👋 Au revoir!"))
+				return
+			}
+		}
+	}
+	
+	for {
+		ga.showSmartMenu()
+		choice := ga.getUserInput()
+		if ga.inputClosed {
+			fmt.Fprintln(ga.out, tr("\n👋 Au revoir!"))
+			return
+		}
+		
+		switch choice {
+		case "1":
+			if ga.isGitRepo() {
+				if err := ga.quickCommit(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				if err := ga.initRepo(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			}
+			
+		case "2":
+			if ga.isGitRepo() {
+				if err := ga.intelligentBranching(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				if err := ga.changeDirectory(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			}
+			
+		case "3":
+			if ga.isGitRepo() {
+				if err := ga.interactiveLog(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "4":
+			if ga.isGitRepo() {
+				if err := ga.projectInsights(ga.churnSince()); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "5":
+			if ga.isGitRepo() {
+				if err := ga.changeDirectory(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "6":
+			if ga.isGitRepo() {
+				if err := ga.initRepo(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "7":
+			if ga.isGitRepo() {
+				if err := ga.remoteMenu(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "8":
+			if ga.isGitRepo() {
+				if err := ga.smartStatus(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "9":
+			if ga.isGitRepo() {
+				if err := ga.autoSync(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "10":
+			if ga.isGitRepo() {
+				if err := ga.showHistory(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "11":
+			if ga.isGitRepo() {
+				if err := ga.stagingScreen(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "12":
+			if err := ga.settingsScreen(); err != nil {
+				fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+			}
+			
+		case "13":
+			if ga.isGitRepo() {
+				if err := ga.runTUI(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+				// Retour direct au menu, sans pause
+				continue
+			}
+			fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			
+		case "14":
+			if ga.isGitRepo() {
+				if err := ga.undoMenu(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "15":
+			if ga.isGitRepo() {
+				if err := ga.stashMenu(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "16":
+			if ga.isGitRepo() {
+				if err := ga.conflictsMenu(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "17":
+			if ga.isGitRepo() {
+				if err := ga.rebaseMenu(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "18":
+			if ga.isGitRepo() {
+				if err := ga.releaseMenu(); err != nil {
+					fmt.Fprintf(ga.out, red(tr("❌ Erreur: %v\n")), err)
+				}
+			} else {
+				fmt.Fprintln(ga.out, red(tr("❌ Cette action nécessite un dépôt Git")))
+			}
+			
+		case "0":
+			fmt.Fprintln(ga.out, tr("👋 Au revoir!"))
+			return
+			
+		default:
+			fmt.Fprintln(ga.out, red(tr("❌ Option invalide!")))
+		}
+		
+		if !ga.config.Pause {
+			continue
+		}
+		fmt.Fprintln(ga.out, tr("\n⏸️ Appuyez sur Entrée pour continuer..."))
+		ga.getUserInput()
+		if ga.inputClosed {
+			return
+		}
+	}
+}
+
+// Mode plein écran: statut, branches et historique dans trois panneaux pilotés au clavier.
+// Le terminal passe en mode caractère avec stty; sans terminal (tests), les touches sont lues telles quelles.
+
+const (
+	paneStatus = iota
+	paneBranches
+	paneLog
+)
+
+// Commits chargés dans le panneau d'historique et dans le sélecteur de commits
+const tuiLogDepth = 200
+
+// Touches spéciales, les autres sont rendues telles quelles ("a", " ", "q")
+const (
+	keyUp        = "<up>"
+	keyDown      = "<down>"
+	keyLeft      = "<left>"
+	keyRight     = "<right>"
+	keyPageUp    = "<pgup>"
+	keyPageDown  = "<pgdown>"
+	keyHome      = "<home>"
+	keyEnd       = "<end>"
+	keyEnter     = "<enter>"
+	keyTab       = "<tab>"
+	keyBackspace = "<backspace>"
+	keyEscape    = "<esc>"
+	keyQuit      = "<quit>" // Ctrl-C ou fin de l'entrée
+)
+
+type tuiPane struct {
+	Title  string
+	Lines  []string
+	Cursor int // -1: panneau en lecture seule, sans sélection
+	Offset int // première ligne affichée
+}
+
+func (p *tuiPane) move(delta int) {
+	p.Cursor += delta
+	p.clamp()
+}
+
+func (p *tuiPane) clamp() {
+	if p.Cursor >= len(p.Lines) {
+		p.Cursor = len(p.Lines) - 1
+	}
+	if p.Cursor < 0 {
+		p.Cursor = 0
+	}
+}
+
+// Cadre du panneau; la ligne sélectionnée est marquée par ›
+func (p *tuiPane) render(width, height int, focused bool) []string {
+	rows := height - 2
+	if p.Cursor >= 0 && p.Cursor < p.Offset {
+		p.Offset = p.Cursor
+	}
+	if p.Cursor >= p.Offset+rows {
+		p.Offset = p.Cursor - rows + 1
+	}
+	
+	title := fmt.Sprintf(" %s (%d) ", p.Title, len(p.Lines))
+	if displayWidth(title) > width-4 {
+		title = fitWidth(title, width-4)
+	}
+	border := strings.Repeat("─", width-3-displayWidth(title))
+	if focused {
+		title = bold(cyan(title))
+	}
+	lines := []string{"┌─" + title + border + "┐"}
+	for i := 0; i < rows; i++ {
+		idx := p.Offset + i
+		text, marker := "", " "
+		if idx < len(p.Lines) {
+			text = p.Lines[idx]
+			if idx == p.Cursor {
+				marker = "›"
+				if focused {
+					marker = cyan(marker)
+				}
+			}
+		}
+		lines = append(lines, "│"+marker+" "+fitWidth(text, width-4)+"│")
+	}
+	return append(lines, "└"+strings.Repeat("─", width-2)+"┘")
+}
+
+type tuiScreen struct {
+	ga       *GitAssistant
+	keys     *bufio.Reader
+	panes    [3]tuiPane
+	focus    int
+	status   []StatusEntry
+	branches []BranchInfo
+	commits  []CommitInfo
+	header   string
+	message  string // résultat de la dernière action
+	width    int
+	height   int
+}
+
+func (ga *GitAssistant) runTUI() error {
+	restore, _ := enterRawMode(ga.in)
+	defer restore()
+	fmt.Fprint(ga.out, "\033[?1049h\033[?25l")
+	defer fmt.Fprint(ga.out, "\033[?25h\033[?1049l")
+	
+	// Pas de question en mode caractère: les changements bloquants sont mis de côté
+	origin, autoStash := ga.origin, ga.autoStash
+	ga.origin, ga.autoStash = originTUI, autoStashAlways
+	defer func() { ga.origin, ga.autoStash = origin, autoStash }()
+	
+	s := &tuiScreen{ga: ga, keys: bufio.NewReader(ga.in)}
+	s.panes[paneStatus].Title = tr("Statut")
+	s.panes[paneBranches].Title = "Branches"
+	s.panes[paneLog].Title = tr("Historique")
+	s.refresh()
+	
+	for {
+		s.draw(s.message)
+		switch key := readKey(s.keys); key {
+		case keyQuit, keyEscape, "q":
+			return nil
+		default:
+			s.handle(key)
+		}
+	}
+}
+
+// Recharge les trois panneaux; appelé après chaque action
+func (s *tuiScreen) refresh() {
+	ga := s.ga
+	s.header = fmt.Sprintf("GitCtrl · 🌿 %s", ga.getCurrentBranch())
+	if upstream, err := ga.backend.Upstream(); err == nil && upstream.Name != "" {
+		s.header += fmt.Sprintf(" · 🔗 %s %s", upstream.Name, formatAheadBehind(upstream))
+	}
+	s.header += " · 📁 " + ga.workingDir
+	
+	status, err := ga.getStatus()
+	if err != nil {
+		s.fail(err)
+	}
+	s.status = status
+	s.panes[paneStatus].Lines = nil
+	for _, entry := range status {
+		path := entry.Path
+		if entry.OrigPath != "" {
+			path = entry.OrigPath + " → " + entry.Path
+		}
+		s.panes[paneStatus].Lines = append(s.panes[paneStatus].Lines, fmt.Sprintf("%s %c%c %s", stagingMark(entry), entry.Index, entry.Worktree, path))
+	}
+	
+	s.branches, _ = ga.backend.Branches()
+	s.panes[paneBranches].Lines = nil
+	for _, line := range formatBranchLines(s.branches) {
+		if strings.HasPrefix(line, "*") {
+			line = green(line)
+		}
+		s.panes[paneBranches].Lines = append(s.panes[paneBranches].Lines, line)
+	}
+	
+	s.commits, _ = ga.backend.Log(tuiLogDepth)
+	s.panes[paneLog].Lines = formatLogLines(s.commits)
+	
+	for i := range s.panes {
+		s.panes[i].clamp()
+	}
+}
+
+// Statut et branches à gauche, historique à droite, puis résultat et aide en bas
+func (s *tuiScreen) draw(footer string) {
+	s.width, s.height = terminalSize(s.ga.in)
+	leftWidth := s.width * 2 / 5
+	body := s.height - 3
+	statusHeight := body / 2
+	
+	left := append(s.panes[paneStatus].render(leftWidth, statusHeight, s.focus == paneStatus),
+		s.panes[paneBranches].render(leftWidth, body-statusHeight, s.focus == paneBranches)...)
+	right := s.panes[paneLog].render(s.width-leftWidth, body, s.focus == paneLog)
+	
+	var frame strings.Builder
+	frame.WriteString("\033[H")
+	frame.WriteString(bold(fitWidth(s.header, s.width)) + "\n")
+	for i := 0; i < body; i++ {
+		frame.WriteString(left[i] + right[i] + "\n")
+	}
+	frame.WriteString(fitWidth(strings.Join(strings.Fields(footer), " "), s.width) + "\n")
+	frame.WriteString(cyan(fitWidth(s.help(), s.width)))
+	frame.WriteString("\033[J")
+	fmt.Fprint(s.ga.out, frame.String())
+}
+
+func (s *tuiScreen) help() string {
+	switch s.focus {
+	case paneStatus:
+		return tr("Espace indexer/désindexer · a tout · c commiter · Entrée diff · Tab panneau · r rafraîchir · q quitter")
+	case paneBranches:
+		return tr("Entrée changer de branche · m fusionner · Tab panneau · r rafraîchir · q quitter")
+	default:
+		return tr("Entrée détails du commit · Tab panneau · r rafraîchir · q quitter")
+	}
+}
+
+func (s *tuiScreen) handle(key string) {
+	pane := &s.panes[s.focus]
+	page := s.height/2 - 2
+	switch key {
+	case keyUp, "k":
+		pane.move(-1)
+	case keyDown, "j":
+		pane.move(1)
+	case keyPageUp:
+		pane.move(-page)
+	case keyPageDown:
+		pane.move(page)
+	case keyHome, "g":
+		pane.move(-len(pane.Lines))
+	case keyEnd, "G":
+		pane.move(len(pane.Lines))
+	case keyTab, keyRight, "l":
+		s.focus = (s.focus + 1) % len(s.panes)
+	case keyLeft, "h":
+		s.focus = (s.focus + len(s.panes) - 1) % len(s.panes)
+	case "r":
+		s.refresh()
+		s.message = tr("🔄 Rafraîchi")
+	default:
+		switch s.focus {
+		case paneStatus:
+			s.statusAction(key)
+		case paneBranches:
+			s.branchAction(key)
+		case paneLog:
+			s.logAction(key)
+		}
+	}
+}
+
+func (s *tuiScreen) statusAction(key string) {
+	ga := s.ga
+	cursor := s.panes[paneStatus].Cursor
+	switch key {
+	case " ", "s":
+		if cursor < len(s.status) {
+			s.perform(func() error { return ga.toggleEntries(s.status, strconv.Itoa(cursor+1)) })
+		}
+	case "a":
+		if len(s.status) > 0 {
+			s.perform(func() error { return ga.toggleEntries(s.status, ".") })
+		}
+	case "c":
+		message, ok := s.prompt("💬 Message: ")
+		if ok && message != "" {
+			s.perform(func() error { return ga.commitSelectionWithMessage(message) })
+		}
+	case keyEnter, "d":
+		if cursor >= len(s.status) {
+			return
+		}
+		entry := s.status[cursor]
+		if entry.Index == '?' {
+			s.message = tr("fichier non suivi: indexez-le en entier")
+			return
+		}
+		text, err := s.capture(func() error {
+			// Partie indexée puis partie non indexée
+			for _, cached := range []bool{true, false} {
+				diff, err := ga.backend.Diff(entry.Path, cached)
+				if err != nil {
+					return err
+				}
+				if diff != "" {
+					ga.displayColoredDiff(diff)
+				}
+			}
+			return nil
+		})
+		s.showPager(entry.Path, text, err)
+	}
+}
+
+func (s *tuiScreen) branchAction(key string) {
+	ga := s.ga
+	cursor := s.panes[paneBranches].Cursor
+	if cursor >= len(s.branches) {
+		return
+	}
+	branch := s.branches[cursor]
+	switch key {
+	case keyEnter, "o":
+		if !branch.Current {
+			s.perform(func() error { return ga.switchBranch(branch.Name) })
+		}
+	case "m":
+		if branch.Current {
+			s.message = tr("❌ Choisissez une autre branche que la branche courante")
+			return
+		}
+		question := fmt.Sprintf(tr("🔀 Fusionner '%s' dans la branche courante? (%s) "), branch.Name, yesNoHint(false))
+		if answer, ok := s.prompt(question); ok && isYes(answer) {
+			s.perform(func() error { return ga.mergeInto(branch.Name, ga.config.MergeStrategy) })
+		}
+	}
+}
+
+func (s *tuiScreen) logAction(key string) {
+	cursor := s.panes[paneLog].Cursor
+	if cursor >= len(s.commits) || (key != keyEnter && key != "d") {
+		return
+	}
+	commit := s.commits[cursor]
+	text, err := s.capture(func() error { return s.ga.printCommitDetails(commit.Hash) })
+	s.showPager(commit.ShortHash+" "+commit.Subject, text, err)
+}
+
+// Exécute une action existante de GitAssistant en gardant sa dernière ligne
+// de sortie comme résultat, puis rafraîchit les panneaux
+func (s *tuiScreen) perform(action func() error) {
+	output, err := s.capture(action)
+	s.message = ""
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if last := lines[len(lines)-1]; last != "" {
+		s.message = last
+	}
+	if err != nil {
+		s.fail(err)
+	}
+	s.refresh()
+}
+
+func (s *tuiScreen) fail(err error) {
+	s.message = red(strings.TrimSpace(fmt.Sprintf(tr("❌ Erreur: %v\n"), err)))
+}
+
+func (s *tuiScreen) capture(action func() error) (string, error) {
+	var captured bytes.Buffer
+	out := s.ga.out
+	s.ga.out = &captured
+	err := action()
+	s.ga.out = out
+	return captured.String(), err
+}
+
+// Saisie sur la ligne de résultat; Échap annule
+func (s *tuiScreen) prompt(label string) (string, bool) {
+	var input []rune
+	for {
+		s.draw(cyan(label) + string(input) + "█")
+		switch key := readKey(s.keys); key {
+		case keyEnter:
+			return strings.TrimSpace(string(input)), true
+		case keyEscape, keyQuit:
+			s.message = ""
+			return "", false
+		case keyBackspace:
+			if len(input) > 0 {
+				input = input[:len(input)-1]
+			}
+		default:
+			if utf8.RuneCountInString(key) == 1 {
+				input = append(input, []rune(key)...)
+			}
+		}
+	}
+}
+
+// Affiche un texte long (diff, détails d'un commit) en plein écran
+func (s *tuiScreen) showPager(title, text string, err error) {
+	if err != nil {
+		s.fail(err)
+		return
+	}
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(text, "\t", "    "), "\n"), "\n")
+	offset := 0
+	for {
+		s.width, s.height = terminalSize(s.ga.in)
+		rows := s.height - 2
+		if offset > len(lines)-rows {
+			offset = len(lines) - rows
+		}
+		if offset < 0 {
+			offset = 0
+		}
+		
+		var frame strings.Builder
+		frame.WriteString("\033[H")
+		frame.WriteString(bold(fitWidth(title, s.width)) + "\n")
+		for i := offset; i < offset+rows; i++ {
+			line := ""
+			if i < len(lines) {
+				line = lines[i]
+			}
+			frame.WriteString(fitWidth(line, s.width) + "\n")
+		}
+		frame.WriteString(cyan(fitWidth(tr("↑↓ PgUp PgDn défiler · q retour"), s.width)))
+		frame.WriteString("\033[J")
+		fmt.Fprint(s.ga.out, frame.String())
+		
+		switch readKey(s.keys) {
+		case keyUp, "k":
+			offset--
+		case keyDown, "j", keyEnter:
+			offset++
+		case keyPageUp:
+			offset -= rows
+		case keyPageDown, " ":
+			offset += rows
+		case keyHome, "g":
+			offset = 0
+		case keyEnd, "G":
+			offset = len(lines)
+		case "q", keyEscape, keyQuit:
+			return
+		}
+	}
+}
+
+func readKey(keys *bufio.Reader) string {
+	r, _, err := keys.ReadRune()
+	if err != nil {
+		return keyQuit
+	}
+	switch r {
+	case '\r', '\n':
+		return keyEnter
+	case '\t':
+		return keyTab
+	case 127, '\b':
+		return keyBackspace
+	case 3:
+		return keyQuit
+	case 27:
+		// Séquence ESC [ x envoyée d'un bloc par le terminal; ESC seul sinon
+		if keys.Buffered() == 0 {
+			return keyEscape
+		}
+		if next, _, _ := keys.ReadRune(); next != '[' && next != 'O' {
+			return keyEscape
+		}
+		sequence := ""
+		for {
+			c, _, err := keys.ReadRune()
+			if err != nil {
+				break
+			}
+			sequence += string(c)
+			if c == '~' || (c >= 'A' && c <= 'Z') {
+				break
+			}
+		}
+		return escapeSequenceKeys[sequence]
+	}
+	return string(r)
+}
+
+var escapeSequenceKeys = map[string]string{
+	"A": keyUp, "B": keyDown, "C": keyRight, "D": keyLeft,
+	"5~": keyPageUp, "6~": keyPageDown,
+	"H": keyHome, "1~": keyHome, "F": keyEnd, "4~": keyEnd,
+}
+
+// Passe le terminal en mode caractère sans écho; retourne la fonction de restauration
+// et false si l'entrée n'est pas un terminal
+func enterRawMode(in io.Reader) (func(), bool) {
+	tty, ok := in.(*os.File)
+	if !ok {
+		return func() {}, false
+	}
+	state, err := stty(tty, "-g")
+	if err != nil {
+		return func() {}, false
+	}
+	if _, err := stty(tty, "-icanon", "-echo", "-isig", "min", "1"); err != nil {
+		return func() {}, false
+	}
+	return func() { stty(tty, strings.TrimSpace(state)) }, true
+}
+
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+	output, err := cmd.Output()
+	return string(output), err
+}
+
+// Taille du terminal: stty size, sinon COLUMNS et LINES, sinon 100x30
+func terminalSize(in io.Reader) (int, int) {
+	width, height := 100, 30
+	if tty, ok := in.(*os.File); ok {
+		if output, err := stty(tty, "size"); err == nil {
+			fmt.Sscan(output, &height, &width)
+		}
+	} else {
+		if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil {
+			width = columns
+		}
+		if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil {
+			height = lines
+		}
+	}
+	if width < 60 {
+		width = 60
+	}
+	if height < 16 {
+		height = 16
+	}
+	return width, height
+}
+
+// Largeur affichée: séquences ANSI ignorées, emoji et idéogrammes sur deux colonnes
+func displayWidth(text string) int {
+	width, inEscape := 0, false
+	for _, r := range text {
+		switch {
+		case inEscape:
+			inEscape = r != 'm'
+		case r == '\033':
+			inEscape = true
+		default:
+			width += runeWidth(r)
+		}
+	}
+	return width
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r == 0xFE0F || r == 0x200D || unicode.Is(unicode.Mn, r):
+		return 0
+	case r >= 0x1F300 && r <= 0x1FAFF, r >= 0x1100 && r <= 0x115F, r >= 0x2E80 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3, r >= 0xF900 && r <= 0xFAFF, r >= 0xFF00 && r <= 0xFF60,
+		strings.ContainsRune("⌛⏩⏪⏸⚡⚠✅✨❌❓❗⭐", r):
+		return 2
+	}
+	return 1
+}
+
+// Tronque (avec …) ou complète avec des espaces pour occuper exactement width colonnes
+func fitWidth(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if used := displayWidth(text); used <= width {
+		return text + strings.Repeat(" ", width-used)
+	}
+	
+	var b strings.Builder
+	used, inEscape, colored := 0, false, false
+	for _, r := range text {
+		if inEscape || r == '\033' {
+			inEscape = r != 'm'
+			colored = true
+			b.WriteRune(r)
+			continue
+		}
+		if used+runeWidth(r) > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += runeWidth(r)
+	}
+	b.WriteString("…")
+	if colored && colorsEnabled {
+		b.WriteString(ColorReset)
+	}
+	return b.String() + strings.Repeat(" ", width-used-1)
+}
+
+// Sélecteur flou: la frappe filtre la liste, les flèches déplacent, Entrée choisit.
+// Hors terminal (scripts, tests), une ligne est lue: nom exact, sinon préfixe unique.
+
+type pickerItem struct {
+	Key     string // valeur retournée: nom de branche, hash abrégé
+	Label   string // ligne affichée et filtrée
+	Preview func() string
+}
+
+// Choisit une branche ou un commit. Le texte saisi est retourné tel quel s'il ne
+// correspond à aucun élément (HEAD~2, origin/main...); vide si l'utilisateur annule.
+func (ga *GitAssistant) pick(prompt string, items []pickerItem) string {
+	if restore, ok := enterRawMode(ga.in); ok {
+		defer restore()
+		return ga.pickInteractive(prompt, items)
+	}
+	fmt.Fprint(ga.out, prompt)
+	return resolvePick(items, ga.getUserInput())
+}
+
+func resolvePick(items []pickerItem, input string) string {
+	if input == "" {
+		return ""
+	}
+	var matched []string
+	for _, item := range items {
+		if item.Key == input {
+			return input
+		}
+		if strings.HasPrefix(item.Key, input) {
+			matched = append(matched, item.Key)
+		}
+	}
+	if len(matched) == 1 {
+		return matched[0]
+	}
+	return input
+}
+
+func (ga *GitAssistant) pickInteractive(prompt string, items []pickerItem) string {
+	fmt.Fprint(ga.out, "\033[?1049h\033[?25l")
+	defer fmt.Fprint(ga.out, "\033[?25h\033[?1049l")
+	
+	keys := bufio.NewReader(ga.in)
+	prompt = strings.TrimSpace(prompt)
+	list := tuiPane{Title: strings.TrimSuffix(stripANSI(prompt), ":")}
+	preview := tuiPane{Title: tr("Aperçu"), Cursor: -1}
+	previews := make(map[string][]string)
+	var query []rune
+	
+	for {
+		matches := filterPickerItems(items, string(query))
+		list.Lines = nil
+		for _, item := range matches {
+			list.Lines = append(list.Lines, item.Label)
+		}
+		list.clamp()
+		
+		// Aperçu de l'élément sélectionné, calculé une seule fois
+		preview.Lines = nil
+		if len(matches) > 0 {
+			item := matches[list.Cursor]
+			if _, ok := previews[item.Key]; !ok && item.Preview != nil {
+				text := strings.ReplaceAll(strings.TrimRight(item.Preview(), "\n"), "\t", "    ")
+				previews[item.Key] = strings.Split(text, "\n")
+			}
+			preview.Lines = previews[item.Key]
+		}
+		
+		width, height := terminalSize(ga.in)
+		listWidth := width * 2 / 5
+		left := list.render(listWidth, height-2, true)
+		right := preview.render(width-listWidth, height-2, false)
+		var frame strings.Builder
+		frame.WriteString("\033[H")
+		frame.WriteString(fitWidth(cyan(prompt)+" "+string(query)+"█", width) + "\n")
+		for i := range left {
+			frame.WriteString(left[i] + right[i] + "\n")
+		}
+		frame.WriteString(cyan(fitWidth(tr("Tapez pour filtrer · ↑↓ choisir · Entrée valider · Échap annuler"), width)))
+		frame.WriteString("\033[J")
+		fmt.Fprint(ga.out, frame.String())
+		
+		switch key := readKey(keys); key {
+		case keyEnter:
+			if len(matches) > 0 {
+				return matches[list.Cursor].Key
+			}
+			return strings.TrimSpace(string(query))
+		case keyEscape, keyQuit:
+			return ""
+		case keyUp:
+			list.move(-1)
+		case keyDown, keyTab:
+			list.move(1)
+		case keyPageUp:
+			list.move(-(height - 4))
+		case keyPageDown:
+			list.move(height - 4)
+		case keyBackspace:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				list.Cursor = 0
+			}
+		default:
+			if r, size := utf8.DecodeRuneInString(key); size == len(key) && unicode.IsPrint(r) {
+				query = append(query, r)
+				list.Cursor = 0
+			}
+		}
+	}
+}
+
+// Éléments correspondant à la requête, les meilleurs d'abord; ordre d'origine si elle est vide
+func filterPickerItems(items []pickerItem, query string) []pickerItem {
+	type scoredItem struct {
+		item  pickerItem
+		score int
+	}
+	var scored []scoredItem
+	for _, item := range items {
+		if score, ok := fuzzyScore(query, item.Label); ok {
+			scored = append(scored, scoredItem{item, score})
+		}
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
+	
+	matches := make([]pickerItem, len(scored))
+	for i, s := range scored {
+		matches[i] = s.item
+	}
+	return matches
+}
+
+// Les lettres de la requête doivent apparaître dans l'ordre, sans tenir compte de la casse.
+// Les lettres consécutives et les débuts de mot (après /, -, _, espace) comptent davantage.
+func fuzzyScore(query, text string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(text))
+	score, qi, previous := 0, 0, -2
+	for i, r := range t {
+		if qi == len(q) {
+			break
+		}
+		if r != q[qi] {
+			continue
+		}
+		score++
+		if i == previous+1 {
+			score += 3
+		}
+		if i == 0 || strings.ContainsRune(" /-_.(", t[i-1]) {
+			score += 2
+		}
+		previous = i
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// À score égal, le texte le plus court l'emporte
+	return score*1000 - len(t), true
+}
+
+var ansiPattern = regexp.MustCompile("\033\\[[0-9;]*m")
+
+func stripANSI(text string) string {
+	return ansiPattern.ReplaceAllString(text, "")
+}
+
+// Branches locales au format de git branch -v, avec leurs derniers commits en aperçu
+func (ga *GitAssistant) branchItems(includeCurrent bool) []pickerItem {
+	branches, err := ga.backend.Branches()
+	if err != nil {
+		return nil
+	}
+	var items []pickerItem
+	for i, line := range formatBranchLines(branches) {
+		branch := branches[i]
+		if branch.Current && !includeCurrent {
+			continue
+		}
+		items = append(items, pickerItem{Key: branch.Name, Label: line, Preview: func() string {
+			commits, _ := ga.backend.LogFrom(branch.Name, 20)
+			return strings.Join(formatLogLines(commits), "\n")
+		}})
+	}
+	return items
+}
+
+// Commits récents au format de git log --oneline, avec leurs statistiques en aperçu
+func (ga *GitAssistant) commitItems() []pickerItem {
+	commits, err := ga.backend.Log(tuiLogDepth)
+	if err != nil {
+		return nil
+	}
+	items := make([]pickerItem, 0, len(commits))
+	for i, line := range formatLogLines(commits) {
+		commit := commits[i]
+		items = append(items, pickerItem{Key: commit.ShortHash, Label: line, Preview: func() string {
+			output, _ := ga.backend.ShowCommit(commit.Hash)
+			return output
+		}})
+	}
+	return items
+}
+
+// Configuration en couches: défauts, ~/.config/gitctrl/config.toml,
+// .gitctrl.toml à la racine du dépôt, puis variables d'environnement
+
+// Type de branche de travail proposé dans le menu des branches
+type branchType struct {
+	Name     string
+	Prefix   string
+	Label    string
+	Upstream bool // premier push avec --set-upstream automatique
+}
+
+type Config struct {
+	Backend       string
+	Presets       []commitPreset
+	BranchTypes   []branchType
+	LogDepth      int
+	ChurnDays     int
+	ResetMode     string
+	MergeStrategy string
+	Colors        bool
+	ClearScreen   bool
+	Pause         bool
+	Lang          string
+	
+	// Origine de chaque valeur: "défaut", chemin du fichier ou variable d'environnement
+	Sources map[string]string
+}
+
+const sourceDefault = "défaut"
+
+func defaultConfig() *Config {
+	return &Config{
+		Backend: backendAuto,
+		Presets: []commitPreset{
+			{"update", "🚀 Mise à jour rapide"},
+			{"bug", "🐛 Correction de bug"},
+			{"feature", "✨ Nouvelle fonctionnalité"},
+			{"docs", "📝 Documentation"},
+			{"refactor", "♻️ Refactoring"},
+			{"ui", "🎨 Améliorations UI"},
+			{"perf", "⚡ Performance"},
+			{"config", "🔧 Configuration"},
+		},
+		BranchTypes: []branchType{
+			{"feature", "feature/", "🌱 Créer branche de fonctionnalité", true},
+			{"bugfix", "bugfix/", "🐛 Créer branche de correction", true},
+		},
+		LogDepth:      15,
+		ChurnDays:     90,
+		ResetMode:     "mixed",
+		MergeStrategy: mergeDefault,
+		Colors:        true,
+		ClearScreen:   true,
+		Pause:         true,
+		Lang:          "auto",
+		Sources:       make(map[string]string),
+	}
+}
+
+// Paramètre simple, lisible et modifiable sous forme de texte
+type configSetting struct {
+	Key  string // table.clé, ou clé seule pour la racine
+	Env  string
+	Kind string // string, int ou bool
+	Help string
+	get  func(cfg *Config) string
+	set  func(cfg *Config, value string) error
+}
+
+var configSettings = []configSetting{
+	{
+		Key: "backend", Env: "GITCTRL_BACKEND", Kind: "string", Help: "auto, exec ou native",
+		get: func(cfg *Config) string { return cfg.Backend },
+		set: func(cfg *Config, value string) error {
+			switch value {
+			case backendAuto, backendExec, backendNative:
+				cfg.Backend = value
+				return nil
+			}
+			return fmt.Errorf(tr("backend inconnu: %s (auto, exec ou native)"), value)
+		},
+	},
+	{
+		Key: "log.depth", Env: "GITCTRL_LOG_DEPTH", Kind: "int", Help: "commits affichés dans l'historique",
+		get: func(cfg *Config) string { return strconv.Itoa(cfg.LogDepth) },
+		set: func(cfg *Config, value string) error {
+			depth, err := strconv.Atoi(value)
+			if err != nil || depth < 1 || depth > 1000 {
+				return fmt.Errorf(tr("nombre entre 1 et 1000 attendu: %s"), value)
+			}
+			cfg.LogDepth = depth
+			return nil
+		},
+	},
+	{
+		Key: "insights.churn_days", Env: "GITCTRL_CHURN_DAYS", Kind: "int", Help: "jours des points chauds (0: tout l'historique)",
+		get: func(cfg *Config) string { return strconv.Itoa(cfg.ChurnDays) },
+		set: func(cfg *Config, value string) error {
+			days, err := strconv.Atoi(value)
+			if err != nil || days < 0 || days > 36500 {
+				return fmt.Errorf(tr("nombre entre 0 et 36500 attendu: %s"), value)
+			}
+			cfg.ChurnDays = days
+			return nil
+		},
+	},
+	{
+		Key: "reset.mode", Env: "GITCTRL_RESET_MODE", Kind: "string", Help: "soft, mixed ou hard",
+		get: func(cfg *Config) string { return cfg.ResetMode },
+		set: func(cfg *Config, value string) error {
+			switch value {
+			case "soft", "mixed", "hard":
+				cfg.ResetMode = value
+				return nil
+			}
+			return fmt.Errorf(tr("mode de reset inconnu: %s (soft, mixed ou hard)"), value)
+		},
+	},
+	{
+		Key: "merge.strategy", Env: "GITCTRL_MERGE_STRATEGY", Kind: "string", Help: "merge, ff-only, no-ff, squash ou rebase",
+		get: func(cfg *Config) string { return cfg.MergeStrategy },
+		set: func(cfg *Config, value string) error {
+			if isMergeStrategy(value) {
+				cfg.MergeStrategy = value
+				return nil
+			}
+			return fmt.Errorf(tr("stratégie de fusion inconnue: %s (merge, ff-only, no-ff, squash ou rebase)"), value)
+		},
+	},
+	{
+		Key: "ui.colors", Env: "GITCTRL_COLORS", Kind: "bool", Help: "couleurs ANSI",
+		get: func(cfg *Config) string { return strconv.FormatBool(cfg.Colors) },
+		set: func(cfg *Config, value string) (err error) {
+			cfg.Colors, err = parseBoolSetting(value)
+			return err
+		},
+	},
+	{
+		Key: "ui.clear_screen", Env: "GITCTRL_CLEAR_SCREEN", Kind: "bool", Help: "effacer l'écran avant le menu",
+		get: func(cfg *Config) string { return strconv.FormatBool(cfg.ClearScreen) },
+		set: func(cfg *Config, value string) (err error) {
+			cfg.ClearScreen, err = parseBoolSetting(value)
+			return err
+		},
+	},
+	{
+		Key: "ui.pause", Env: "GITCTRL_PAUSE", Kind: "bool", Help: "attendre Entrée après chaque action",
+		get: func(cfg *Config) string { return strconv.FormatBool(cfg.Pause) },
+		set: func(cfg *Config, value string) (err error) {
+			cfg.Pause, err = parseBoolSetting(value)
+			return err
+		},
+	},
+	{
+		Key: "ui.lang", Env: "GITCTRL_LANG", Kind: "string", Help: "auto (LANG), fr ou en",
+		get: func(cfg *Config) string { return cfg.Lang },
+		set: func(cfg *Config, value string) error {
+			if _, ok := catalogs[value]; ok || value == "auto" {
+				cfg.Lang = value
+				return nil
+			}
+			return fmt.Errorf(tr("langue inconnue: %s (auto, fr ou en)"), value)
+		},
+	},
+}
+
+func findConfigSetting(key string) (configSetting, bool) {
+	for _, setting := range configSettings {
+		if setting.Key == key {
+			return setting, true
+		}
+	}
+	return configSetting{}, false
+}
+
+func parseBoolSetting(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "1", "yes", "oui", "on":
+		return true, nil
+	case "false", "0", "no", "non", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf(tr("booléen attendu: %s"), value)
+}
+
+// Sépare "log.depth" en table et clé; la racine est la table ""
+func splitSettingKey(key string) (string, string) {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return "", key
+}
+
+// Fichier global: GITCTRL_CONFIG, sinon $XDG_CONFIG_HOME ou ~/.config
+func globalConfigPath() string {
+	if path := os.Getenv("GITCTRL_CONFIG"); path != "" {
+		return path
+	}
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "gitctrl", "config.toml")
+}
+
+// Racine du dépôt contenant dir (dossier avec .git), ou dir lui-même
+func findRepoRoot(dir string) string {
+	for current := dir; ; {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return current
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dir
+		}
+		current = parent
+	}
+}
+
+// Répertoire Git d'un arbre de travail et répertoire commun à ses worktrees
+func gitDirs(workDir string) (string, string, error) {
+	gitDir := filepath.Join(workDir, ".git")
+	info, err := os.Stat(gitDir)
+	if err != nil {
+		return "", "", fmt.Errorf(tr("%s n'est pas un dépôt Git"), workDir)
+	}
+	if !info.IsDir() {
+		// Fichier .git des worktrees et sous-modules: "gitdir: <chemin>"
+		data, err := os.ReadFile(gitDir)
+		if err != nil {
+			return "", "", err
+		}
+		target := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(data)), "gitdir:"))
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(workDir, target)
+		}
+		gitDir = target
+	}
+	
+	commonDir := gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+	return gitDir, commonDir, nil
+}
+
+func repoConfigPath(dir string) string {
+	return filepath.Join(findRepoRoot(dir), ".gitctrl.toml")
+}
+
+// Charge les fichiers dans l'ordre (les suivants l'emportent) puis l'environnement.
+// Un fichier invalide est ignoré; les erreurs sont retournées pour affichage.
+func loadConfig(files []string, getenv func(string) string) (*Config, []error) {
+	cfg := defaultConfig()
+	var errs []error
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		doc, err := parseTOML(string(data))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", file, err))
+			continue
+		}
+		errs = append(errs, cfg.apply(doc, file)...)
+	}
+	
+	for _, setting := range configSettings {
+		value := getenv(setting.Env)
+		if value == "" {
+			continue
+		}
+		if err := setting.set(cfg, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", setting.Env, err))
+			continue
+		}
+		cfg.Sources[setting.Key] = setting.Env
+	}
+	// Convention https://no-color.org
+	if getenv("NO_COLOR") != "" {
+		cfg.Colors = false
+		cfg.Sources["ui.colors"] = "NO_COLOR"
+	}
+	return cfg, errs
+}
+
+// Applique un document TOML; les tableaux presets et branch_types remplacent
+// entièrement ceux des couches précédentes
+func (cfg *Config) apply(doc *tomlDocument, source string) []error {
+	var errs []error
+	known := make(map[string]bool)
+	for _, setting := range configSettings {
+		known[setting.Key] = true
+		table, key := splitSettingKey(setting.Key)
+		value, ok := doc.Tables[table][key]
+		if !ok {
+			continue
+		}
+		text, ok := tomlScalarString(value)
+		if !ok {
+			errs = append(errs, fmt.Errorf(tr("%s: %s: valeur simple attendue"), source, setting.Key))
+			continue
+		}
+		if err := setting.set(cfg, text); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %v", source, setting.Key, err))
+			continue
+		}
+		cfg.Sources[setting.Key] = source
+	}
+	
+	tables := make([]string, 0, len(doc.Tables))
+	for table := range doc.Tables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		keys := make([]string, 0, len(doc.Tables[table]))
+		for key := range doc.Tables[table] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			full := key
+			if table != "" {
+				full = table + "." + key
+			}
+			if !known[full] {
+				errs = append(errs, fmt.Errorf(tr("%s: clé inconnue '%s'"), source, full))
+			}
+		}
+	}
+	
+	for name, items := range doc.ArrayTables {
+		switch name {
+		case "presets":
+			var presets []commitPreset
+			for i, item := range items {
+				preset := commitPreset{Name: tomlString(item, "name"), Message: tomlString(item, "message")}
+				if preset.Name == "" || preset.Message == "" {
+					errs = append(errs, fmt.Errorf(tr("%s: presets n°%d: name et message requis"), source, i+1))
+					continue
+				}
+				presets = append(presets, preset)
+			}
+			if len(presets) > 0 {
+				cfg.Presets = presets
+				cfg.Sources["presets"] = source
+			}
+		case "branch_types":
+			var types []branchType
+			for i, item := range items {
+				bt := branchType{Name: tomlString(item, "name"), Prefix: tomlString(item, "prefix"), Label: tomlString(item, "label")}
+				bt.Upstream, _ = item["upstream"].(bool)
+				if bt.Name == "" || bt.Prefix == "" {
+					errs = append(errs, fmt.Errorf(tr("%s: branch_types n°%d: name et prefix requis"), source, i+1))
+					continue
+				}
+				if bt.Label == "" {
+					bt.Label = fmt.Sprintf(tr("🌿 Créer branche %s"), bt.Name)
+				}
+				types = append(types, bt)
+			}
+			if len(types) > 0 {
+				cfg.BranchTypes = types
+				cfg.Sources["branch_types"] = source
+			}
+		default:
+			errs = append(errs, fmt.Errorf(tr("%s: tableau inconnu [[%s]]"), source, name))
+		}
+	}
+	return errs
+}
+
+func (cfg *Config) source(key string) string {
+	if source, ok := cfg.Sources[key]; ok {
+		return source
+	}
+	return tr(sourceDefault)
+}
+
+func (cfg *Config) branchType(name string) (branchType, bool) {
+	for _, bt := range cfg.BranchTypes {
+		if bt.Name == name {
+			return bt, true
+		}
+	}
+	return branchType{}, false
+}
+
+// Sous-ensemble de TOML: tables, tableaux de tables, chaînes, entiers,
+// booléens et tableaux de chaînes sur une ligne
+
+type tomlDocument struct {
+	Tables      map[string]map[string]interface{}
+	ArrayTables map[string][]map[string]interface{}
+}
+
+var tomlKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func parseTOML(data string) (*tomlDocument, error) {
+	doc := &tomlDocument{
+		Tables:      map[string]map[string]interface{}{"": {}},
+		ArrayTables: make(map[string][]map[string]interface{}),
+	}
+	current := doc.Tables[""]
+	
+	for n, raw := range strings.Split(data, "\n") {
+		line := strings.TrimSpace(stripTOMLComment(raw))
+		if line == "" {
+			continue
+		}
+		
+		if name, isArray, ok := tomlHeader(line); ok {
+			table := make(map[string]interface{})
+			if isArray {
+				doc.ArrayTables[name] = append(doc.ArrayTables[name], table)
+			} else {
+				if _, exists := doc.Tables[name]; exists {
+					return nil, fmt.Errorf(tr("ligne %d: table [%s] définie deux fois"), n+1, name)
+				}
+				doc.Tables[name] = table
+			}
+			current = table
+			continue
+		} else if strings.HasPrefix(line, "[") {
+			return nil, fmt.Errorf(tr("ligne %d: en-tête de table invalide"), n+1)
+		}
+		
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || !tomlKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf(tr("ligne %d: 'clé = valeur' attendu"), n+1)
+		}
+		if _, exists := current[key]; exists {
+			return nil, fmt.Errorf(tr("ligne %d: clé '%s' définie deux fois"), n+1, key)
+		}
+		parsed, err := parseTOMLValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf(tr("ligne %d: %v"), n+1, err)
+		}
+		current[key] = parsed
+	}
+	return doc, nil
+}
+
+// Nom d'un en-tête [table] ou [[tableau]]
+func tomlHeader(line string) (string, bool, bool) {
+	if strings.HasPrefix(line, "[[") && strings.HasSuffix(line, "]]") {
+		name := strings.TrimSpace(line[2 : len(line)-2])
+		return name, true, name != "" && !strings.ContainsAny(name, "[]")
+	}
+	if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+		name := strings.TrimSpace(line[1 : len(line)-1])
+		return name, false, name != "" && !strings.ContainsAny(name, "[]")
+	}
+	return "", false, false
+}
+
+// Retire le commentaire de fin de ligne, hors des chaînes
+func stripTOMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func parseTOMLValue(text string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(text, `"`):
+		value, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf(tr("chaîne invalide: %s"), text)
+		}
+		return value, nil
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") || strings.Contains(text[1:len(text)-1], "'") {
+			return nil, fmt.Errorf(tr("chaîne invalide: %s"), text)
+		}
+		return text[1 : len(text)-1], nil
+	case text == "true" || text == "false":
+		return text == "true", nil
+	case strings.HasPrefix(text, "["):
+		if !strings.HasSuffix(text, "]") {
+			return nil, fmt.Errorf(tr("tableau invalide: %s"), text)
+		}
+		items := []string{}
+		for _, item := range splitTOMLArray(text[1 : len(text)-1]) {
+			value, err := parseTOMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			str, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf(tr("seuls les tableaux de chaînes sont acceptés: %s"), text)
+			}
+			items = append(items, str)
+		}
+		return items, nil
+	}
+	number, err := strconv.ParseInt(strings.ReplaceAll(text, "_", ""), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf(tr("valeur invalide: %s"), text)
+	}
+	return number, nil
+}
+
+// Éléments d'un tableau séparés par des virgules hors des chaînes
+func splitTOMLArray(inner string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, inner[start:i])
+			start = i + 1
+		}
+	}
+	items = append(items, inner[start:])
+	
+	var trimmed []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			trimmed = append(trimmed, item)
+		}
+	}
+	return trimmed
+}
+
+func tomlScalarString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+func tomlString(table map[string]interface{}, key string) string {
+	value, _ := table[key].(string)
+	return value
+}
+
+// Valeur écrite dans le fichier selon le type du paramètre
+func tomlLiteral(kind, value string) string {
+	if kind == "string" {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// Édition du fichier en place: les commentaires et l'ordre des lignes
+// existantes sont conservés
+
+func splitTOMLLines(content string) []string {
+	content = strings.TrimRight(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+func joinTOMLLines(lines []string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Lignes [start, end) du contenu d'une table; la racine précède le premier en-tête
+func findTOMLTable(lines []string, table string) (int, int, bool) {
+	start, inside := 0, table == ""
+	for i, line := range lines {
+		name, isArray, ok := tomlHeader(strings.TrimSpace(stripTOMLComment(line)))
+		if !ok {
+			continue
+		}
+		if inside {
+			return start, i, true
+		}
+		if !isArray && name == table {
+			start, inside = i+1, true
+		}
+	}
+	if inside {
+		return start, len(lines), true
+	}
+	return 0, 0, false
+}
+
+func tomlLineKey(line string) string {
+	key, _, found := strings.Cut(stripTOMLComment(line), "=")
+	if !found {
+		return ""
+	}
+	return strings.TrimSpace(key)
+}
+
+func setTOMLValue(content, fullKey, literal string) string {
+	table, key := splitSettingKey(fullKey)
+	lines := splitTOMLLines(content)
+	keyLine := key + " = " + literal
+	
+	start, end, found := findTOMLTable(lines, table)
+	if !found {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		return joinTOMLLines(append(lines, "["+table+"]", keyLine))
+	}
+	
+	insertAt := start
+	for i := start; i < end; i++ {
+		if tomlLineKey(lines[i]) == key {
+			lines[i] = keyLine
+			return joinTOMLLines(lines)
+		}
+		if strings.TrimSpace(lines[i]) != "" {
+			insertAt = i + 1
+		}
+	}
+	lines = append(lines[:insertAt], append([]string{keyLine}, lines[insertAt:]...)...)
+	return joinTOMLLines(lines)
+}
+
+func removeTOMLValue(content, fullKey string) string {
+	table, key := splitSettingKey(fullKey)
+	lines := splitTOMLLines(content)
+	start, end, found := findTOMLTable(lines, table)
+	if !found {
+		return content
+	}
+	for i := start; i < end; i++ {
+		if tomlLineKey(lines[i]) == key {
+			return joinTOMLLines(append(lines[:i], lines[i+1:]...))
+		}
+	}
+	return content
+}
+
+// Remplace tous les blocs [[name]] par les blocs fournis, ajoutés en fin de fichier
+func replaceTOMLArray(content, name string, blocks [][]string) string {
+	var kept []string
+	skipping := false
+	for _, line := range splitTOMLLines(content) {
+		if header, isArray, ok := tomlHeader(strings.TrimSpace(stripTOMLComment(line))); ok {
+			skipping = isArray && header == name
+		}
+		if !skipping {
+			kept = append(kept, line)
+		}
+	}
+	for len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+		kept = kept[:len(kept)-1]
+	}
+	
+	for _, block := range blocks {
+		if len(kept) > 0 {
+			kept = append(kept, "")
+		}
+		kept = append(kept, "[["+name+"]]")
+		kept = append(kept, block...)
+	}
+	if len(kept) == 0 {
+		return ""
+	}
+	return joinTOMLLines(kept)
+}
+
+func presetBlocks(presets []commitPreset) [][]string {
+	blocks := make([][]string, 0, len(presets))
+	for _, preset := range presets {
+		blocks = append(blocks, []string{"name = " + strconv.Quote(preset.Name), "message = " + strconv.Quote(preset.Message)})
+	}
+	return blocks
+}
+
+func branchTypeBlocks(types []branchType) [][]string {
+	blocks := make([][]string, 0, len(types))
+	for _, bt := range types {
+		blocks = append(blocks, []string{
+			"name = " + strconv.Quote(bt.Name),
+			"prefix = " + strconv.Quote(bt.Prefix),
+			"label = " + strconv.Quote(bt.Label),
+			"upstream = " + strconv.FormatBool(bt.Upstream),
+		})
+	}
+	return blocks
+}
+
+// Lit, transforme et réécrit un fichier de configuration (créé si besoin)
+func editConfigFile(path string, edit func(content string) string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	updated := edit(string(data))
+	
+	// Ne pas enregistrer un fichier que le chargement refuserait
+	if _, err := parseTOML(updated); err != nil {
+		return fmt.Errorf(tr("configuration invalide après modification: %v"), err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, []byte(updated), 0644)
+}
+
+// Paramètres et écran de réglages
+
+func (ga *GitAssistant) configFiles() []string {
+	return []string{globalConfigPath(), repoConfigPath(ga.workingDir)}
+}
+
+// Recharge la configuration (au démarrage, au changement de répertoire, après édition)
+func (ga *GitAssistant) reloadConfig() {
+	cfg, errs := loadConfig(ga.configFiles(), os.Getenv)
+	for _, err := range errs {
+		fmt.Fprintf(ga.out, red(tr("⚠️ Configuration: %v\n")), err)
+	}
+	ga.config = cfg
+	ga.quickCommits = cfg.Presets
+	colorsEnabled = cfg.Colors
+	if !ga.localeForced {
+		currentLocale = resolveLocale(cfg.Lang, os.Getenv)
+	}
+	
+	if !ga.backendForced {
+		if backend, err := newGitBackend(cfg.Backend, ga.currentDir); err == nil {
+			ga.backend = backend
+		}
+	}
+}
+
+// Fichier à modifier: global, ou celui du dépôt
+func (ga *GitAssistant) chooseConfigFile() (string, bool) {
+	global, repo := globalConfigPath(), repoConfigPath(ga.workingDir)
+	fmt.Fprintf(ga.out, "1. 🌍 Global (%s)\n", global)
+	fmt.Fprintf(ga.out, tr("2. 📁 Dépôt (%s)\n"), repo)
+	fmt.Fprint(ga.out, cyan(tr("Enregistrer dans (1-2, défaut 2): ")))
+	switch ga.getUserInput() {
+	case "1":
+		return global, true
+	case "", "2":
+		return repo, true
+	}
+	fmt.Fprintln(ga.out, red(tr("❌ Choix invalide")))
+	return "", false
+}
+
+func (ga *GitAssistant) settingsScreen() error {
+	for {
+		fmt.Fprintf(ga.out, "\n⚙️ === %s ===\n", bold(tr("PARAMÈTRES")))
+		for _, path := range ga.configFiles() {
+			state := tr("absent")
+			if _, err := os.Stat(path); err == nil {
+				state = tr("présent")
+			}
+			fmt.Fprintf(ga.out, "📄 %s (%s)\n", path, state)
+		}
+		fmt.Fprintln(ga.out)
+		
+		for i, setting := range configSettings {
+			fmt.Fprintf(ga.out, "%d. %-16s %-8s %s (%s)\n", i+1, setting.Key, green(setting.get(ga.config)), tr(setting.Help), ga.config.source(setting.Key))
+		}
+		fmt.Fprintf(ga.out, tr("p. Messages prédéfinis (%d) (%s)\n"), len(ga.config.Presets), ga.config.source("presets"))
+		fmt.Fprintf(ga.out, tr("b. Types de branches (%d) (%s)\n"), len(ga.config.BranchTypes), ga.config.source("branch_types"))
+		fmt.Fprint(ga.out, cyan(tr("\nParamètre à modifier (Entrée pour quitter): ")))
+		
+		choice := ga.getUserInput()
+		var err error
+		switch choice {
+		case "":
+			return nil
+		case "p":
+			err = ga.editPresets()
+		case "b":
+			err = ga.editBranchTypes()
+		default:
+			idx, convErr := strconv.Atoi(choice)
+			if convErr != nil || idx < 1 || idx > len(configSettings) {
+				fmt.Fprintln(ga.out, red(tr("❌ Choix invalide")))
+				continue
+			}
+			err = ga.editSetting(configSettings[idx-1])
+		}
+		if err != nil {
+			return err
+		}
+		ga.reloadConfig()
+	}
+}
+
+func (ga *GitAssistant) editSetting(setting configSetting) error {
+	path, ok := ga.chooseConfigFile()
+	if !ok {
+		return nil
+	}
+	fmt.Fprintf(ga.out, tr("✏️ %s (%s), vide pour retirer du fichier: "), setting.Key, tr(setting.Help))
+	value := ga.getUserInput()
+	return ga.writeSetting(path, setting, value)
+}
+
+// Écrit une valeur validée, ou la retire du fichier si elle est vide
+func (ga *GitAssistant) writeSetting(path string, setting configSetting, value string) error {
+	if value == "" {
+		if err := editConfigFile(path, func(content string) string { return removeTOMLValue(content, setting.Key) }); err != nil {
+			return err
+		}
+		fmt.Fprintf(ga.out, tr("✅ %s retiré de %s\n"), setting.Key, path)
+		return nil
+	}
+	
+	if err := setting.set(defaultConfig(), value); err != nil {
+		return err
+	}
+	if setting.Kind == "bool" {
+		parsed, _ := parseBoolSetting(value)
+		value = strconv.FormatBool(parsed)
+	}
+	literal := tomlLiteral(setting.Kind, value)
+	if err := editConfigFile(path, func(content string) string { return setTOMLValue(content, setting.Key, literal) }); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ %s = %s enregistré dans %s\n"), setting.Key, value, path)
+	if setting.Env != "" && os.Getenv(setting.Env) != "" {
+		fmt.Fprintf(ga.out, tr("ℹ️ La variable %s reste prioritaire\n"), setting.Env)
+	}
+	return nil
+}
+
+func (ga *GitAssistant) editPresets() error {
+	presets := append([]commitPreset{}, ga.config.Presets...)
+	for i, preset := range presets {
+		fmt.Fprintf(ga.out, "%d. %-10s %s\n", i+1, preset.Name, tr(preset.Message))
+	}
+	fmt.Fprint(ga.out, cyan(tr("a pour ajouter, d <n°> pour supprimer: ")))
+	
+	command, arg, _ := strings.Cut(ga.getUserInput(), " ")
+	switch command {
+	case "a":
+		fmt.Fprint(ga.out, tr("🏷️ Nom court (pour --preset): "))
+		name := ga.getUserInput()
+		fmt.Fprint(ga.out, "💬 Message: ")
+		message := ga.getUserInput()
+		if name == "" || message == "" {
+			return errors.New(tr("nom et message requis"))
+		}
+		presets = append(presets, commitPreset{name, message})
+	case "d":
+		idx, err := strconv.Atoi(arg)
+		if err != nil || idx < 1 || idx > len(presets) || len(presets) == 1 {
+			return errors.New(tr("numéro invalide (au moins un message doit rester)"))
+		}
+		presets = append(presets[:idx-1], presets[idx:]...)
+	default:
+		return nil
+	}
+	
+	path, ok := ga.chooseConfigFile()
+	if !ok {
+		return nil
+	}
+	if err := editConfigFile(path, func(content string) string {
+		return replaceTOMLArray(content, "presets", presetBlocks(presets))
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ %d messages enregistrés dans %s\n"), len(presets), path)
+	return nil
+}
+
+func (ga *GitAssistant) editBranchTypes() error {
+	types := append([]branchType{}, ga.config.BranchTypes...)
+	for i, bt := range types {
+		fmt.Fprintf(ga.out, "%d. %-10s %-12s %s\n", i+1, bt.Name, bt.Prefix, tr(bt.Label))
+	}
+	fmt.Fprint(ga.out, cyan(tr("a pour ajouter, d <n°> pour supprimer: ")))
+	
+	command, arg, _ := strings.Cut(ga.getUserInput(), " ")
+	switch command {
+	case "a":
+		fmt.Fprint(ga.out, tr("🏷️ Nom (ex: hotfix): "))
+		name := ga.getUserInput()
+		fmt.Fprintf(ga.out, tr("🔤 Préfixe (défaut %s/): "), name)
+		prefix := ga.getUserInput()
+		if prefix == "" {
+			prefix = name + "/"
+		}
+		fmt.Fprint(ga.out, tr("📋 Libellé du menu: "))
+		label := ga.getUserInput()
+		if label == "" {
+			label = fmt.Sprintf(tr("🌿 Créer branche %s"), name)
+		}
+		upstream := ga.confirm(tr("🔗 Premier push avec --set-upstream?"), false)
+		if name == "" {
+			return errors.New(tr("nom requis"))
+		}
+		types = append(types, branchType{name, prefix, label, upstream})
+	case "d":
+		idx, err := strconv.Atoi(arg)
+		if err != nil || idx < 1 || idx > len(types) || len(types) == 1 {
+			return errors.New(tr("numéro invalide (au moins un type doit rester)"))
+		}
+		types = append(types[:idx-1], types[idx:]...)
+	default:
+		return nil
+	}
+	
+	path, ok := ga.chooseConfigFile()
+	if !ok {
+		return nil
+	}
+	if err := editConfigFile(path, func(content string) string {
+		return replaceTOMLArray(content, "branch_types", branchTypeBlocks(types))
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(ga.out, tr("✅ %d types de branches enregistrés dans %s\n"), len(types), path)
+	return nil
+}
+
+// Langues: le texte français des appels à tr sert de clé aux catalogues.
+// Le français est la langue source; une clé absente d'un catalogue reste en français.
+
+const (
+	localeFR = "fr"
+	localeEN = "en"
+)
+
+type messageCatalog struct {
+	Name     string
+	Yes      []string // réponses acceptées aux confirmations, la première est affichée
+	No       []string
+	Messages map[string]string
+}
+
+var catalogs = map[string]*messageCatalog{
+	localeFR: {Name: "Français", Yes: []string{"o", "oui"}, No: []string{"n", "non"}},
+	localeEN: {Name: "English", Yes: []string{"y", "yes"}, No: []string{"n", "no"}, Messages: messagesEN},
+}
+
+// Langue des messages, fixée par la configuration (ui.lang), LANG ou --lang
+var currentLocale = localeFR
+
+func tr(message string) string {
+	if translated, ok := catalogs[currentLocale].Messages[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Erreur traduite à l'affichage, pour les variables initialisées avant le choix de la langue
+type localizedError string
+
+func (e localizedError) Error() string {
+	return tr(string(e))
+}
+
+// Langue à utiliser: "auto" ou vide consulte LC_ALL, LC_MESSAGES puis LANG.
+// fr_FR.UTF-8 donne fr; une langue sans catalogue retombe sur le français.
+func resolveLocale(requested string, getenv func(string) string) string {
+	if requested == "" || requested == "auto" {
+		for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+			if value := getenv(name); value != "" {
+				requested = value
+				break
+			}
+		}
+	}
+	lang := strings.ToLower(requested)
+	if i := strings.IndexAny(lang, "_.@-"); i >= 0 {
+		lang = lang[:i]
+	}
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	return localeFR
+}
+
+// Indication des réponses, la réponse par défaut en majuscule: "o/N", "Y/n"
+func yesNoHint(defaultYes bool) string {
+	catalog := catalogs[currentLocale]
+	yes, no := catalog.Yes[0], catalog.No[0]
+	if defaultYes {
+		yes = strings.ToUpper(yes)
+	} else {
+		no = strings.ToUpper(no)
+	}
+	return yes + "/" + no
+}
+
+func isAnswer(answer string, accepted []string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	for _, candidate := range accepted {
+		if answer == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+func isYes(answer string) bool {
+	return isAnswer(answer, catalogs[currentLocale].Yes)
+}
+
+func isNo(answer string) bool {
+	return isAnswer(answer, catalogs[currentLocale].No)
+}
+
+// Catalogue anglais, dans l'ordre du fichier. Les libellés identiques dans les deux langues
+// (Push, Rebase, CONVENTIONAL COMMIT...) n'y figurent pas.
+var messagesEN = map[string]string{
+	// Statut
+	"Erreur lors de la récupération du répertoire courant:": "Error while reading the current directory:",
+	"📊 === STATUT INTELLIGENT ===":                          "📊 === SMART STATUS ===",
+	"🌿 HEAD détachée sur %s\n":                              "🌿 Detached HEAD at %s\n",
+	"🌿 Branche actuelle: %s\n":                              "🌿 Current branch: %s\n",
+	"🔗 Branche suivie: %s (%s)\n":                           "🔗 Upstream branch: %s (%s)\n",
+	"📁 Projet: %s\n":                                        "📁 Project: %s\n",
+	"📊 %d commits | %d fichiers | %d branches\n\n":          "📊 %d commits | %d files | %d branches\n\n",
+	"✅ Aucun changement - Dépôt propre":                     "✅ No changes - working tree clean",
+	"📝 Dernier commit: %s - %s (%s)\n":                      "📝 Last commit: %s - %s (%s)\n",
+	"il y a %d seconde":                                     "%d second ago",
+	"il y a %d secondes":                                    "%d seconds ago",
+	"il y a %d minute":                                      "%d minute ago",
+	"il y a %d minutes":                                     "%d minutes ago",
+	"il y a %d heure":                                       "%d hour ago",
+	"il y a %d heures":                                      "%d hours ago",
+	"il y a %d jour":                                        "%d day ago",
+	"il y a %d jours":                                       "%d days ago",
+	"il y a %d semaine":                                     "%d week ago",
+	"il y a %d semaines":                                    "%d weeks ago",
+	"il y a %d mois":                                        "%d month(s) ago",
+	"il y a %d an":                                          "%d year ago",
+	"il y a %d ans":                                         "%d years ago",
+	"ajouté":                                                "added",
+	"modifié":                                               "modified",
+	"supprimé":                                              "deleted",
+	"renommé":                                               "renamed",
+	"copié":                                                 "copied",
+	"type modifié":                                          "type changed",
+	"modifié des deux côtés":                                "both modified",
+	"ajouté des deux côtés":                                 "both added",
+	"supprimé des deux côtés":                               "both deleted",
+	"ajouté par nous":                                       "added by us",
+	"ajouté par eux":                                        "added by them",
+	"supprimé par nous":                                     "deleted by us",
+	"supprimé par eux":                                      "deleted by them",
+	"📝 Changements détectés:":                               "📝 Detected changes:",
+	"  📥 Indexés (%d):\n":                                   "  📥 Staged (%d):\n",
+	"  ✏️ Non indexés (%d):\n":                              "  ✏️ Not staged (%d):\n",
+	"  ⚔️ Conflits (%d):\n":                                 "  ⚔️ Conflicts (%d):\n",
+	"  📦 Sous-modules (%d):\n":                              "  📦 Submodules (%d):\n",
+	"nouveaux commits":                                      "new commits",
+	"modifications":                                         "modified content",
+	"fichiers non suivis":                                   "untracked files",
+	"  📂 Non suivis (%d): %s\n":                             "  📂 Untracked (%d): %s\n",
+	"  → Des conflits sont en cours - résolvez-les avec 'Résoudre les conflits' (16)": "  → There are unresolved conflicts - resolve them with 'Resolve conflicts' (16)",
+	"  → Utilisez 'Sync rapide' (9) pour ajouter, commiter et synchroniser automatiquement": "  → Use 'Quick sync' (9) to stage, commit and sync automatically",
+	"  → Des fichiers ont été supprimés - vérifiez que c'est intentionnel":                  "  → Some files were deleted - make sure this is intended",
+	
+	// Commit rapide et Conventional Commits
+	"ℹ️ Aucun changement à commiter": "ℹ️ Nothing to commit",
+	"COMMIT RAPIDE":                  "QUICK COMMIT",
+	"📥 %d fichier(s) déjà indexé(s). Commiter uniquement la sélection?": "📥 %d file(s) already staged. Commit only the selection?",
+	"Messages prédéfinis:":                                                    "Preset messages:",
+	"💬 Message personnalisé":                                                  "💬 Custom message",
+	"📐 Conventional Commit (type, portée, sujet...)":                          "📐 Conventional Commit (type, scope, subject...)",
+	"\nChoisissez (1-%d): ":                                                   "\nChoose (1-%d): ",
+	"💬 Votre message: ":                                                       "💬 Your message: ",
+	"❌ Choix invalide, utilisation du message par défaut":                     "❌ Invalid choice, using the default message",
+	"Commit rapide: %s":                                                       "Quick commit: %s",
+	"🚀 Mise à jour rapide":                                                    "🚀 Quick update",
+	"🐛 Correction de bug":                                                     "🐛 Bug fix",
+	"✨ Nouvelle fonctionnalité":                                               "✨ New feature",
+	"🎨 Améliorations UI":                                                      "🎨 UI improvements",
+	"Nouvelle fonctionnalité":                                                 "New feature",
+	"Correction de bug":                                                       "Bug fix",
+	"Mise en forme, sans changement de comportement":                          "Formatting, no behavior change",
+	"Build et dépendances":                                                    "Build and dependencies",
+	"Intégration continue":                                                    "Continuous integration",
+	"Annulation d'un commit":                                                  "Reverts a commit",
+	"type inconnu: '%s'":                                                      "unknown type: '%s'",
+	"portée invalide '%s': minuscules, chiffres, '-', '_', '.', '/'":          "invalid scope '%s': lowercase letters, digits, '-', '_', '.', '/'",
+	"sujet requis":                                                            "subject required",
+	"le sujet ne doit pas commencer ni finir par un espace":                   "the subject must not start or end with a space",
+	"le sujet ne doit pas finir par un point":                                 "the subject must not end with a period",
+	"le sujet doit tenir sur une ligne":                                       "the subject must fit on one line",
+	"le sujet doit commencer par une minuscule":                               "the subject must start with a lowercase letter",
+	"première ligne trop longue: %d caractères (max %d)":                      "first line too long: %d characters (max %d)",
+	"pied de page invalide '%s' (attendu 'Jeton: valeur' ou 'Jeton #valeur')": "invalid footer '%s' (expected 'Token: value' or 'Token #value')",
+	"changement incompatible: ajoutez un pied de page 'BREAKING CHANGE: ...'": "breaking change: add a 'BREAKING CHANGE: ...' footer",
+	"🏷️ Type (numéro ou nom): ":                                               "🏷️ Type (number or name): ",
+	"commit annulé":                                                           "commit cancelled",
+	"❌ Type inconnu":                                                          "❌ Unknown type",
+	"🎯 Portée (Entrée pour '%s', - pour aucune): ":                            "🎯 Scope (Enter for '%s', - for none): ",
+	"🎯 Portée (optionnelle): ":                                                "🎯 Scope (optional): ",
+	"💥 Changement incompatible?":                                              "💥 Breaking change?",
+	"📝 Sujet (impératif, sans point final): ":                                 "📝 Subject (imperative, no trailing period): ",
+	"📄 Corps (optionnel, ligne vide pour terminer):":                          "📄 Body (optional, empty line to finish):",
+	"🔗 Tickets liés (ex: #12 #34): ":                                          "🔗 Related issues (e.g. #12 #34): ",
+	"💥 Description du changement incompatible: ":                              "💥 Breaking change description: ",
+	"🦶 Autres pieds de page ('Jeton: valeur', ligne vide pour terminer):":     "🦶 Other footers ('Token: value', empty line to finish):",
+	"message invalide: %v":                                                    "invalid message: %v",
+	"🎯 Nouvelle portée (Entrée pour garder '%s', - pour aucune): ":            "🎯 New scope (Enter to keep '%s', - for none): ",
+	"📝 Nouveau sujet (vide pour annuler): ":                                   "📝 New subject (empty to cancel): ",
+	"Aperçu du message:":                                                      "Message preview:",
+	"✅ Utiliser ce message?":                                                  "✅ Use this message?",
+	
+	// Branches
+	"GESTION INTELLIGENTE DES BRANCHES":                         "SMART BRANCH MANAGEMENT",
+	"Actions disponibles":                                       "Available actions",
+	"%d. 🔄 Changer de branche\n":                                "%d. 🔄 Switch branch\n",
+	"%d. 🗑️ Supprimer une branche\n":                            "%d. 🗑️ Delete a branch\n",
+	"%d. 🔀 Fusionner une branche\n":                             "%d. 🔀 Merge a branch\n",
+	"❌ Choix invalide":                                          "❌ Invalid choice",
+	"Branches existantes":                                       "Existing branches",
+	"🌱 Créer branche de fonctionnalité":                         "🌱 Create feature branch",
+	"🐛 Créer branche de correction":                             "🐛 Create bugfix branch",
+	"🌿 Créer branche %s":                                        "🌿 Create %s branch",
+	"✨ Nom de la fonctionnalité: ":                              "✨ Feature name: ",
+	"nom requis":                                                "name required",
+	"Branche créée":                                             "Branch created",
+	"🐛 Description du bug: ":                                    "🐛 Bug description: ",
+	"description requise":                                       "description required",
+	"Branche de correction créée":                               "Bugfix branch created",
+	"🌿 Nom de la branche %s: ":                                  "🌿 %s branch name: ",
+	"✅ Branche '%s' créée et activée!\n":                        "✅ Branch '%s' created and checked out!\n",
+	"🗑️ Nom de la branche à supprimer: ":                        "🗑️ Branch to delete: ",
+	"❌ Impossible de supprimer la branche courante":             "❌ Cannot delete the current branch",
+	"⚠️ Êtes-vous sûr de vouloir supprimer '%s'?":               "⚠️ Are you sure you want to delete '%s'?",
+	"❌ Suppression annulée":                                     "❌ Deletion cancelled",
+	"⚠️ Branche non fusionnée. Forcer la suppression?":          "⚠️ Branch not merged. Force deletion?",
+	"impossible de supprimer la branche courante":               "cannot delete the current branch",
+	"✅ Branche '%s' supprimée!\n":                               "✅ Branch '%s' deleted!\n",
+	"Branche supprimée: %s":                                     "Branch deleted: %s",
+	"🔀 Fusion vers la branche courante (%s)\n":                  "🔀 Merge into the current branch (%s)\n",
+	"Nom de la branche à fusionner: ":                           "Branch to merge: ",
+	"✅ Branche '%s' fusionnée dans '%s'!\n":                     "✅ Branch '%s' merged into '%s'!\n",
+	"Fusion: %s → %s":                                           "Merge: %s → %s",
+	"Fusion (fast-forward si possible, sinon commit de fusion)": "Merge (fast-forward when possible, merge commit otherwise)",
+	"Fast-forward uniquement":                                   "Fast-forward only",
+	"Toujours un commit de fusion":                              "Always create a merge commit",
+	"Squash: un seul commit avec tous les changements":          "Squash: a single commit with all the changes",
+	"Rebase de la branche puis fast-forward":                    "Rebase the branch, then fast-forward",
+	"⚠️ Aperçu indisponible: %v\n":                               "⚠️ Preview unavailable: %v\n",
+	"\n🔀 Stratégie de fusion:":                                  "\n🔀 Merge strategy:",
+	"\nStratégie (1-%d, défaut %s): ":                            "\nStrategy (1-%d, default %s): ",
+	"⚠️ Fusionner malgré les conflits prévus?":                   "⚠️ Merge despite the expected conflicts?",
+	"❌ Fusion annulée":                                          "❌ Merge cancelled",
+	"Aperçu: %s → %s":                                           "Preview: %s → %s",
+	"ℹ️ '%s' est déjà à jour avec '%s'\n":                        "ℹ️ '%s' is already up to date with '%s'\n",
+	"📥 %d commit(s) à intégrer:\n":                             "📥 %d incoming commit(s):\n",
+	"  ... et %d autre(s)\n":                                     "  ... and %d more\n",
+	"📄 %d fichier(s) touché(s):\n":                              "📄 %d file(s) touched:\n",
+	"✅ Fast-forward possible, aucun conflit":                    "✅ Fast-forward possible, no conflicts",
+	"✅ Fusion sans conflit":                                     "✅ Merges without conflicts",
+	"⚔️ Conflits prévus dans %d fichier(s):\n":                   "⚔️ Expected conflicts in %d file(s):\n",
+	"stratégie de fusion inconnue: %s (merge, ff-only, no-ff, squash ou rebase)": "unknown merge strategy: %s (merge, ff-only, no-ff, squash or rebase)",
+	"🔁 Rebase de '%s' sur '%s'...\n":                            "🔁 Rebasing '%s' onto '%s'...\n",
+	"🔀 Squash de la branche '%s'":                               "🔀 Squash of branch '%s'",
+	"fast-forward impossible: '%s' a divergé":                   "cannot fast-forward: '%s' has diverged",
+	"%w (backend exec requis: installez git ou utilisez --backend exec)": "%w (exec backend required: install git or use --backend exec)",
+	
+	// Historique
+	"HISTORIQUE INTERACTIF":                                       "INTERACTIVE HISTORY",
+	"1. 👀 Voir détails d'un commit":                               "1. 👀 Show commit details",
+	"2. ⏪ Reset vers un commit":                                   "2. ⏪ Reset to a commit",
+	"3. 🌱 Créer branche depuis commit":                            "3. 🌱 Create branch from commit",
+	"4. 🔍 Rechercher dans l'historique":                           "4. 🔍 Search history",
+	"\nChoisissez (1-4): ":                                        "\nChoose (1-4): ",
+	"🔍 Hash du commit: ":                                          "🔍 Commit hash: ",
+	"hash requis":                                                 "hash required",
+	"Détails du commit":                                           "Commit details",
+	"impossible d'obtenir le diff: %v":                            "cannot get the diff: %v",
+	"Aucun changement de fichier dans ce commit":                  "No file changes in this commit",
+	"Diff complet":                                                "Full diff",
+	"🔍 Rechercher (message/auteur/fichier): ":                     "🔍 Search (message/author/file): ",
+	"terme de recherche requis":                                   "search term required",
+	"📝 Commits avec ce message:":                                  "📝 Commits with this message:",
+	"📁 Commits affectant ce fichier:":                             "📁 Commits touching this file:",
+	"❌ Aucun résultat trouvé":                                     "❌ No results found",
+	"📜 Historique récent:":                                        "📜 Recent history:",
+	"\n🔄 Types de reset:":                                         "\n🔄 Reset types:",
+	"1. 🟢 SOFT - Garde les changements dans le staging":           "1. 🟢 SOFT - Keeps changes staged",
+	"2. 🟡 MIXED - Garde les changements mais pas dans le staging": "2. 🟡 MIXED - Keeps changes, unstaged",
+	"3. 🔴 HARD - Supprime TOUS les changements":                   "3. 🔴 HARD - Discards ALL changes",
+	"\nType (1-3, défaut %s): ":                                   "\nType (1-3, default %s): ",
+	"🎯 Hash du commit (ou HEAD~n): ":                              "🎯 Commit hash (or HEAD~n): ",
+	"✅ Reset effectué!":                                           "✅ Reset done!",
+	"Reset %s vers %s":                                            "Reset %s to %s",
+	"\n🎯 Hash du commit: ":                                        "\n🎯 Commit hash: ",
+	"🌱 Nom de la branche: ":                                       "🌱 Branch name: ",
+	"hash et nom requis":                                          "hash and name required",
+	"✅ Branche créée et activée!":                                 "✅ Branch created and checked out!",
+	"Branche %s depuis %s":                                        "Branch %s from %s",
+	
+	// Annulation
+	"Suppression de branche":       "Branch deletion",
+	"Reset":                        "Reset",
+	"Fusion":                       "Merge",
+	"Rebase":                       "Rebase",
+	"Cherry-pick":                  "Cherry-pick",
+	"Revert":                       "Revert",
+	"Annulation":                   "Undo",
+	"HEAD détachée":                "detached HEAD",
+	"%s · %s @ %s · %d branche(s)": "%s · %s @ %s · %d branch(es)",
+	" · changements locaux":        " · local changes",
+	"⚠️ Changements locaux non sauvegardés: %v\n":                                                      "⚠️ Local changes not saved: %v\n",
+	"⚠️ Continuer sans pouvoir les restaurer?":                                                         "⚠️ Continue without being able to restore them?",
+	"💾 État sauvegardé: « Annuler la dernière action » le restaure":                                    "💾 State saved: \"Undo last action\" restores it",
+	"💾 Branches sauvegardées: « Annuler la dernière action » ne restaurera pas les changements locaux": "💾 Branches saved: \"Undo last action\" will not restore the local changes",
+	"ℹ️ Aucun instantané à restaurer":                                                                  "ℹ️ No snapshots to restore",
+	"ANNULER LA DERNIÈRE ACTION":                                                                       "UNDO LAST ACTION",
+	"\nInstantané à restaurer (Entrée pour le plus récent): ":                                          "\nSnapshot to restore (Enter for the latest): ",
+	"↩️ Restaurer l'état d'avant « %s » (%s)?":                                                         "↩️ Restore the state before \"%s\" (%s)?",
+	"❌ Annulation abandonnée":                                                                          "❌ Undo cancelled",
+	"✅ État restauré: %s\n":                                                                            "✅ State restored: %s\n",
+	"Annulation: %s":                                                                                   "Undo: %s",
+	
+	// Journal
+	"⚠️ Journal non enregistré: %v\n":                                       "⚠️ Journal not written: %v\n",
+	"date invalide: %s (AAAA-MM-JJ, RFC 3339 ou durée comme 7d)":            "invalid date: %s (YYYY-MM-DD, RFC 3339 or a duration such as 7d)",
+	"📜 Aucune action enregistrée dans ce dépôt":                             "📜 No actions recorded in this repository",
+	"JOURNAL DES ACTIONS":                                                   "ACTION JOURNAL",
+	"Types: %s\n":                                                           "Types: %s\n",
+	"Type d'action (Entrée pour tous): ":                                    "Action type (Enter for all): ",
+	"Depuis (AAAA-MM-JJ ou durée comme 7d, Entrée pour tout): ":             "Since (YYYY-MM-DD or a duration such as 7d, Enter for everything): ",
+	"ℹ️ Aucune action ne correspond au filtre":                              "ℹ️ No actions match the filter",
+	"… %d entrée(s) plus ancienne(s): filtrez ou exportez pour tout voir\n": "… %d older entry(ies): filter or export to see everything\n",
+	"\n📤 Exporter en JSON (chemin du fichier, Entrée pour ignorer): ":       "\n📤 Export as JSON (file path, Enter to skip): ",
+	"✅ %d entrée(s) exportée(s) vers %s\n":                                  "✅ %d entry(ies) exported to %s\n",
+	
+	// Stash
+	"%v (relancez avec --autostash pour les mettre de côté)":                                           "%v (run again with --autostash to stash them)",
+	"⚠️ Des changements locaux seraient écrasés par le changement de branche":                          "⚠️ Local changes would be overwritten by the branch switch",
+	"📦 Les mettre de côté, changer de branche puis les réappliquer?":                                   "📦 Stash them, switch branch, then reapply them?",
+	"gitctrl: avant le passage à %s":                                                                   "gitctrl: before switching to %s",
+	"📦 Changements mis de côté dans stash@{0}":                                                         "📦 Changes stashed in stash@{0}",
+	"%v (changements conservés dans stash@{0}: %v)":                                                    "%v (changes kept in stash@{0}: %v)",
+	"📥 Changements réappliqués":                                                                        "📥 Changes reapplied",
+	"%w: ils restent dans %s (%v)":                                                                     "%w: they remain in %s (%v)",
+	"branche changée, mais les changements mis de côté n'ont pas pu être réappliqués":                  "branch switched, but the stashed changes could not be reapplied",
+	"ℹ️ Aucune entrée mise de côté":                                                                    "ℹ️ No stashed entries",
+	"\n1. 💾 Mettre de côté les changements":                                                            "\n1. 💾 Stash changes",
+	"2. 👀 Voir le diff d'une entrée":                                                                   "2. 👀 Show an entry's diff",
+	"3. 📥 Appliquer une entrée (la garder)":                                                            "3. 📥 Apply an entry (keep it)",
+	"4. 📤 Réappliquer et retirer une entrée (pop)":                                                     "4. 📤 Reapply and remove an entry (pop)",
+	"5. 🗑️ Supprimer une entrée":                                                                       "5. 🗑️ Drop an entry",
+	"6. 🌱 Créer une branche depuis une entrée":                                                         "6. 🌱 Create a branch from an entry",
+	"\nChoisissez (1-6): ":                                                                             "\nChoose (1-6): ",
+	"💬 Message (Entrée pour le message par défaut): ":                                                  "💬 Message (Enter for the default message): ",
+	"Inclure les fichiers non suivis?":                                                                 "Include untracked files?",
+	"📦 Entrée (n° ou stash@{n}): ":                                                                     "📦 Entry (number or stash@{n}): ",
+	"entrée requise":                                                                                   "entry required",
+	"⚠️ Supprimer %s? Ses changements seront perdus":                                                   "⚠️ Drop %s? Its changes will be lost",
+	"aucun changement à mettre de côté":                                                                "no changes to stash",
+	"✅ %d changement(s) mis de côté dans stash@{0}\n":                                                  "✅ %d change(s) stashed in stash@{0}\n",
+	"Stash: %d changement(s)":                                                                          "Stash: %d change(s)",
+	"⚠️ Conflits en réappliquant %s: résolvez-les puis indexez les fichiers; l'entrée est conservée\n": "⚠️ Conflicts while reapplying %s: resolve them, then stage the files; the entry is kept\n",
+	"✅ %s réappliquée\n":                                                                               "✅ %s reapplied\n",
+	"🗑️ %s retirée de la pile\n":                                                                       "🗑️ %s removed from the stack\n",
+	"✅ %s supprimée (%s)\n":                                                                            "✅ %s dropped (%s)\n",
+	"Stash supprimé: %s":                                                                               "Stash dropped: %s",
+	"✅ Branche '%s' créée depuis %s et activée!\n":                                                     "✅ Branch '%s' created from %s and checked out!\n",
+	
+	// Conflits
+	"opération abandonnée": "operation aborted",
+	"conflits non résolus": "unresolved conflicts",
+	"⚔️ Conflits dans %d fichier(s):\n": "⚔️ Conflicts in %d file(s):\n",
+	"⚔️ Résoudre les conflits maintenant?": "⚔️ Resolve the conflicts now?",
+	"→ Résolvez-les avec « gitctrl conflicts » ou l'entrée 16 du menu": "→ Resolve them with \"gitctrl conflicts\" or menu entry 16",
+	"RÉSOLUTION DES CONFLITS": "CONFLICT RESOLUTION",
+	"Opération en cours: %s\n": "Operation in progress: %s\n",
+	"✅ Aucun conflit": "✅ No conflicts",
+	"✅ Tous les conflits sont résolus": "✅ All conflicts are resolved",
+	"a: abandonner, Entrée: plus tard: ": "a: abort, Enter: later: ",
+	"s: passer ce commit, ": "s: skip this commit, ",
+	"c: continuer, ": "c: continue, ",
+	"\nFichier à résoudre (n°), ": "\nFile to resolve (number), ",
+	"⏸️ Arrêt pour modifier le commit %s: changez les fichiers et indexez-les (11), ils seront ajoutés au commit\n": "⏸️ Stopped to edit commit %s: change the files and stage them (11), they will be added to the commit\n",
+	"⏭️ Commit passé (%s)...\n": "⏭️ Commit skipped (%s)...\n",
+	"Commit passé: %s": "Commit skipped: %s",
+	"\nFichier à résoudre (n°), Entrée: plus tard: ": "\nFile to resolve (number), Enter: later: ",
+	"  nous: %s · eux: %s\n": "  ours: %s · theirs: %s\n",
+	"--- Conflit %d/%d ---\n": "--- Conflict %d/%d ---\n",
+	"nous": "ours",
+	"eux": "theirs",
+	"%d ligne(s)": "%d line(s)",
+	"\no: garder la nôtre, t: garder la leur, b: les deux, e: éditer, Entrée: passer: ": "\no: keep ours, t: keep theirs, b: both, e: edit, Enter: skip: ",
+	"✅ %s résolu (%s)\n": "✅ %s resolved (%s)\n",
+	"Conflit résolu: %s (%s)": "Conflict resolved: %s (%s)",
+	"éditeur: %v": "editor: %v",
+	"⚠️ Des marqueurs de conflit restent dans %s: fichier non marqué résolu\n": "⚠️ Conflict markers remain in %s: file not marked resolved\n",
+	"%d fichier(s) encore en conflit": "%d file(s) still conflicted",
+	"▶️ Poursuite de l'opération (%s)...\n": "▶️ Continuing the operation (%s)...\n",
+	"✅ Opération terminée (%s)\n": "✅ Operation finished (%s)\n",
+	"Conflits résolus: %s": "Conflicts resolved: %s",
+	"↩️ Opération abandonnée (%s): le dépôt est revenu à son état d'avant\n": "↩️ Operation aborted (%s): the repository is back to its previous state\n",
+	"Opération abandonnée: %s": "Operation aborted: %s",
+	"%s n'est pas en conflit": "%s is not conflicted",
+	
+	// Cherry-pick et revert
+	"5. 🍒 Cherry-pick de commits d'une autre branche": "5. 🍒 Cherry-pick commits from another branch",
+	"6. ↩️ Revert d'un commit": "6. ↩️ Revert a commit",
+	"🍒 Branche source: ": "🍒 Source branch: ",
+	"ℹ️ Tous les commits de '%s' sont déjà dans la branche courante\n": "ℹ️ All the commits of '%s' are already in the current branch\n",
+	"    … %d commit(s) plus ancien(s) non affiché(s): indiquez leur hash\n": "    … %d older commit(s) not shown: enter their hash\n",
+	"\nCommits à appliquer (n° ou hash, séparés par des espaces): ": "\nCommits to apply (numbers or hashes, separated by spaces): ",
+	"aucun commit choisi": "no commit selected",
+	"📝 Noter la provenance dans le message (-x)?": "📝 Record the origin in the message (-x)?",
+	"✅ %d commit(s) appliqué(s) sur '%s'!\n": "✅ %d commit(s) applied onto '%s'!\n",
+	"Cherry-pick: %s": "Cherry-pick: %s",
+	"↩️ Commit à annuler: ": "↩️ Commit to revert: ",
+	"🔀 %s est un commit de fusion. Parents:\n": "🔀 %s is a merge commit. Parents:\n",
+	"Parent principal (1-%d, défaut 1, la branche qui a reçu la fusion): ": "Mainline parent (1-%d, default 1, the branch that received the merge): ",
+	"↩️ Annuler « %s » par un nouveau commit?": "↩️ Revert \"%s\" with a new commit?",
+	"❌ Revert annulé": "❌ Revert cancelled",
+	"✅ Commit %s annulé!\n": "✅ Commit %s reverted!\n",
+	"Revert: %s": "Revert: %s",
+	"commit introuvable: %s": "commit not found: %s",
+	
+	// Rebase interactif
+	"%s %s: aucun commit avant auquel l'ajouter": "%s %s: no earlier commit to add it to",
+	"REBASE INTERACTIF": "INTERACTIVE REBASE",
+	"⚠️ Une opération est déjà en cours (%s)\n": "⚠️ An operation is already in progress (%s)\n",
+	"🎯 Rebaser '%s' sur (branche, commit ou HEAD~n): ": "🎯 Rebase '%s' onto (branch, commit or HEAD~n): ",
+	"base requise": "base required",
+	"ℹ️ Aucun commit de '%s' à rejouer sur %s\n": "ℹ️ No commit of '%s' to replay onto %s\n",
+	"\n<n°> <action>: p pick, r reword, e edit, s squash, f fixup, d drop": "\n<number> <action>: p pick, r reword, e edit, s squash, f fixup, d drop",
+	"m <n°> <position>: déplacer un commit": "m <number> <position>: move a commit",
+	"Choix (Entrée: lancer le rebase, q: annuler): ": "Choice (Enter: start the rebase, q: cancel): ",
+	"❌ Rebase annulé": "❌ Rebase cancelled",
+	"💬 Nouveau message (%s): ": "💬 New message (%s): ",
+	"⏸️ Opération arrêtée (%s): poursuivez avec « gitctrl conflicts continue », skip ou abort\n": "⏸️ Operation stopped (%s): resume with \"gitctrl conflicts continue\", skip or abort\n",
+	"✅ '%s' rebasée sur %s!\n": "✅ '%s' rebased onto %s!\n",
+	"Rebase interactif sur %s": "Interactive rebase onto %s",
+	
+	// Tags et releases
+	"📌 Dernière release: %s (%d commit(s) depuis)\n": "📌 Latest release: %s (%d commit(s) since)\n",
+	"📌 Aucune release taguée (%d commit(s))\n": "📌 No tagged release (%d commit(s))\n",
+	"💡 Version suggérée: %s (%s)\n": "💡 Suggested version: %s (%s)\n",
+	"⚠️ Changements incompatibles": "⚠️ Breaking changes",
+	"✨ Nouveautés": "✨ Features",
+	"🐛 Corrections": "🐛 Fixes",
+	"ℹ️ Aucun tag": "ℹ️ No tags",
+	"TAGS ET RELEASES": "TAGS AND RELEASES",
+	"\n1. 🚀 Nouvelle release (version suggérée, notes générées)": "\n1. 🚀 New release (suggested version, generated notes)",
+	"2. 🏷️ Tagger un commit": "2. 🏷️ Tag a commit",
+	"3. 🗑️ Supprimer un tag": "3. 🗑️ Delete a tag",
+	"🔍 Commit à tagger: ": "🔍 Commit to tag: ",
+	"🏷️ Nom du tag: ": "🏷️ Tag name: ",
+	"💬 Message (Entrée pour des notes générées): ": "💬 Message (Enter for generated notes): ",
+	"🏷️ Tag à supprimer: ": "🏷️ Tag to delete: ",
+	"⚠️ Supprimer le tag %s?": "⚠️ Delete tag %s?",
+	"ℹ️ Aucun commit depuis %s: rien à publier\n": "ℹ️ No commit since %s: nothing to release\n",
+	"🏷️ Version (Entrée: %s; major, minor, patch ou un nom): ": "🏷️ Version (Enter: %s; major, minor, patch or a name): ",
+	"🏷️ Créer le tag annoté %s sur HEAD avec ces notes?": "🏷️ Create annotated tag %s on HEAD with these notes?",
+	"❌ Release annulée": "❌ Release cancelled",
+	"nom de tag invalide: '%s'": "invalid tag name: '%s'",
+	"✅ Tag %s créé sur %s\n": "✅ Tag %s created on %s\n",
+	"Tag: %s": "Tag: %s",
+	"✅ Tag %s supprimé\n": "✅ Tag %s deleted\n",
+	"Tag supprimé: %s": "Tag deleted: %s",
+	"le tag '%s' existe déjà": "tag '%s' already exists",
+	"tag introuvable: %s": "tag not found: %s",
+	
+	// Changelog
+	"📝 Documentation": "📝 Documentation",
+	"🎨 Style": "🎨 Style",
+	"♻️ Refactoring": "♻️ Refactoring",
+	"⚡ Performance": "⚡ Performance",
+	"✅ Tests": "✅ Tests",
+	"📦 Build et dépendances": "📦 Build and dependencies",
+	"👷 Intégration continue": "👷 Continuous integration",
+	"🔧 Maintenance": "🔧 Chores",
+	"⏪ Annulations": "⏪ Reverts",
+	"📌 Autres changements": "📌 Other changes",
+	"Non publié": "Unreleased",
+	"%s contient déjà une section « %s »": "%s already has a \"%s\" section",
+	"✅ Section « %s » ajoutée en tête de %s\n": "✅ Section \"%s\" added at the top of %s\n",
+	"Changelog: %s": "Changelog: %s",
+	"premier commit": "first commit",
+	"📍 Depuis (Entrée: %s): ": "📍 From (Enter: %s): ",
+	"📍 Jusqu'à (Entrée: HEAD): ": "📍 To (Enter: HEAD): ",
+	"ℹ️ Aucun commit dans cet intervalle": "ℹ️ No commit in this range",
+	"📰 Ajouter cette section en tête de %s?": "📰 Add this section at the top of %s?",
+	"4. 📰 Changelog (aperçu, ajout en tête de CHANGELOG.md)": "4. 📰 Changelog (preview, add at the top of CHANGELOG.md)",
+	
+	// Analyse du projet
+	"ANALYSE DU PROJET":                              "PROJECT INSIGHTS",
+	"📈 Statistiques:\n":                              "📈 Statistics:\n",
+	"  • %s commits au total\n":                      "  • %s commits in total\n",
+	"  • %s fichiers suivis\n":                       "  • %s tracked files\n",
+	"  • %s branches\n\n":                            "  • %s branches\n\n",
+	"Branches disponibles":                           "Available branches",
+	"(branche actuelle)":                             "(current branch)",
+	"  Aucune branche trouvée":                       "  No branches found",
+	"⚡ Activité récente: %s commits cette semaine\n": "⚡ Recent activity: %s commits this week\n",
+	"💾 Taille: %s\n":                                 "💾 Size: %s\n",
+	
+	// Contributeurs
+	"Contributeurs (%d)": "Contributors (%d)",
+	"  … et %d autres\n": "  … and %d more\n",
+	"  • %s <%s>: %s commits, %s %s lignes, du %s au %s\n":                         "  • %s <%s>: %s commits, %s %s lines, from %s to %s\n",
+	"Bus factor par dossier (auteurs qui ont écrit plus de la moitié des lignes):": "Bus factor per directory (authors who wrote more than half of the lines):",
+	"(racine)": "(root)",
+	
+	// Langages
+	"Langages (fichiers suivis à HEAD):": "Languages (files tracked at HEAD):",
+	"Langage":                            "Language",
+	"Fichiers":                           "Files",
+	"Code":                               "Code",
+	"Commentaires":                       "Comments",
+	"Vides":                              "Blank",
+	"Autre":                              "Other",
+	"  ℹ️ %d fichier(s) vendored ou générés ignorés (.gitattributes: linguist-vendored, linguist-generated)\n": "  ℹ️ %d vendored or generated file(s) skipped (.gitattributes: linguist-vendored, linguist-generated)\n",
+	"réponse inattendue de git cat-file pour %s": "unexpected git cat-file response for %s",
+	
+	// Points chauds
+	"tout l'historique":                            "whole history",
+	"depuis le %s":                                 "since %s",
+	"Points chauds (%s, %d commits)":               "Hot spots (%s, %d commits)",
+	"  Aucun fichier suivi modifié sur la période": "  No tracked file changed in this period",
+	"  Les plus souvent modifiés:":                 "  Most frequently changed:",
+	"  %2d. %-*s %4d commits  %s %s\n":             "  %2d. %-*s %4d commits  %s %s\n",
+	"  Le plus de lignes changées:":                "  Most lines changed:",
+	"  %2d. %-*s %6d lignes   %s %s\n":             "  %2d. %-*s %6d lines    %s %s\n",
+	"  Souvent modifiés ensemble:":                 "  Often changed together:",
+	"  %2d. %s ↔ %s: %d commits communs (%d%%)\n":  "  %2d. %s ↔ %s: %d shared commits (%d%%)\n",
+	
+	// Indexation sélective
+	"ℹ️ Aucun changement à indexer": "ℹ️ Nothing to stage",
+	"INDEXATION SÉLECTIVE":          "SELECTIVE STAGING",
+	"Commandes":                     "Commands",
+	"  <n°> [n°...]  basculer des fichiers    <dossier>/  basculer un dossier": "  <n> [n...]  toggle files    <directory>/  toggle a directory",
+	"  a  tout indexer    n  tout désindexer":                                  "  a  stage all    n  unstage all",
+	"  p <n°>  indexer par morceaux    u <n°>  désindexer par morceaux":        "  p <n>  stage hunks    u <n>  unstage hunks",
+	"  c  commiter la sélection    q  quitter":                                 "  c  commit the selection    q  quit",
+	"❌ Aucun fichier indexé":                                                   "❌ No staged files",
+	"❌ Numéro de fichier invalide":                                             "❌ Invalid file number",
+	"❌ Erreur: %v\n":                                                           "❌ Error: %v\n",
+	"aucun fichier ne correspond à '%s'":                                       "no file matches '%s'",
+	"➖ %d fichier(s) désindexé(s)\n":                                           "➖ %d file(s) unstaged\n",
+	"➕ %d fichier(s) indexé(s)\n":                                              "➕ %d file(s) staged\n",
+	"fichier non suivi: indexez-le en entier":                                  "untracked file: stage it as a whole",
+	"ℹ️ Aucun morceau texte pour ce fichier":                                   "ℹ️ No text hunks for this file",
+	"Indexer ce morceau?":                                                      "Stage this hunk?",
+	"Désindexer ce morceau?":                                                   "Unstage this hunk?",
+	"\n🧩 Morceau %d/%d de %s\n":                                                "\n🧩 Hunk %d/%d of %s\n",
+	"ℹ️ Aucun morceau sélectionné":                                             "ℹ️ No hunks selected",
+	"✅ %d morceau(x) appliqué(s) à l'index\n":                                  "✅ %d hunk(s) applied to the index\n",
+	"aucun fichier indexé":                                                     "no staged files",
+	"📥 Fichiers sélectionnés (%d): %s\n":                                       "📥 Selected files (%d): %s\n",
+	"Commit sélectif (%d fichiers): %s":                                        "Selective commit (%d files): %s",
+	
+	// Opérations de base
+	"🔧 Initialisation du dépôt Git...":        "🔧 Initializing the Git repository...",
+	"erreur lors de l'initialisation: %v":     "initialization failed: %v",
+	"✅ Dépôt Git initialisé avec succès!":     "✅ Git repository initialized!",
+	"Dépôt initialisé":                        "Repository initialized",
+	"📝 Ajout de tous les fichiers...":         "📝 Staging all files...",
+	"erreur lors de l'ajout des fichiers: %v": "staging files failed: %v",
+	"✅ Fichiers ajoutés!":                     "✅ Files staged!",
+	"💾 Commit avec le message: %s\n":          "💾 Committing with message: %s\n",
+	"erreur lors du commit: %v":               "commit failed: %v",
+	"✅ Commit effectué!":                      "✅ Commit done!",
+	
+	// Synchronisation
+	"SYNCHRONISATION AUTOMATIQUE":  "AUTOMATIC SYNC",
+	"Ajout des fichiers":           "Stage files",
+	"aucun changement":             "no changes",
+	"%d fichier(s)":                "%d file(s)",
+	"aucun dépôt distant":          "no remote",
+	"aucune branche suivie":        "no upstream branch",
+	"interrompu":                   "interrupted",
+	"Résumé de la synchronisation": "Sync summary",
+	"↩️ Dépôt restauré dans son état d'avant la synchronisation":    "↩️ Repository restored to its state before the sync",
+	"synchronisation interrompue (%s): %v":                          "sync interrupted (%s): %v",
+	"🎉 Synchronisation locale terminée (aucun dépôt distant)!":      "🎉 Local sync done (no remote)!",
+	"🎉 Synchronisation terminée!":                                   "🎉 Sync done!",
+	"Synchronisation automatique":                                   "Automatic sync",
+	"↩️ Annulation des étapes effectuées...":                        "↩️ Rolling back completed steps...",
+	"⚠️ Abandon du rebase impossible: %v\n":                         "⚠️ Cannot abort the rebase: %v\n",
+	"⚠️ Premier commit du dépôt conservé: annulation impossible":    "⚠️ First commit of the repository kept: cannot roll back",
+	"⚠️ Restauration du commit de synchronisation impossible: %v\n": "⚠️ Cannot restore the sync commit: %v\n",
+	"⚠️ Restauration de HEAD impossible: %v\n":                      "⚠️ Cannot restore HEAD: %v\n",
+	"ajouté(s)":   "added",
+	"modifié(s)":  "modified",
+	"renommé(s)":  "renamed",
+	"supprimé(s)": "deleted",
+	
+	// Dépôts distants
+	"DÉPÔTS DISTANTS":                                     "REMOTES",
+	"1. ➕ Ajouter un dépôt distant":                       "1. ➕ Add a remote",
+	"2. 📥 Récupérer (fetch)":                              "2. 📥 Fetch",
+	"3. ⬇️ Tirer (pull)":                                  "3. ⬇️ Pull",
+	"4. ⬆️ Pousser (push)":                                "4. ⬆️ Push",
+	"🏷️ Nom du dépôt distant (origin): ":                  "🏷️ Remote name (origin): ",
+	"URL requise":                                         "URL required",
+	"1. 🔀 Fusion (merge)":                                 "1. 🔀 Merge",
+	"Dépôts distants":                                     "Remotes",
+	"  Aucun dépôt distant configuré":                     "  No remote configured",
+	"à jour":                                              "up to date",
+	"aucun dépôt distant configuré":                       "no remote configured",
+	"plusieurs dépôts distants: précisez lequel utiliser": "several remotes: specify which one to use",
+	"✅ Dépôt distant '%s' ajouté (%s)\n":                  "✅ Remote '%s' added (%s)\n",
+	"Dépôt distant ajouté: %s":                            "Remote added: %s",
+	"tous les dépôts distants":                            "all remotes",
+	"📥 Récupération depuis %s...\n":                       "📥 Fetching from %s...\n",
+	"✅ Récupération terminée!":                            "✅ Fetch done!",
+	"fusion":                                              "merge",
+	"⬇️ Pull (%s)...\n":                                   "⬇️ Pull (%s)...\n",
+	"✅ Branche à jour!":                                   "✅ Branch up to date!",
+	"HEAD détachée: aucune branche à pousser":             "detached HEAD: no branch to push",
+	"⬆️ Push de %s...\n":                                  "⬆️ Pushing %s...\n",
+	"🔗 Branche suivie: %s/%s\n":                           "🔗 Upstream branch: %s/%s\n",
+	"✅ Push effectué!":                                    "✅ Push done!",
+	"🔄 Nom de la branche: ":                               "🔄 Branch name: ",
+	"nom de branche requis":                               "branch name required",
+	"🔄 Changement vers la branche: %s\n":                  "🔄 Switching to branch: %s\n",
+	"erreur lors du changement de branche: %v":            "switching branch failed: %v",
+	"✅ Branche changée!":                                  "✅ Branch switched!",
+	"Changement vers: %s":                                 "Switched to: %s",
+	
+	// Répertoire de travail et menu
+	"chemin vide":                                               "empty path",
+	"chemin invalide: %v":                                       "invalid path: %v",
+	"le répertoire n'existe pas: %s":                            "directory does not exist: %s",
+	"✅ Répertoire défini: %s\n":                                 "✅ Working directory: %s\n",
+	"📁 Nouveau répertoire: ":                                    "📁 New directory: ",
+	"\n⚠️ Ce répertoire n'est pas un dépôt Git.":                "\n⚠️ This directory is not a Git repository.",
+	"1. 🔧 Initialiser un dépôt Git ici":                         "1. 🔧 Initialize a Git repository here",
+	"2. 📁 Changer de répertoire":                                "2. 📁 Change directory",
+	"3. ❌ Quitter":                                              "3. ❌ Quit",
+	"\nChoisissez (1-3): ":                                      "\nChoose (1-3): ",
+	"👋 Au revoir!":                                              "👋 Goodbye!",
+	"❌ Choix invalide!":                                         "❌ Invalid choice!",
+	"GIT ASSISTANT INTELLIGENT":                                 "SMART GIT ASSISTANT",
+	"📁 Répertoire: %s\n":                                        "📁 Directory: %s\n",
+	"🌿 Branche: %s | 📊 %d commits":                              "🌿 Branch: %s | 📊 %d commits",
+	"⚠️ Changements non commitées":                              "⚠️ Uncommitted changes",
+	"ACTIONS RAPIDES":                                           "QUICK ACTIONS",
+	"1. ⚡ Commit rapide (messages prédéfinis)":                  "1. ⚡ Quick commit (preset messages)",
+	"8. 📊 Statut intelligent":                                   "8. 📊 Smart status",
+	"9. 🔄 Sync rapide (ajout, commit, pull, push)":              "9. 🔄 Quick sync (stage, commit, pull, push)",
+	"11. 📋 Indexation sélective (fichiers, dossiers, morceaux)": "11. 📋 Selective staging (files, directories, hunks)",
+	"13. 🖥️ Mode plein écran (statut, branches, historique)":    "13. 🖥️ Full-screen mode (status, branches, log)",
+	"14. ↩️ Annuler la dernière action (instantanés)":           "14. ↩️ Undo last action (snapshots)",
+	"GESTION AVANCÉE":                                           "ADVANCED",
+	"2. 🌿 Gestion intelligente des branches":                    "2. 🌿 Smart branch management",
+	"3. 📜 Historique interactif":                                "3. 📜 Interactive history",
+	"4. 📊 Analyse du projet":                                    "4. 📊 Project insights",
+	"10. 🕘 Journal des actions (filtre, export JSON)":           "10. 🕘 Action journal (filter, JSON export)",
+	"15. 📦 Stash (mettre de côté, réappliquer)":                 "15. 📦 Stash (set aside, reapply)",
+	"16. ⚔️ Résoudre les conflits (nous, eux, les deux, éditeur)": "16. ⚔️ Resolve conflicts (ours, theirs, both, editor)",
+	"17. ✏️ Rebase interactif (réordonner, squash, reword, drop)": "17. ✏️ Interactive rebase (reorder, squash, reword, drop)",
+	"18. 🏷️ Tags et releases (versions, notes)": "18. 🏷️ Tags and releases (versions, notes)",
+	"5. 📁 Changer de répertoire":                                "5. 📁 Change directory",
+	"6. 🔧 Initialiser Git":                                      "6. 🔧 Initialize Git",
+	"12. ⚙️ Paramètres":                                         "12. ⚙️ Settings",
+	"SYNCHRONISATION":                                           "SYNC",
+	"7. 🌐 Dépôts distants (fetch, pull, push)":                  "7. 🌐 Remotes (fetch, pull, push)",
+	"⚠️ Pas un dépôt Git":                                       "⚠️ Not a Git repository",
+	"ACTIONS DISPONIBLES":                                       "AVAILABLE ACTIONS",
+	"\n0. ❌ Quitter":                                            "\n0. ❌ Quit",
+	"\n💫 Choisissez une action: ":                               "\n💫 Choose an action: ",
+	"🎯 Git Assistant Intelligent démarré!":                      "🎯 Smart Git Assistant started!",
+	"SÉLECTION DU RÉPERTOIRE DE TRAVAIL":                        "WORKING DIRECTORY SELECTION",
+	"Répertoire actuel: %s\n":                                   "Current directory: %s\n",
+	"Entrez le chemin du dossier de travail: ":                  "Enter the working directory path: ",
+	"Continuer avec le répertoire actuel?":                      "Continue with the current directory?",
+	"\n👋 Au revoir!":                                            "\n👋 Goodbye!",
+	"❌ Cette action nécessite un dépôt Git":                     "❌ This action requires a Git repository",
+	"❌ Option invalide!":                                        "❌ Invalid option!",
+	"\n⏸️ Appuyez sur Entrée pour continuer...":                 "\n⏸️ Press Enter to continue...",
+	
+	// Mode plein écran
+	"Statut":     "Status",
+	"Historique": "Log",
+	"Espace indexer/désindexer · a tout · c commiter · Entrée diff · Tab panneau · r rafraîchir · q quitter": "Space stage/unstage · a all · c commit · Enter diff · Tab pane · r refresh · q quit",
+	"Entrée changer de branche · m fusionner · Tab panneau · r rafraîchir · q quitter":                       "Enter switch branch · m merge · Tab pane · r refresh · q quit",
+	"Entrée détails du commit · Tab panneau · r rafraîchir · q quitter":                                      "Enter commit details · Tab pane · r refresh · q quit",
+	"🔄 Rafraîchi": "🔄 Refreshed",
+	"❌ Choisissez une autre branche que la branche courante": "❌ Pick a branch other than the current one",
+	"🔀 Fusionner '%s' dans la branche courante? (%s) ":       "🔀 Merge '%s' into the current branch? (%s) ",
+	"↑↓ PgUp PgDn défiler · q retour":                        "↑↓ PgUp PgDn scroll · q back",
+	
+	// Sélecteur
+	"Aperçu": "Preview",
+	"Tapez pour filtrer · ↑↓ choisir · Entrée valider · Échap annuler": "Type to filter · ↑↓ move · Enter select · Esc cancel",
+	
+	// Configuration
+	"défaut":                                            "default",
+	"auto, exec ou native":                              "auto, exec or native",
+	"commits affichés dans l'historique":                "commits shown in the history",
+	"jours des points chauds (0: tout l'historique)":    "hot spot window in days (0: whole history)",
+	"merge, ff-only, no-ff, squash ou rebase":           "merge, ff-only, no-ff, squash or rebase",
+	"soft, mixed ou hard":                               "soft, mixed or hard",
+	"couleurs ANSI":                                     "ANSI colors",
+	"effacer l'écran avant le menu":                     "clear the screen before the menu",
+	"attendre Entrée après chaque action":               "wait for Enter after each action",
+	"auto (LANG), fr ou en":                             "auto (LANG), fr or en",
+	"backend inconnu: %s (auto, exec ou native)":        "unknown backend: %s (auto, exec or native)",
+	"nombre entre 1 et 1000 attendu: %s":                "number between 1 and 1000 expected: %s",
+	"nombre entre 0 et 36500 attendu: %s":               "number between 0 and 36500 expected: %s",
+	"mode de reset inconnu: %s (soft, mixed ou hard)":   "unknown reset mode: %s (soft, mixed or hard)",
+	"langue inconnue: %s (auto, fr ou en)":              "unknown language: %s (auto, fr or en)",
+	"booléen attendu: %s":                               "boolean expected: %s",
+	"%s: %s: valeur simple attendue":                    "%s: %s: single value expected",
+	"%s: clé inconnue '%s'":                             "%s: unknown key '%s'",
+	"%s: presets n°%d: name et message requis":          "%s: presets #%d: name and message required",
+	"%s: branch_types n°%d: name et prefix requis":      "%s: branch_types #%d: name and prefix required",
+	"%s: tableau inconnu [[%s]]":                        "%s: unknown array [[%s]]",
+	"ligne %d: table [%s] définie deux fois":            "line %d: table [%s] defined twice",
+	"ligne %d: en-tête de table invalide":               "line %d: invalid table header",
+	"ligne %d: 'clé = valeur' attendu":                  "line %d: 'key = value' expected",
+	"ligne %d: clé '%s' définie deux fois":              "line %d: key '%s' defined twice",
+	"ligne %d: %v":                                      "line %d: %v",
+	"chaîne invalide: %s":                               "invalid string: %s",
+	"tableau invalide: %s":                              "invalid array: %s",
+	"seuls les tableaux de chaînes sont acceptés: %s":   "only arrays of strings are supported: %s",
+	"valeur invalide: %s":                               "invalid value: %s",
+	"configuration invalide après modification: %v":     "invalid configuration after edit: %v",
+	"⚠️ Configuration: %v\n":                            "⚠️ Configuration: %v\n",
+	"2. 📁 Dépôt (%s)\n":                                 "2. 📁 Repository (%s)\n",
+	"Enregistrer dans (1-2, défaut 2): ":                "Save to (1-2, default 2): ",
+	"PARAMÈTRES":                                        "SETTINGS",
+	"absent":                                            "missing",
+	"présent":                                           "present",
+	"p. Messages prédéfinis (%d) (%s)\n":                "p. Preset messages (%d) (%s)\n",
+	"b. Types de branches (%d) (%s)\n":                  "b. Branch types (%d) (%s)\n",
+	"\nParamètre à modifier (Entrée pour quitter): ":    "\nSetting to change (Enter to quit): ",
+	"✏️ %s (%s), vide pour retirer du fichier: ":        "✏️ %s (%s), empty to remove from the file: ",
+	"✅ %s retiré de %s\n":                               "✅ %s removed from %s\n",
+	"✅ %s = %s enregistré dans %s\n":                    "✅ %s = %s saved to %s\n",
+	"ℹ️ La variable %s reste prioritaire\n":             "ℹ️ The %s variable still takes precedence\n",
+	"a pour ajouter, d <n°> pour supprimer: ":           "a to add, d <n> to delete: ",
+	"🏷️ Nom court (pour --preset): ":                    "🏷️ Short name (for --preset): ",
+	"nom et message requis":                             "name and message required",
+	"numéro invalide (au moins un message doit rester)": "invalid number (at least one message must remain)",
+	"✅ %d messages enregistrés dans %s\n":               "✅ %d messages saved to %s\n",
+	"🏷️ Nom (ex: hotfix): ":                             "🏷️ Name (e.g. hotfix): ",
+	"🔤 Préfixe (défaut %s/): ":                          "🔤 Prefix (default %s/): ",
+	"📋 Libellé du menu: ":                               "📋 Menu label: ",
+	"🔗 Premier push avec --set-upstream?":               "🔗 First push with --set-upstream?",
+	"numéro invalide (au moins un type doit rester)":    "invalid number (at least one type must remain)",
+	"✅ %d types de branches enregistrés dans %s\n":      "✅ %d branch types saved to %s\n",
+	
+	// Backends
+	"entrée de statut invalide: %q":                         "invalid status entry: %q",
+	"opération non supportée par le backend natif":          "operation not supported by the native backend",
+	"la branche '%s' existe déjà":                           "branch '%s' already exists",
+	"branche introuvable: %s":                               "branch not found: %s",
+	"la branche '%s' n'est pas entièrement fusionnée":       "branch '%s' is not fully merged",
+	"%w: fusion non fast-forward de '%s'":                   "%w: non fast-forward merge of '%s'",
+	"mode de reset inconnu: %s":                             "unknown reset mode: %s",
+	"le dépôt distant '%s' existe déjà":                     "remote '%s' already exists",
+	"%s n'est pas un dépôt Git":                             "%s is not a Git repository",
+	"chemin hors du dépôt: %s":                              "path outside the repository: %s",
+	"le chemin '%s' ne correspond à aucun fichier":          "pathspec '%s' did not match any files",
+	"rien à commiter":                                       "nothing to commit",
+	"identité inconnue: configurez user.name et user.email": "unknown identity: set user.name and user.email",
+	"des changements locaux seraient écrasés":               "local changes would be overwritten",
+	"HEAD détachée: précisez la branche":                    "detached HEAD: specify the branch",
+	
+	// Ligne de commande
+	"répertoire de travail": "working directory",
+	"backend Git: auto, exec ou native (défaut: configuration)":                   "Git backend: auto, exec or native (default: configuration)",
+	"langue des messages: fr ou en (défaut: configuration, puis LANG)":            "message language: fr or en (default: configuration, then LANG)",
+	"❌ Langue inconnue: %s (fr ou en)\n":                                          "❌ Unknown language: %s (fr or en)\n",
+	"❌ Commande inconnue: %s\n":                                                   "❌ Unknown command: %s\n",
+	"format de sortie: text ou json":                                              "output format: text or json",
+	"❌ Format inconnu: %s (text ou json)\n":                                       "❌ Unknown format: %s (text or json)\n",
+	"nom du message prédéfini":                                                    "preset message name",
+	"message de commit":                                                           "commit message",
+	"commiter uniquement les fichiers indexés":                                    "commit only staged files",
+	"type Conventional Commits (feat, fix, docs...)":                              "Conventional Commits type (feat, fix, docs...)",
+	"portée Conventional Commits":                                                 "Conventional Commits scope",
+	"description du changement incompatible":                                      "breaking change description",
+	"Usage: gitctrl commit [--preset nom | -m message] [--staged]":                "Usage: gitctrl commit [--preset name | -m message] [--staged]",
+	"Presets disponibles:":                                                        "Available presets:",
+	"❌ --preset et -m sont incompatibles":                                         "❌ --preset and -m cannot be combined",
+	"❌ --preset et --type sont incompatibles":                                     "❌ --preset and --type cannot be combined",
+	"❌ Preset inconnu: %s\n":                                                      "❌ Unknown preset: %s\n",
+	"❌ Message invalide: %v\n":                                                    "❌ Invalid message: %v\n",
+	"forcer la suppression d'une branche non fusionnée":                           "force deletion of an unmerged branch",
+	"❌ gitctrl branch %s: nom requis\n":                                           "❌ gitctrl branch %s: name required\n",
+	"❌ Action de branche inconnue: %s\n":                                          "❌ Unknown branch action: %s\n",
+	"nombre de commits":                                                           "number of commits",
+	"❌ -n doit être supérieur à 0":                                                "❌ -n must be greater than 0",
+	"Usage: gitctrl remote [list] | gitctrl remote add <nom> <url>":               "Usage: gitctrl remote [list] | gitctrl remote add <name> <url>",
+	"pull: rebaser au lieu de fusionner":                                          "pull: rebase instead of merge",
+	"push: définir la branche suivie":                                             "push: set the upstream branch",
+	"❌ gitctrl %s: un seul dépôt distant attendu\n":                               "❌ gitctrl %s: a single remote expected\n",
+	"message de commit (généré par défaut)":                                       "commit message (generated by default)",
+	"branche %-8s %s (%s)\n":                                                      "branch  %-8s %s (%s)\n",
+	"modifier le fichier global":                                                  "edit the global file",
+	"Usage: gitctrl config [list] | gitctrl config set [--global] <clé> <valeur>": "Usage: gitctrl config [list] | gitctrl config set [--global] <key> <value>",
+	"Clés:":                             "Keys:",
+	"Usage: gitctrl undo [list | <n°>]": "Usage: gitctrl undo [list | <n>]",
+	"aucun instantané n°%d (voir gitctrl undo list)":                                        "no snapshot #%d (see gitctrl undo list)",
+	"type d'action (commit, branch, merge, reset...)":                                       "action type (commit, branch, merge, reset...)",
+	"depuis une date (AAAA-MM-JJ, RFC 3339) ou une durée (12h, 7d)":                         "since a date (YYYY-MM-DD, RFC 3339) or a duration (12h, 7d)",
+	"début des points chauds: date (AAAA-MM-JJ, RFC 3339), durée (90d) ou all":              "hot spots start: date (YYYY-MM-DD, RFC 3339), duration (90d) or all",
+	"nombre d'entrées, les plus récentes (0 = toutes)":                                      "number of entries, most recent first (0 = all)",
+	"Usage: gitctrl journal [--type <type>] [--since <date>] [-n <nombre>] [--format json]": "Usage: gitctrl journal [--type <type>] [--since <date>] [-n <count>] [--format json]",
+	"mettre de côté les changements locaux qui bloquent le changement de branche":           "stash the local changes that block the branch switch",
+	"message de l'entrée":             "entry message",
+	"inclure les fichiers non suivis": "include untracked files",
+	"Usage: gitctrl stash [list | save [-u] [-m message] | show|apply|pop|drop [n] | branch <nom> [n]]": "Usage: gitctrl stash [list | save [-u] [-m message] | show|apply|pop|drop [n] | branch <name> [n]]",
+	"❌ Action de stash inconnue: %s\n": "❌ Unknown stash action: %s\n",
+	"Usage: gitctrl conflicts [list | show <fichier> | ours|theirs|both <fichier>... | continue | skip | abort]": "Usage: gitctrl conflicts [list | show <file> | ours|theirs|both <file>... | continue | skip | abort]",
+	"aucune opération en cours": "no operation in progress",
+	"une fusion ne se passe pas: poursuivez-la ou abandonnez-la": "a merge cannot be skipped: continue or abort it",
+	"noter la provenance dans le message": "record the origin in the message",
+	"Usage: gitctrl cherry-pick [-x] <commit>...": "Usage: gitctrl cherry-pick [-x] <commit>...",
+	"parent principal d'un commit de fusion (1 pour le premier)": "mainline parent of a merge commit (1 for the first)",
+	"Usage: gitctrl revert [-m parent] <commit>": "Usage: gitctrl revert [-m parent] <commit>",
+	"message du tag (défaut: notes générées)": "tag message (default: generated notes)",
+	"Usage: gitctrl tag [list [--format json] | create [-m message] <nom> [commit] | delete <nom>]": "Usage: gitctrl tag [list [--format json] | create [-m message] <name> [commit] | delete <name>]",
+	"incrément: major, minor ou patch (défaut: d'après les commits)": "increment: major, minor or patch (default: from the commits)",
+	"afficher la version et les notes sans créer le tag": "show the version and notes without creating the tag",
+	"Usage: gitctrl release [--bump major|minor|patch] [--dry-run]": "Usage: gitctrl release [--bump major|minor|patch] [--dry-run]",
+	"début de l'intervalle, exclu (défaut: dernier tag)": "start of the range, excluded (default: latest tag)",
+	"fin de l'intervalle": "end of the range",
+	"titre de la section (défaut: --to, ou « Non publié » pour HEAD)": "section title (default: --to, or \"Unreleased\" for HEAD)",
+	"format de sortie: markdown, text ou json": "output format: markdown, text or json",
+	"ajouter la section Markdown en tête de ce fichier (ex: CHANGELOG.md)": "add the Markdown section at the top of this file (e.g. CHANGELOG.md)",
+	"Usage: gitctrl changelog [--from ref] [--to ref] [--title titre] [--format markdown|text|json] [--prepend fichier]": "Usage: gitctrl changelog [--from ref] [--to ref] [--title title] [--format markdown|text|json] [--prepend file]",
+	"❌ Format inconnu: %s (markdown, text ou json)\n": "❌ Unknown format: %s (markdown, text or json)\n",
+	"❌ --prepend écrit toujours du Markdown: retirez --format": "❌ --prepend always writes Markdown: remove --format",
+	"fusion: merge, ff-only, no-ff, squash ou rebase": "merge: merge, ff-only, no-ff, squash or rebase",
+	"fusion: afficher l'aperçu sans fusionner": "merge: show the preview without merging",
+	"❌ Stratégie de fusion inconnue: %s\n": "❌ Unknown merge strategy: %s\n",
+	"❌ Action inconnue: %s\n": "❌ Unknown action: %s\n",
+	cliUsage: `Usage: gitctrl [-C directory] [--backend auto|exec|native] [--lang fr|en] <command> [options]
+
+Without a command, the interactive menu starts.
+The backend can also be set with the GITCTRL_BACKEND variable
+or the backend configuration key; the language with ui.lang, GITCTRL_LANG
+or LANG.
+
+Commands:
+  status [--format json]          Smart repository status
+  commit [--preset name|-m msg] [--staged]
+                                  Stage everything and commit (--staged: index only)
+  commit --type feat [--scope s] [--breaking "desc"] -m subject
+                                  Validated Conventional Commits commit
+  branch list [--format json]     List branches
+  branch feature <name>           Create and check out feature/<name>
+  branch bugfix <description>     Create and check out bugfix/<description>
+  branch <type> <name>            Same for the other configured types
+  branch switch [--autostash] <name>
+                                  Switch branch (--autostash: stash, then reapply
+                                  the local changes that get in the way)
+  branch delete [--force] <name>  Delete a branch
+  branch merge [--strategy s] <name>
+                                  Merge a branch into the current branch (merge,
+                                  ff-only, no-ff, squash or rebase)
+  branch merge --preview [--format json] <name>
+                                  Incoming commits, touched files and expected
+                                  conflicts, without merging
+  log [-n N] [--format json]      Show the history (log.depth commits by default)
+  remote [list]                   List remotes
+  remote add <name> <url>         Add a remote
+  fetch [remote]                  Fetch from a remote (all by default)
+  pull [--rebase] [remote]        Update the current branch
+  push [-u] [remote]              Push the current branch (-u is automatic
+                                  for new feature/ and bugfix/ branches)
+  sync [-m message]               Stage everything, commit, pull --rebase and push;
+                                  rolls everything back if a step fails
+  insights [--since date] [--format json]
+                                  Project insights (hot spots since insights.churn_days)
+  undo [list | <n>]               Restore the state before the last reset, branch
+                                  deletion, merge, rebase, cherry-pick or revert
+                                  (list: snapshots)
+  journal [--type t] [--since date] [-n N] [--format json]
+                                  Repository action journal (.git/gitctrl/journal.jsonl)
+  stash [list]                    List stashed entries
+  stash save [-u] [-m message]    Stash changes (-u: untracked files too)
+  stash show|apply|pop|drop [n]   Diff, apply, pop or drop stash@{n}
+  stash branch <name> [n]         Create a branch from stash@{n} (0 by default)
+  conflicts [list]                Conflicted files and the operation in progress
+  conflicts show <file>           The file's conflicts: ours, base and theirs
+  conflicts ours|theirs|both <file>...
+                                  Resolve by keeping our version, theirs or both
+  conflicts continue|abort        Finish or abort the merge (or rebase...)
+  conflicts skip                  Skip the commit that stopped a rebase,
+                                  cherry-pick or revert
+  cherry-pick [-x] <commit>...    Apply commits onto the current branch
+                                  (-x: record where they come from)
+  revert [-m parent] <commit>     Undo a commit with a new commit (-m: mainline
+                                  parent of a merge commit)
+  tag [list] [--format json]      Tags, most recent version first
+  tag create [-m message] <name> [commit]
+                                  Create an annotated tag (default message:
+                                  notes since the previous release)
+  tag delete <name>               Delete a tag
+  release [--bump major|minor|patch] [--dry-run]
+                                  Tag HEAD with the next version, suggested from
+                                  the commit types since the latest release
+  changelog [--from ref] [--to ref] [--format markdown|text|json]
+                                  Commits grouped by type, from the latest tag
+                                  to HEAD by default
+  changelog --prepend CHANGELOG.md
+                                  Add the section at the top of the file
+  tui                             Full-screen mode: status, branches and log
+  config [list]                   Show the configuration and where values come from
+  config set [--global] <key> <value>
+                                  Edit .gitctrl.toml (or the global file)
+  help                            Show this help
+`,
+}
+
+// Backends Git
+
+// Entrée du statut: Index et Worktree reprennent les lettres de git status
+// (' ' inchangé, M, T, A, D, R, C, U, ?); Score est la similarité d'un renommage
+type StatusEntry struct {
+	Index     byte
+	Worktree  byte
+	Path      string
+	OrigPath  string
+	Score     int
+	Conflict  bool
+	Submodule *SubmoduleState
+}
+
+// État d'un sous-module modifié
+type SubmoduleState struct {
+	CommitChanged bool `json:"commit_changed"`
+	Modified      bool `json:"modified"`
+	Untracked     bool `json:"untracked"`
+}
+
+// Statut complet: en-têtes de branche et entrées
+type RepoStatus struct {
+	Branch   string // vide si HEAD est détachée
+	Head     string // vide avant le premier commit
+	Upstream UpstreamInfo
+	Entries  []StatusEntry
+}
+
+// Analyse la sortie de git status --porcelain=v2 -z --branch.
+// Avec -z les chemins ne sont jamais entre guillemets.
+func parsePorcelainV2(output string) (RepoStatus, error) {
+	var status RepoStatus
+	records := strings.Split(output, "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if record == "" {
+			continue
+		}
+		
+		switch record[0] {
+		case '#':
+			parseBranchHeader(&status, record)
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <chemin>
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) != 9 || len(fields[1]) != 2 {
+				return status, fmt.Errorf(tr("entrée de statut invalide: %q"), record)
+			}
+			status.Entries = append(status.Entries, newStatusEntry(fields[1], fields[2], fields[8]))
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <R|C><score> <chemin>\0<origine>
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) != 10 || len(fields[1]) != 2 || len(fields[8]) < 2 || i+1 >= len(records) {
+				return status, fmt.Errorf(tr("entrée de statut invalide: %q"), record)
+			}
+			entry := newStatusEntry(fields[1], fields[2], fields[9])
+			entry.Score, _ = strconv.Atoi(fields[8][1:])
+			i++
+			entry.OrigPath = records[i]
+			status.Entries = append(status.Entries, entry)
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <chemin>
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) != 11 || len(fields[1]) != 2 {
+				return status, fmt.Errorf(tr("entrée de statut invalide: %q"), record)
+			}
+			entry := newStatusEntry(fields[1], fields[2], fields[10])
+			entry.Conflict = true
+			status.Entries = append(status.Entries, entry)
+		case '?':
+			status.Entries = append(status.Entries, StatusEntry{Index: '?', Worktree: '?', Path: record[2:]})
+		}
+	}
+	return status, nil
+}
+
+func newStatusEntry(xy, submodule, path string) StatusEntry {
+	entry := StatusEntry{Index: statusCode(xy[0]), Worktree: statusCode(xy[1]), Path: path}
+	// N... pour un fichier, S<c><m><u> pour un sous-module
+	if len(submodule) == 4 && submodule[0] == 'S' {
+		entry.Submodule = &SubmoduleState{
+			CommitChanged: submodule[1] == 'C',
+			Modified:      submodule[2] == 'M',
+			Untracked:     submodule[3] == 'U',
+		}
+	}
+	return entry
+}
+
+// Le format v2 note "." ce que le format v1 note par une espace
+func statusCode(code byte) byte {
+	if code == '.' {
+		return ' '
+	}
+	return code
+}
+
+func parseBranchHeader(status *RepoStatus, record string) {
+	fields := strings.Fields(record)
+	if len(fields) < 3 {
+		return
+	}
+	switch fields[1] {
+	case "branch.oid":
+		if fields[2] != "(initial)" {
+			status.Head = fields[2]
+		}
+	case "branch.head":
+		if fields[2] != "(detached)" {
+			status.Branch = fields[2]
+		}
+	case "branch.upstream":
+		status.Upstream.Name = fields[2]
+	case "branch.ab":
+		if len(fields) == 4 {
+			status.Upstream.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+			status.Upstream.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+		}
+	}
+}
+
+type CommitInfo struct {
+	Hash      string    `json:"hash"`
+	ShortHash string    `json:"short_hash"`
+	Author    string    `json:"author"`
+	Email     string    `json:"email"`
+	Date      time.Time `json:"date"`
+	Subject   string    `json:"subject"`
+	Parents   []string  `json:"parents"`
+	Refs      []string  `json:"refs"`
+	Body      string    `json:"-"` // message sans la ligne de sujet
+}
+
+// Branche locale et son dernier commit
+type BranchInfo struct {
+	Name    string    `json:"name"`
+	Current bool      `json:"current"`
+	Hash    string    `json:"hash"`
+	Subject string    `json:"subject"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+}
+
+// Statistiques de la base d'objets (tailles en octets)
+type ObjectStats struct {
+	Count     int   `json:"count"`
+	LooseSize int64 `json:"loose_size_bytes"`
+	InPack    int   `json:"in_pack"`
+	Packs     int   `json:"packs"`
+	PackSize  int64 `json:"pack_size_bytes"`
+}
+
+type RemoteInfo struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Branche suivie par la branche courante; Name est vide s'il n'y en a pas
+type UpstreamInfo struct {
+	Name   string `json:"name"`
+	Ahead  int    `json:"ahead"`
+	Behind int    `json:"behind"`
+}
+
+// Entrée de la pile de stash; Ref est de la forme stash@{n}
+type StashInfo struct {
+	Ref     string
+	Hash    string
+	Branch  string
+	Message string
+	Date    time.Time
+}
+
+// Contenu d'un fichier en conflit dans les étages de l'index (1 base, 2 nous, 3 eux).
+// Un étage manque quand un côté a supprimé le fichier ou que les deux l'ont ajouté (base).
+type ConflictStages struct {
+	Base, Ours, Theirs          string
+	HasBase, HasOurs, HasTheirs bool
+}
+
+// Fichier modifié entre deux commits; Status est la lettre de git diff --name-status (A, M, D, R...)
+type FileChange struct {
+	Status string `json:"status"`
+	Path   string `json:"path"`
+}
+
+// Aperçu d'une fusion dans la branche courante, calculé sans toucher à l'arbre de travail
+type MergePreview struct {
+	Commits     []CommitInfo `json:"commits"`   // commits qui arrivent
+	Files       []FileChange `json:"files"`     // fichiers touchés depuis l'ancêtre commun
+	Conflicts   []string     `json:"conflicts"` // fichiers qui entreraient en conflit
+	UpToDate    bool         `json:"up_to_date"`
+	FastForward bool         `json:"fast_forward"`
+}
+
+// Ligne du todo d'un rebase interactif. Message remplace celui du commit pour reword.
+type RebaseStep struct {
+	Action  string
+	Hash    string
+	Subject string
+	Message string
+}
+
+// Tag et commit désigné. Message est celui du tag annoté, ou du commit pour un tag léger;
+// Date est celle du tag annoté, ou du commit.
+type TagInfo struct {
+	Name      string    `json:"name"`
+	Commit    string    `json:"commit"`
+	Annotated bool      `json:"annotated"`
+	Message   string    `json:"message"`
+	Date      time.Time `json:"date"`
+}
+
+// Lignes ajoutées et supprimées d'un fichier par un commit; Git ne compte pas les lignes
+// des fichiers binaires
+type FileStat struct {
+	Path    string `json:"path"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Binary  bool   `json:"binary"`
+}
+
+// Commit (hors fusions) avec ses fichiers modifiés; l'identité de l'auteur passe par .mailmap
+type CommitStats struct {
+	Hash   string
+	Author string
+	Email  string
+	Date   time.Time
+	Files  []FileStat
+}
+
+// Checkout refusé parce qu'il écraserait des changements locaux
+var errLocalChanges error = localizedError("des changements locaux seraient écrasés")
+
+// GitBackend regroupe les opérations Git utilisées par l'assistant
+type GitBackend interface {
+	Name() string
+	Init() error
+	CurrentBranch() (string, error)
+	CommitCount() (int, error)
+	Status() (RepoStatus, error)
+	Log(limit int) ([]CommitInfo, error)
+	// Comme Log, depuis une révision quelconque
+	LogFrom(rev string, limit int) ([]CommitInfo, error)
+	LogRange(from, to string) ([]CommitInfo, error)
+	// Fichiers modifiés par chaque commit depuis since (tout l'historique si since est nul)
+	LogStats(since time.Time) ([]CommitStats, error)
+	ReadCommit(rev string) (CommitInfo, error)
+	// En-tête et fichiers modifiés d'un commit (git show --stat), puis diff avec son premier parent
+	ShowCommit(rev string) (string, error)
+	CommitDiff(rev string) (string, error)
+	// Commits dont le message contient query sans tenir compte de la casse,
+	// ou qui modifient un chemin contenant query si byPath
+	SearchCommits(query string, byPath bool) ([]CommitInfo, error)
+	Branches() ([]BranchInfo, error)
+	Add(paths ...string) error
+	Unstage(paths ...string) error
+	Diff(path string, cached bool) (string, error)
+	ApplyToIndex(patch string, reverse bool) error
+	Commit(message string) error
+	Checkout(ref string) error
+	CreateBranch(name, startPoint string) error
+	DeleteBranch(name string, force bool) error
+	Merge(branch, strategy string) error
+	// Nil si le backend sait réaliser cette fusion, vérifié avant l'instantané d'annulation
+	CanMerge(branch, strategy string) error
+	PreviewMerge(branch string) (MergePreview, error)
+	Rebase(upstream, branch string) error
+	CherryPick(hashes []string, recordOrigin bool) error
+	Revert(hash string, mainline int) error
+	Reset(mode, target string) error
+	LsFiles() ([]string, error)
+	// Contenu des fichiers suivis à HEAD, par ordre de chemin (liens symboliques et sous-modules exclus)
+	WalkHeadBlobs(visit func(path string, data []byte) error) error
+	CountObjects() (ObjectStats, error)
+	
+	// Dépôts distants
+	Remotes() ([]RemoteInfo, error)
+	AddRemote(name, url string) error
+	Fetch(remote string) error
+	Pull(remote, branch string, rebase bool) error
+	Push(remote, branch string, setUpstream bool) error
+	Upstream() (UpstreamInfo, error)
+	AbortRebase() error
+	
+	// Références et instantanés des changements locaux (annulation)
+	Refs(prefix string) (map[string]string, error)
+	UpdateRef(name, hash string) error
+	DeleteRef(name string) error
+	SnapshotWorktree() (string, error)
+	RestoreSnapshot(hash string) error
+	
+	// Stash
+	Stashes() ([]StashInfo, error)
+	StashSave(message string, includeUntracked bool) error
+	StashShow(ref string) (string, error)
+	StashApply(ref string, pop bool) error
+	StashDrop(ref string) error
+	StashBranch(name, ref string) error
+	
+	// Conflits et opérations interrompues (merge, rebase, cherry-pick, revert)
+	ConflictStages(path string) (ConflictStages, error)
+	MergeFile(ours, base, theirs string) (string, error)
+	ContinueOperation(op string) error
+	SkipOperation(op string) error
+	AbortOperation(op string) error
+	
+	// Rebase interactif: les étapes remplacent la liste que Git propose
+	RebaseInteractive(onto string, steps []RebaseStep) error
+	
+	// Tags; un message vide donne un tag léger
+	Tags() ([]TagInfo, error)
+	CreateTag(name, target, message string) error
+	DeleteTag(name string) error
+}
+
+const (
+	backendAuto   = "auto"
+	backendExec   = "exec"
+	backendNative = "native"
+)
+
+// Choisit le backend; "auto" préfère le binaire git s'il fonctionne
+func newGitBackend(kind string, dir func() string) (GitBackend, error) {
+	switch kind {
+	case "", backendAuto:
+		if gitBinaryAvailable() {
+			return &execBackend{dir: dir}, nil
+		}
+		return &nativeBackend{dir: dir}, nil
+	case backendExec:
+		return &execBackend{dir: dir}, nil
+	case backendNative:
+		return &nativeBackend{dir: dir}, nil
+	default:
+		return nil, fmt.Errorf(tr("backend inconnu: %s (auto, exec ou native)"), kind)
+	}
+}
+
+func gitBinaryAvailable() bool {
+	if _, err := exec.LookPath("git"); err != nil {
+		return false
+	}
+	return exec.Command("git", "--version").Run() == nil
+}
+
+// Backend exec : appelle le binaire git et analyse sa sortie
+
+type execBackend struct {
+	dir func() string
+}
+
+func (eb *execBackend) Name() string {
+	return backendExec
+}
+
+func (eb *execBackend) run(args ...string) (string, error) {
+	return eb.runEnv(nil, args...)
+}
+
+// Comme run, avec des variables d'environnement en plus
+func (eb *execBackend) runEnv(env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = eb.dir()
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return string(output), fmt.Errorf("%v: %s", err, msg)
+		}
+	}
+	return string(output), err
+}
+
+// Sortie standard seule, pour les formats analysés
+func (eb *execBackend) output(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = eb.dir()
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return string(output), fmt.Errorf("%v: %s", err, msg)
+		}
+	}
+	return string(output), err
+}
+
+func (eb *execBackend) Init() error {
+	_, err := eb.run("init")
+	return err
+}
+
+func (eb *execBackend) CurrentBranch() (string, error) {
+	output, err := eb.output("branch", "--show-current")
+	return strings.TrimSpace(output), err
+}
+
+func (eb *execBackend) hasHead() bool {
+	_, err := eb.output("rev-parse", "--verify", "-q", "HEAD")
+	return err == nil
+}
+
+func (eb *execBackend) CommitCount() (int, error) {
+	if !eb.hasHead() {
+		return 0, nil
+	}
+	output, err := eb.output("rev-list", "--count", "HEAD")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(output))
+}
+
+func (eb *execBackend) Status() (RepoStatus, error) {
+	output, err := eb.output("status", "--porcelain=v2", "-z", "--branch", "--untracked-files=all")
+	if err != nil {
+		return RepoStatus{}, err
+	}
+	return parsePorcelainV2(output)
+}
+
+func (eb *execBackend) Log(limit int) ([]CommitInfo, error) {
+	if !eb.hasHead() {
+		return nil, nil
+	}
+	args := []string{"log", logFormat}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-%d", limit))
+	}
+	output, err := eb.output(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLogRecords(output), nil
+}
+
+func (eb *execBackend) LogFrom(rev string, limit int) ([]CommitInfo, error) {
+	args := []string{"log", logFormat}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-%d", limit))
+	}
+	output, err := eb.output(append(args, rev, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLogRecords(output), nil
+}
+
+func (eb *execBackend) LogStats(since time.Time) ([]CommitStats, error) {
+	if !eb.hasHead() {
+		return nil, nil
+	}
+	args := []string{"log", "--no-merges", "--no-renames", "--numstat", "-z", "--format=%x1e%H%x1f%aN%x1f%aE%x1f%at"}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	output, err := eb.output(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseNumstatRecords(output), nil
+}
+
+// Commits séparés par \x1e: en-tête terminé par \0, puis une entrée
+// "ajoutées\tsupprimées\tchemin" par fichier, terminée par \0 ("-" pour un binaire)
+func parseNumstatRecords(output string) []CommitStats {
+	var commits []CommitStats
+	for _, record := range strings.Split(output, "\x1e") {
+		header, entries, _ := strings.Cut(record, "\x00")
+		fields := strings.Split(header, "\x1f")
+		if len(fields) < 4 {
+			continue
+		}
+		timestamp, _ := strconv.ParseInt(fields[3], 10, 64)
+		commit := CommitStats{Hash: fields[0], Author: fields[1], Email: fields[2], Date: time.Unix(timestamp, 0)}
+		for _, entry := range strings.Split(entries, "\x00") {
+			parts := strings.SplitN(strings.TrimLeft(entry, "\n"), "\t", 3)
+			if len(parts) < 3 {
+				continue
+			}
+			file := FileStat{Path: parts[2], Binary: parts[0] == "-"}
+			file.Added, _ = strconv.Atoi(parts[0])
+			file.Deleted, _ = strconv.Atoi(parts[1])
+			commit.Files = append(commit.Files, file)
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// Champs séparés par \x1f, commits par \x1e; lus par parseLogRecords
+const logFormat = "--pretty=format:%H%x1f%h%x1f%an%x1f%ae%x1f%at%x1f%P%x1f%D%x1f%s%x1f%b%x1e"
+
+// Commits accessibles depuis to mais pas depuis from (tous si from est vide),
+// du plus récent au plus ancien
+func (eb *execBackend) LogRange(from, to string) ([]CommitInfo, error) {
+	revision := to
+	if from != "" {
+		revision = from + ".." + to
+	}
+	output, err := eb.output("log", logFormat, revision)
+	if err != nil {
+		return nil, err
+	}
+	return parseLogRecords(output), nil
+}
+
+func (eb *execBackend) ReadCommit(rev string) (CommitInfo, error) {
+	output, err := eb.output("log", "-1", logFormat, rev+"^{commit}", "--")
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	commits := parseLogRecords(output)
+	if len(commits) == 0 {
+		return CommitInfo{}, fmt.Errorf(tr("commit introuvable: %s"), rev)
+	}
+	return commits[0], nil
+}
+
+func (eb *execBackend) ShowCommit(rev string) (string, error) {
+	return eb.output("show", "--stat", "--pretty=format:%h - %s%n%an <%ae>%n%ad%n", rev)
+}
+
+func (eb *execBackend) CommitDiff(rev string) (string, error) {
+	output, err := eb.output("diff", rev+"^", rev)
+	if err != nil {
+		// Premier commit: pas de parent, git show donne tout son contenu
+		return eb.output("show", "--format=", rev)
+	}
+	return output, nil
+}
+
+func (eb *execBackend) SearchCommits(query string, byPath bool) ([]CommitInfo, error) {
+	if !eb.hasHead() {
+		return nil, nil
+	}
+	args := []string{"log", logFormat, "--regexp-ignore-case", "--fixed-strings", "--grep=" + query}
+	if byPath {
+		args = []string{"log", logFormat, "--", "*" + query + "*"}
+	}
+	output, err := eb.output(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLogRecords(output), nil
+}
+
+func parseLogRecords(output string) []CommitInfo {
+	var commits []CommitInfo
+	for _, record := range strings.Split(output, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		fields := strings.Split(record, "\x1f")
+		if len(fields) < 8 {
+			continue
+		}
+		timestamp, _ := strconv.ParseInt(fields[4], 10, 64)
+		commit := CommitInfo{
+			Hash:      fields[0],
+			ShortHash: fields[1],
+			Author:    fields[2],
+			Email:     fields[3],
+			Date:      time.Unix(timestamp, 0),
+			Parents:   strings.Fields(fields[5]),
+			Subject:   fields[7],
+		}
+		if len(fields) > 8 {
+			commit.Body = strings.TrimSpace(fields[8])
+		}
+		if fields[6] != "" {
+			commit.Refs = strings.Split(fields[6], ", ")
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+func (eb *execBackend) Branches() ([]BranchInfo, error) {
+	output, err := eb.output("for-each-ref", "--format=%(HEAD)%1f%(refname:short)%1f%(objectname:short)%1f%(authorname)%1f%(authordate:unix)%1f%(contents:subject)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	
+	var branches []BranchInfo
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) < 6 {
+			continue
+		}
+		timestamp, _ := strconv.ParseInt(fields[4], 10, 64)
+		branches = append(branches, BranchInfo{
+			Name:    fields[1],
+			Current: fields[0] == "*",
+			Hash:    fields[2],
+			Author:  fields[3],
+			Date:    time.Unix(timestamp, 0),
+			Subject: fields[5],
+		})
+	}
+	return branches, nil
+}
+
+func (eb *execBackend) Add(paths ...string) error {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	_, err := eb.run(append([]string{"add", "--"}, paths...)...)
+	return err
+}
+
+// Sans chemin, tout l'index revient à HEAD
+func (eb *execBackend) Unstage(paths ...string) error {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	args := append([]string{"reset", "-q", "--"}, paths...)
+	if !eb.hasHead() {
+		// Avant le premier commit, il n'y a pas de HEAD vers laquelle revenir
+		args = append([]string{"rm", "-r", "-q", "--cached", "--ignore-unmatch", "--"}, paths...)
+	}
+	_, err := eb.run(args...)
+	return err
+}
+
+func (eb *execBackend) Diff(path string, cached bool) (string, error) {
+	args := []string{"diff", "--no-color", "--no-ext-diff"}
+	if cached {
+		args = append(args, "--cached")
+	}
+	return eb.output(append(args, "--", path)...)
+}
+
+func (eb *execBackend) ApplyToIndex(patch string, reverse bool) error {
+	args := []string{"apply", "--cached"}
+	if reverse {
+		args = append(args, "--reverse")
+	}
+	cmd := exec.Command("git", append(args, "-")...)
+	cmd.Dir = eb.dir()
+	cmd.Stdin = strings.NewReader(patch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (eb *execBackend) Commit(message string) error {
+	_, err := eb.run("commit", "-m", message)
+	return err
+}
+
+func (eb *execBackend) Checkout(ref string) error {
+	return eb.checkout(ref)
+}
+
+func (eb *execBackend) CreateBranch(name, startPoint string) error {
+	args := []string{"-b", name}
+	if startPoint != "" {
+		args = append(args, startPoint)
+	}
+	return eb.checkout(args...)
+}
+
+// Messages de git en anglais (LC_ALL=C) pour reconnaître le refus
+// d'écraser des changements locaux, suivis ou non
+func (eb *execBackend) checkout(args ...string) error {
+	output, err := eb.runEnv([]string{"LC_ALL=C"}, append([]string{"checkout"}, args...)...)
+	if err != nil && strings.Contains(output, "would be overwritten by checkout") {
+		return fmt.Errorf("%w: %s", errLocalChanges, strings.TrimSpace(output))
+	}
+	return err
+}
+
+func (eb *execBackend) DeleteBranch(name string, force bool) error {
+	deleteFlag := "-d"
+	if force {
+		deleteFlag = "-D"
+	}
+	_, err := eb.run("branch", deleteFlag, name)
+	return err
+}
+
+// Sans éditeur: le message de fusion proposé par Git est gardé
+func (eb *execBackend) Merge(branch, strategy string) error {
+	args := []string{"merge", "--no-edit"}
+	switch strategy {
+	case mergeFFOnly, mergeNoFF, mergeSquash:
+		args = append(args, "--"+strategy)
+	}
+	_, err := eb.run(append(args, branch)...)
+	return err
+}
+
+// Git réalise toutes les stratégies; un échec éventuel vient de la fusion elle-même
+func (eb *execBackend) CanMerge(branch, strategy string) error { return nil }
+
+func (eb *execBackend) PreviewMerge(branch string) (MergePreview, error) {
+	var preview MergePreview
+	output, err := eb.output("log", logFormat, "HEAD.."+branch)
+	if err != nil {
+		return preview, err
+	}
+	preview.Commits = parseLogRecords(output)
+	
+	// Sans ancêtre commun (historiques indépendants), Git refuse la fusion
+	base, err := eb.output("merge-base", "HEAD", branch)
+	if err != nil {
+		return preview, err
+	}
+	base = strings.TrimSpace(base)
+	head, err := eb.output("rev-parse", "HEAD", branch+"^{commit}")
+	if err != nil {
+		return preview, err
+	}
+	hashes := strings.Fields(head)
+	preview.UpToDate = base == hashes[1]
+	preview.FastForward = base == hashes[0] && !preview.UpToDate
+	
+	output, err = eb.output("diff", "--name-status", "-z", base, branch)
+	if err != nil {
+		return preview, err
+	}
+	preview.Files = parseNameStatus(output)
+	if preview.UpToDate || preview.FastForward {
+		return preview, nil
+	}
+	
+	// Fusion d'essai: l'arbre résultat est écrit dans la base d'objets, ni l'index
+	// ni l'arbre de travail ne changent. Le code de sortie 1 signale des conflits.
+	cmd := exec.Command("git", "merge-tree", "--write-tree", "--name-only", "--no-messages", "-z", "HEAD", branch)
+	cmd.Dir = eb.dir()
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// "<arbre>\0<fichier>\0<fichier>\0..."
+		seen := make(map[string]bool)
+		for _, path := range strings.Split(string(data), "\x00")[1:] {
+			if path != "" && !seen[path] {
+				seen[path] = true
+				preview.Conflicts = append(preview.Conflicts, path)
+			}
+		}
+		return preview, nil
+	}
+	if err != nil {
+		return preview, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return preview, nil
+}
+
+// Sortie -z de git diff --name-status: lettre, chemin, et chemin d'arrivée pour R et C
+func parseNameStatus(output string) []FileChange {
+	var changes []FileChange
+	fields := strings.Split(output, "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		status := fields[i]
+		if status == "" {
+			break
+		}
+		change := FileChange{Status: status[:1], Path: fields[i+1]}
+		if (change.Status == "R" || change.Status == "C") && i+2 < len(fields) {
+			i++
+			change.Path = fields[i+1]
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func (eb *execBackend) Rebase(upstream, branch string) error {
+	_, err := eb.run("rebase", upstream, branch)
+	return err
+}
+
+// recordOrigin ajoute "(cherry picked from commit ...)" au message (-x)
+func (eb *execBackend) CherryPick(hashes []string, recordOrigin bool) error {
+	args := []string{"cherry-pick"}
+	if recordOrigin {
+		args = append(args, "-x")
+	}
+	_, err := eb.run(append(args, hashes...)...)
+	return err
+}
+
+// mainline: parent de référence d'un commit de fusion (1 pour le premier), 0 sinon
+func (eb *execBackend) Revert(hash string, mainline int) error {
+	args := []string{"revert", "--no-edit"}
+	if mainline > 0 {
+		args = append(args, "-m", strconv.Itoa(mainline))
+	}
+	_, err := eb.run(append(args, hash)...)
+	return err
+}
+
+func (eb *execBackend) Reset(mode, target string) error {
+	_, err := eb.run("reset", "--"+mode, target)
+	return err
+}
+
+func (eb *execBackend) LsFiles() ([]string, error) {
+	output, err := eb.output("ls-files", "-z")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range strings.Split(output, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func (eb *execBackend) WalkHeadBlobs(visit func(path string, data []byte) error) error {
+	if !eb.hasHead() {
+		return nil
+	}
+	listing, err := eb.output("ls-tree", "-r", "-z", "HEAD")
+	if err != nil {
+		return err
+	}
+	// "<mode> <type> <objet>\t<chemin>"
+	var paths, hashes []string
+	for _, entry := range strings.Split(listing, "\x00") {
+		meta, path, found := strings.Cut(entry, "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) != 3 || fields[1] != "blob" || fields[0] == "120000" {
+			continue
+		}
+		paths = append(paths, path)
+		hashes = append(hashes, fields[2])
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	
+	// Un seul processus pour tous les contenus: "<objet> <type> <taille>\n<contenu>\n"
+	cmd := exec.Command("git", "cat-file", "--batch")
+	cmd.Dir = eb.dir()
+	cmd.Stdin = strings.NewReader(strings.Join(hashes, "\n") + "\n")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(stdout)
+	for _, path := range paths {
+		header, err := reader.ReadString('\n')
+		fields := strings.Fields(header)
+		if err != nil || len(fields) != 3 {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return fmt.Errorf(tr("réponse inattendue de git cat-file pour %s"), path)
+		}
+		size, _ := strconv.Atoi(fields[2])
+		data := make([]byte, size+1)
+		if _, err = io.ReadFull(reader, data); err == nil {
+			err = visit(path, data[:size])
+		}
+		if err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return err
+		}
+	}
+	return cmd.Wait()
+}
+
+func (eb *execBackend) CountObjects() (ObjectStats, error) {
+	var stats ObjectStats
+	output, err := eb.output("count-objects", "-v")
+	if err != nil {
+		return stats, err
+	}
+	// Les tailles sont exprimées en KiB
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(line, ": ")
+		if !found {
+			continue
+		}
+		n, _ := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		switch key {
+		case "count":
+			stats.Count = int(n)
+		case "size":
+			stats.LooseSize = n * 1024
+		case "in-pack":
+			stats.InPack = int(n)
+		case "packs":
+			stats.Packs = int(n)
+		case "size-pack":
+			stats.PackSize = n * 1024
+		}
+	}
+	return stats, nil
+}
+
+func (eb *execBackend) Remotes() ([]RemoteInfo, error) {
+	output, err := eb.output("remote", "-v")
+	if err != nil {
+		return nil, err
+	}
+	var remotes []RemoteInfo
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		// Une ligne (fetch) et une ligne (push) par dépôt distant
+		if len(fields) == 3 && fields[2] == "(fetch)" {
+			remotes = append(remotes, RemoteInfo{Name: fields[0], URL: fields[1]})
+		}
+	}
+	return remotes, nil
+}
+
+func (eb *execBackend) AddRemote(name, url string) error {
+	_, err := eb.run("remote", "add", name, url)
+	return err
+}
+
+func (eb *execBackend) Fetch(remote string) error {
+	args := []string{"fetch", "--prune"}
+	if remote == "" {
+		args = append(args, "--all")
+	} else {
+		args = append(args, remote)
+	}
+	_, err := eb.run(args...)
+	return err
+}
+
+func (eb *execBackend) Pull(remote, branch string, rebase bool) error {
+	args := []string{"pull", "--no-rebase", "--no-edit"}
+	if rebase {
+		args = []string{"pull", "--rebase"}
+	}
+	if remote != "" {
+		args = append(args, remote)
+		if branch != "" {
+			args = append(args, branch)
+		}
+	}
+	_, err := eb.run(args...)
+	return err
+}
+
+func (eb *execBackend) Push(remote, branch string, setUpstream bool) error {
+	args := []string{"push"}
+	if setUpstream {
+		args = append(args, "--set-upstream")
+	}
+	if remote != "" {
+		args = append(args, remote)
+		if branch != "" {
+			args = append(args, branch)
+		}
+	}
+	_, err := eb.run(args...)
+	return err
+}
+
+func (eb *execBackend) AbortRebase() error {
+	_, err := eb.run("rebase", "--abort")
+	return err
+}
+
+func (eb *execBackend) Refs(prefix string) (map[string]string, error) {
+	output, err := eb.output("for-each-ref", "--format=%(objectname) %(refname)", prefix)
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if hash, name, ok := strings.Cut(line, " "); ok {
+			refs[name] = hash
+		}
+	}
+	return refs, nil
+}
+
+func (eb *execBackend) UpdateRef(name, hash string) error {
+	_, err := eb.run("update-ref", name, hash)
+	return err
+}
+
+func (eb *execBackend) DeleteRef(name string) error {
+	_, err := eb.run("update-ref", "-d", name)
+	return err
+}
+
+// Commit de stash des changements suivis (index et arbre de travail), sans toucher
+// au dépôt ni à la liste des stashs; vide si rien n'a changé
+func (eb *execBackend) SnapshotWorktree() (string, error) {
+	output, err := eb.output("stash", "create", "gitctrl")
+	return strings.TrimSpace(output), err
+}
+
+func (eb *execBackend) RestoreSnapshot(hash string) error {
+	if _, err := eb.run("stash", "apply", "--index", hash); err == nil {
+		return nil
+	}
+	// L'index ne s'applique pas toujours: les changements restent alors non indexés
+	_, err := eb.run("stash", "apply", hash)
+	return err
+}
+
+func (eb *execBackend) Stashes() ([]StashInfo, error) {
+	output, err := eb.output("stash", "list", "--format=%gd%x1f%H%x1f%ct%x1f%gs")
+	if err != nil {
+		return nil, err
+	}
+	stashes := []StashInfo{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		stash := StashInfo{Ref: fields[0], Hash: fields[1]}
+		if seconds, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			stash.Date = time.Unix(seconds, 0)
+		}
+		// "On master: message" (stash push -m) ou "WIP on master: abc1234 sujet"
+		subject := fields[3]
+		for _, prefix := range []string{"WIP on ", "On "} {
+			subject = strings.TrimPrefix(subject, prefix)
+		}
+		stash.Branch, stash.Message, _ = strings.Cut(subject, ": ")
+		stashes = append(stashes, stash)
+	}
+	return stashes, nil
+}
+
+func (eb *execBackend) StashSave(message string, includeUntracked bool) error {
+	args := []string{"stash", "push"}
+	if includeUntracked {
+		args = append(args, "--include-untracked")
+	}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+	_, err := eb.run(args...)
+	return err
+}
+
+func (eb *execBackend) StashShow(ref string) (string, error) {
+	return eb.output("stash", "show", "--stat", "-p", "--include-untracked", ref)
+}
+
+func (eb *execBackend) StashApply(ref string, pop bool) error {
+	command := "apply"
+	if pop {
+		command = "pop"
+	}
+	_, err := eb.run("stash", command, ref)
+	return err
+}
+
+func (eb *execBackend) StashDrop(ref string) error {
+	_, err := eb.run("stash", "drop", ref)
+	return err
+}
+
+func (eb *execBackend) StashBranch(name, ref string) error {
+	_, err := eb.run("stash", "branch", name, ref)
+	return err
+}
+
+func (eb *execBackend) ConflictStages(path string) (ConflictStages, error) {
+	var stages ConflictStages
+	output, err := eb.output("ls-files", "-u", "-z", "--", path)
+	if err != nil {
+		return stages, err
+	}
+	// "<mode> <hash> <étage>\t<chemin>"
+	for _, record := range strings.Split(output, "\x00") {
+		fields := strings.Fields(strings.SplitN(record, "\t", 2)[0])
+		if len(fields) != 3 {
+			continue
+		}
+		content, err := eb.output("cat-file", "blob", fields[1])
+		if err != nil {
+			return stages, err
+		}
+		switch fields[2] {
+		case "1":
+			stages.Base, stages.HasBase = content, true
+		case "2":
+			stages.Ours, stages.HasOurs = content, true
+		case "3":
+			stages.Theirs, stages.HasTheirs = content, true
+		}
+	}
+	if !stages.HasOurs && !stages.HasTheirs {
+		return stages, fmt.Errorf(tr("%s n'est pas en conflit"), path)
+	}
+	return stages, nil
+}
+
+// Fusion à trois versions avec git merge-file --diff3; le code de sortie est le
+// nombre de conflits, ce n'est pas une erreur
+func (eb *execBackend) MergeFile(ours, base, theirs string) (string, error) {
+	dir, err := os.MkdirTemp("", "gitctrl-merge-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	
+	args := []string{"merge-file", "-p", "--diff3", "-L", "ours", "-L", "base", "-L", "theirs"}
+	for _, part := range []struct{ name, content string }{{"ours", ours}, {"base", base}, {"theirs", theirs}} {
+		file := filepath.Join(dir, part.name)
+		if err := os.WriteFile(file, []byte(part.content), 0644); err != nil {
+			return "", err
+		}
+		args = append(args, file)
+	}
+	cmd := exec.Command("git", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		err = nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// Sans éditeur: les messages proposés par Git sont gardés, sauf ceux des commits
+// reword d'un rebase interactif lancé par l'assistant
+func (eb *execBackend) ContinueOperation(op string) error {
+	return eb.resume(op, "--continue")
+}
+
+func (eb *execBackend) SkipOperation(op string) error {
+	return eb.resume(op, "--skip")
+}
+
+func (eb *execBackend) AbortOperation(op string) error {
+	return eb.resume(op, "--abort")
+}
+
+func (eb *execBackend) resume(op, flag string) error {
+	env := []string{"GIT_EDITOR=true"}
+	state, err := eb.rebaseStateDir()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(state, "editor.sh")); err == nil && op == opRebase {
+		env = rebaseEditorEnv(state, false)
+	}
+	_, err = eb.runEnv(env, op, flag)
+	eb.cleanRebaseState(state)
+	return err
+}
+
+// Fichiers des éditeurs de remplacement: todo, editor.sh et un message par commit reword
+func (eb *execBackend) rebaseStateDir() (string, error) {
+	gitDir, err := eb.output("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(strings.TrimSpace(gitDir), "gitctrl", "rebase"), nil
+}
+
+// Supprimés une fois le rebase terminé ou abandonné
+func (eb *execBackend) cleanRebaseState(state string) {
+	if _, err := os.Stat(filepath.Join(filepath.Dir(filepath.Dir(state)), "rebase-merge")); os.IsNotExist(err) {
+		os.RemoveAll(state)
+	}
+}
+
+// Git lance l'éditeur de messages pour chaque reword, mais aussi pour un squash ou
+// après un conflit. Le commit en cours est la dernière ligne de rebase-merge/done:
+// seul un reword dont le message a été préparé est remplacé.
+const rebaseEditorScript = `file="$1"
+set -- $(grep -v '^#' %s 2>/dev/null | tail -n 1)
+case "$1" in
+r|reword)
+	for msg in %s/msg-"$2"*; do
+		[ -f "$msg" ] && cp "$msg" "$file"
+	done
+	;;
+esac
+exit 0
+`
+
+func rebaseEditorEnv(state string, withTodo bool) []string {
+	env := []string{"GIT_EDITOR=sh " + shellQuote(filepath.Join(state, "editor.sh"))}
+	if withTodo {
+		// Git ajoute le chemin de son todo: cp <le nôtre> <le sien>
+		env = append(env, "GIT_SEQUENCE_EDITOR=cp "+shellQuote(filepath.Join(state, "todo")))
+	}
+	return env
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func (eb *execBackend) RebaseInteractive(onto string, steps []RebaseStep) error {
+	state, err := eb.rebaseStateDir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(state); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(state, 0755); err != nil {
+		return err
+	}
+	
+	var todo strings.Builder
+	for _, step := range steps {
+		fmt.Fprintf(&todo, "%s %s %s\n", step.Action, step.Hash, step.Subject)
+		if step.Action == rebaseReword {
+			if err := os.WriteFile(filepath.Join(state, "msg-"+step.Hash), []byte(step.Message+"\n"), 0644); err != nil {
+				return err
+			}
+		}
+	}
+	done := filepath.Join(filepath.Dir(filepath.Dir(state)), "rebase-merge", "done")
+	script := fmt.Sprintf(rebaseEditorScript, shellQuote(done), shellQuote(state))
+	if err := os.WriteFile(filepath.Join(state, "todo"), []byte(todo.String()), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(state, "editor.sh"), []byte(script), 0644); err != nil {
+		return err
+	}
+	
+	_, err = eb.runEnv(rebaseEditorEnv(state, true), "rebase", "-i", onto)
+	eb.cleanRebaseState(state)
+	return err
+}
+
+// Pour un tag annoté, *objectname est le commit désigné; creatordate est la date du
+// tag, ou du commit pour un tag léger
+func (eb *execBackend) Tags() ([]TagInfo, error) {
+	output, err := eb.output("for-each-ref",
+		"--format=%(refname:strip=2)%1f%(objecttype)%1f%(objectname)%1f%(*objectname)%1f%(creatordate:unix)%1f%(contents)%1e",
+		"refs/tags")
+	if err != nil {
+		return nil, err
+	}
+	var tags []TagInfo
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+		if len(fields) < 6 {
+			continue
+		}
+		timestamp, _ := strconv.ParseInt(fields[4], 10, 64)
+		tag := TagInfo{
+			Name:      fields[0],
+			Commit:    fields[2],
+			Annotated: fields[1] == "tag",
+			Message:   strings.TrimSpace(fields[5]),
+			Date:      time.Unix(timestamp, 0),
+		}
+		if tag.Annotated {
+			tag.Commit = fields[3]
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// Les notes générées ont des lignes qui pourraient commencer par #: le message est gardé tel quel
+func (eb *execBackend) CreateTag(name, target, message string) error {
+	args := []string{"tag"}
+	if message != "" {
+		args = append(args, "-a", "--cleanup=whitespace", "-m", message)
+	}
+	args = append(args, name)
+	if target != "" {
+		args = append(args, target)
+	}
+	_, err := eb.run(args...)
+	return err
+}
+
+func (eb *execBackend) DeleteTag(name string) error {
+	_, err := eb.run("tag", "-d", name)
+	return err
+}
+
+func (eb *execBackend) Upstream() (UpstreamInfo, error) {
+	var info UpstreamInfo
+	name, err := eb.output("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		// Pas de branche suivie: ce n'est pas une erreur
+		return info, nil
+	}
+	info.Name = strings.TrimSpace(name)
+	
+	counts, err := eb.output("rev-list", "--left-right", "--count", "HEAD...@{upstream}")
+	if err != nil {
+		return info, err
+	}
+	fields := strings.Fields(counts)
+	if len(fields) == 2 {
+		info.Ahead, _ = strconv.Atoi(fields[0])
+		info.Behind, _ = strconv.Atoi(fields[1])
+	}
+	return info, nil
+}
+
+// Backend natif : s'appuie sur go-git, sans le binaire git.
+// Les fusions non fast-forward, le rebase, le cherry-pick, le revert, le stash
+// et l'indexation par morceaux restent réservés au backend exec.
+
+var errNativeUnsupported error = localizedError("opération non supportée par le backend natif")
+
+type nativeBackend struct {
+	dir func() string
+}
+
+func (nb *nativeBackend) Name() string {
+	return backendNative
+}
+
+// Le dépôt est cherché depuis le répertoire courant jusqu'à ses parents, comme avec git
+func (nb *nativeBackend) open() (*git.Repository, error) {
+	repo, err := git.PlainOpenWithOptions(nb.dir(), &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+	if err == git.ErrRepositoryNotExists {
+		return nil, fmt.Errorf(tr("%s n'est pas un dépôt Git"), nb.dir())
+	}
+	return repo, err
+}
+
+// Arbre de travail; les exclusions globales (core.excludesFile) s'ajoutent aux .gitignore
+func (nb *nativeBackend) worktree() (*git.Repository, *git.Worktree, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return nil, nil, err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, err
+	}
+	if patterns, err := gitignore.LoadGlobalPatterns(osfs.New("/")); err == nil {
+		worktree.Excludes = append(worktree.Excludes, patterns...)
+	}
+	return repo, worktree, nil
+}
+
+// Chemin relatif à la racine du dépôt, au format de l'index
+func (nb *nativeBackend) repoPath(worktree *git.Worktree, path string) (string, error) {
+	rel, err := filepath.Rel(worktree.Filesystem.Root(), filepath.Join(nb.dir(), path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf(tr("chemin hors du dépôt: %s"), path)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// La branche par défaut suit init.defaultBranch, comme git init
+func (nb *nativeBackend) Init() error {
+	options := &git.PlainInitOptions{InitOptions: git.InitOptions{DefaultBranch: plumbing.Master}}
+	if global, err := gitconfig.LoadConfig(gitconfig.GlobalScope); err == nil && global.Init.DefaultBranch != "" {
+		options.DefaultBranch = plumbing.NewBranchReferenceName(global.Init.DefaultBranch)
+	}
+	_, err := git.PlainInitWithOptions(nb.dir(), options)
+	if err == git.ErrRepositoryAlreadyExists {
+		return nil
+	}
+	return err
+}
+
+func (nb *nativeBackend) CurrentBranch() (string, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return "", err
+	}
+	return nativeBranch(repo)
+}
+
+// Branche pointée par HEAD, même sans commit; vide si HEAD est détachée
+func nativeBranch(repo *git.Repository) (string, error) {
+	head, err := repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return "", err
+	}
+	if head.Type() != plumbing.SymbolicReference {
+		return "", nil
+	}
+	return head.Target().Short(), nil
+}
+
+// Commit pointé par HEAD, nil avant le premier commit
+func nativeHead(repo *git.Repository) (*object.Commit, error) {
+	ref, err := repo.Head()
+	if err == plumbing.ErrReferenceNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return repo.CommitObject(ref.Hash())
+}
+
+// Commit désigné par une révision: branche, tag, hash (même abrégé), HEAD~n, ^n...
+func nativeCommit(repo *git.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf(tr("commit introuvable: %s"), rev)
+	}
+	return repo.CommitObject(*hash)
+}
+
+// Parcourt l'historique depuis from, du plus récent au plus ancien (date de commit)
+func nativeWalk(repo *git.Repository, from plumbing.Hash, visit func(*object.Commit) error) error {
+	commits, err := repo.Log(&git.LogOptions{From: from, Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return err
+	}
+	return commits.ForEach(visit)
+}
+
+// Commits accessibles depuis hash, lui compris
+func nativeAncestors(repo *git.Repository, hash plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	seen := make(map[plumbing.Hash]bool)
+	err := nativeWalk(repo, hash, func(c *object.Commit) error {
+		seen[c.Hash] = true
+		return nil
+	})
+	return seen, err
+}
+
+// Nombre de commits accessibles depuis from mais pas depuis exclude
+func nativeCountExclusive(repo *git.Repository, from, exclude plumbing.Hash) int {
+	excluded, err := nativeAncestors(repo, exclude)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	nativeWalk(repo, from, func(c *object.Commit) error {
+		if !excluded[c.Hash] {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+func nativeIsAncestor(repo *git.Repository, ancestor, descendant plumbing.Hash) bool {
+	if ancestor == descendant {
+		return true
+	}
+	a, err := repo.CommitObject(ancestor)
+	if err != nil {
+		return false
+	}
+	d, err := repo.CommitObject(descendant)
+	if err != nil {
+		return false
+	}
+	ok, err := a.IsAncestor(d)
+	return err == nil && ok
+}
+
+// Suit les tags annotés jusqu'à l'objet désigné
+func nativePeel(repo *git.Repository, hash plumbing.Hash) plumbing.Hash {
+	for {
+		tag, err := repo.TagObject(hash)
+		if err != nil {
+			return hash
+		}
+		hash = tag.Target
+	}
+}
+
+// Décorations façon git log --decorate, indexées par commit
+func nativeDecorations(repo *git.Repository) map[plumbing.Hash][]string {
+	result := make(map[plumbing.Hash][]string)
+	head, _ := repo.Reference(plumbing.HEAD, false)
+	if head != nil && head.Type() == plumbing.HashReference {
+		result[head.Hash()] = append(result[head.Hash()], "HEAD")
+	}
+	
+	refs, err := repo.References()
+	if err != nil {
+		return result
+	}
+	var names []*plumbing.Reference
+	refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference {
+			names = append(names, ref)
+		}
+		return nil
+	})
+	sort.Slice(names, func(i, j int) bool { return names[i].Name() < names[j].Name() })
+	for _, ref := range names {
+		hash := nativePeel(repo, ref.Hash())
+		switch name := ref.Name(); {
+		case head != nil && name == head.Target():
+			result[hash] = append([]string{"HEAD -> " + name.Short()}, result[hash]...)
+		case name.IsBranch(), name.IsRemote():
+			result[hash] = append(result[hash], name.Short())
+		case name.IsTag():
+			result[hash] = append(result[hash], "tag: "+name.Short())
+		}
+	}
+	return result
+}
+
+func nativeCommitInfo(commit *object.Commit, decorations map[plumbing.Hash][]string) CommitInfo {
+	subject, body, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+	var parents []string
+	for _, parent := range commit.ParentHashes {
+		parents = append(parents, parent.String())
+	}
+	return CommitInfo{
+		Hash:      commit.Hash.String(),
+		ShortHash: commit.Hash.String()[:7],
+		Author:    commit.Author.Name,
+		Email:     commit.Author.Email,
+		Date:      commit.Author.When,
+		Subject:   subject,
+		Parents:   parents,
+		Refs:      decorations[commit.Hash],
+		Body:      strings.TrimSpace(body),
+	}
+}
+
+// Identité GIT_AUTHOR_* ou GIT_COMMITTER_*, sinon user.name et user.email
+func nativeSignature(repo *git.Repository, role string) (*object.Signature, error) {
+	cfg, err := repo.ConfigScoped(gitconfig.SystemScope)
+	if err != nil {
+		return nil, err
+	}
+	name := os.Getenv("GIT_" + role + "_NAME")
+	if name == "" {
+		name = cfg.User.Name
+	}
+	email := os.Getenv("GIT_" + role + "_EMAIL")
+	if email == "" {
+		email = cfg.User.Email
+	}
+	if name == "" || email == "" {
+		return nil, errors.New(tr("identité inconnue: configurez user.name et user.email"))
+	}
+	return &object.Signature{Name: name, Email: email, When: time.Now()}, nil
+}
+
+func (nb *nativeBackend) CommitCount() (int, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return 0, err
+	}
+	head, err := nativeHead(repo)
+	if head == nil {
+		return 0, err
+	}
+	count := 0
+	err = nativeWalk(repo, head.Hash, func(*object.Commit) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
+func (nb *nativeBackend) Status() (RepoStatus, error) {
+	var status RepoStatus
+	repo, worktree, err := nb.worktree()
+	if err != nil {
+		return status, err
+	}
+	if status.Branch, err = nativeBranch(repo); err != nil {
+		return status, err
+	}
+	if head, err := repo.Head(); err == nil {
+		status.Head = head.Hash().String()
+	}
+	status.Upstream = nativeUpstream(repo)
+	status.Entries, err = nativeStatus(repo, worktree)
+	return status, err
+}
+
+// Statut de go-git, complété par les conflits (étapes de l'index) et les renommages exacts
+func nativeStatus(repo *git.Repository, worktree *git.Worktree) ([]StatusEntry, error) {
+	files, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	
+	// Les fichiers non suivis viennent après, comme dans git status
+	changes := make(map[string]*StatusEntry)
+	var untracked []StatusEntry
+	for path, file := range files {
+		change := &StatusEntry{Index: byte(file.Staging), Worktree: byte(file.Worktree), Path: path}
+		if file.Worktree == git.Untracked {
+			untracked = append(untracked, StatusEntry{Index: '?', Worktree: '?', Path: path})
+			change.Worktree = ' '
+		}
+		if change.Index != ' ' && change.Index != '?' || change.Worktree != ' ' {
+			changes[path] = change
+		}
+	}
+	
+	// Conflits: les étapes présentes (1 base, 2 nous, 3 eux) donnent le code;
+	// une entrée fusionnée est à l'étape 0
+	staged := make(map[string]plumbing.Hash)
+	stages := make(map[string][4]bool)
+	for _, entry := range idx.Entries {
+		if entry.Stage == 0 {
+			staged[entry.Name] = entry.Hash
+			continue
+		}
+		present := stages[entry.Name]
+		present[entry.Stage] = true
+		stages[entry.Name] = present
+	}
+	for path, present := range stages {
+		conflict := &StatusEntry{Path: path, Conflict: true}
+		conflict.Index, conflict.Worktree = conflictCode(present[1], present[2], present[3])
+		changes[path] = conflict
+	}
+	
+	// Renommages exacts: contenu supprimé d'un chemin et ajouté sous un autre
+	added := make(map[plumbing.Hash][]string)
+	var removed []string
+	for path, change := range changes {
+		switch change.Index {
+		case 'A':
+			added[staged[path]] = append(added[staged[path]], path)
+		case 'D':
+			removed = append(removed, path)
+		}
+	}
+	if head, err := nativeHead(repo); err == nil && head != nil && len(removed) > 0 {
+		tree, err := head.Tree()
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(removed)
+		for _, path := range removed {
+			file, err := tree.File(path)
+			if err != nil || len(added[file.Hash]) == 0 {
+				continue
+			}
+			candidates := added[file.Hash]
+			sort.Strings(candidates)
+			target := changes[candidates[0]]
+			target.Index, target.OrigPath, target.Score = 'R', path, 100
+			added[file.Hash] = candidates[1:]
+			if changes[path].Worktree == ' ' {
+				delete(changes, path)
+			} else {
+				changes[path].Index = ' '
+			}
+		}
+	}
+	
+	entries := make([]StatusEntry, 0, len(changes)+len(untracked))
+	for _, change := range changes {
+		entries = append(entries, *change)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	sort.Slice(untracked, func(i, j int) bool { return untracked[i].Path < untracked[j].Path })
+	return append(entries, untracked...), nil
+}
+
+// Codes XY de git status pour un chemin en conflit
+func conflictCode(base, ours, theirs bool) (byte, byte) {
+	switch {
+	case base && ours && theirs:
+		return 'U', 'U'
+	case !base && ours && theirs:
+		return 'A', 'A'
+	case base && ours:
+		return 'U', 'D'
+	case base && theirs:
+		return 'D', 'U'
+	case ours:
+		return 'A', 'U'
+	case theirs:
+		return 'U', 'A'
+	default:
+		return 'D', 'D'
+	}
+}
+
+// Refuse de remplacer l'arbre de travail s'il contient des changements suivis
+func nativeCheckClean(repo *git.Repository, worktree *git.Worktree) error {
+	entries, err := nativeStatus(repo, worktree)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Index != '?' {
+			return fmt.Errorf("%w (%s)", errLocalChanges, entry.Path)
+		}
+	}
+	return nil
+}
+
+func (nb *nativeBackend) Log(limit int) ([]CommitInfo, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return nil, err
+	}
+	head, err := nativeHead(repo)
+	if head == nil {
+		return nil, err
+	}
+	return nativeLog(repo, head, limit)
+}
+
+func (nb *nativeBackend) LogFrom(rev string, limit int) ([]CommitInfo, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return nil, err
+	}
+	start, err := nativeCommit(repo, rev)
+	if err != nil {
+		return nil, err
+	}
+	return nativeLog(repo, start, limit)
+}
+
+func nativeLog(repo *git.Repository, start *object.Commit, limit int) ([]CommitInfo, error) {
+	decorations := nativeDecorations(repo)
+	var commits []CommitInfo
+	err := nativeWalk(repo, start.Hash, func(c *object.Commit) error {
+		commits = append(commits, nativeCommitInfo(c, decorations))
+		if limit > 0 && len(commits) >= limit {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	return commits, err
+}
+
+func (nb *nativeBackend) ReadCommit(rev string) (CommitInfo, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	commit, err := nativeCommit(repo, rev)
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	return nativeCommitInfo(commit, nativeDecorations(repo)), nil
+}
+
+func (nb *nativeBackend) LogRange(from, to string) ([]CommitInfo, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return nil, err
+	}
+	target, err := nativeCommit(repo, to)
+	if err != nil {
+		return nil, err
+	}
+	excluded := make(map[plumbing.Hash]bool)
+	if from != "" {
+		start, err := nativeCommit(repo, from)
+		if err != nil {
+			return nil, err
+		}
+		if excluded, err = nativeAncestors(repo, start.Hash); err != nil {
+			return nil, err
+		}
+	}
+	
+	decorations := nativeDecorations(repo)
+	var commits []CommitInfo
+	err = nativeWalk(repo, target.Hash, func(c *object.Commit) error {
+		if !excluded[c.Hash] {
+			commits = append(commits, nativeCommitInfo(c, decorations))
+		}
+		return nil
+	})
+	return commits, err
+}
+
+// L'identité de l'auteur passe par le .mailmap de l'arbre de travail, comme git log %aN/%aE
+func (nb *nativeBackend) LogStats(since time.Time) ([]CommitStats, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return nil, err
+	}
+	head, err := nativeHead(repo)
+	if head == nil {
+		return nil, err
+	}
+	
+	options := &git.LogOptions{From: head.Hash, Order: git.LogOrderCommitterTime}
+	if !since.IsZero() {
+		options.Since = &since
+	}
+	commits, err := repo.Log(options)
+	if err != nil {
+		return nil, err
+	}
+	mailmap := nativeMailmap(repo, head)
+	var result []CommitStats
+	err = commits.ForEach(func(c *object.Commit) error {
+		if c.NumParents() > 1 {
+			return nil
+		}
+		stats, err := c.Stats()
+		if err != nil {
+			return err
+		}
+		name, email := mailmap.resolve(c.Author.Name, c.Author.Email)
+		commit := CommitStats{Hash: c.Hash.String(), Author: name, Email: email, Date: c.Author.When}
+		for _, file := range stats {
+			commit.Files = append(commit.Files, FileStat{Path: file.Name, Added: file.Addition, Deleted: file.Deletion})
+		}
+		result = append(result, commit)
+		return nil
+	})
+	return result, err
+}
+
+// .mailmap de l'arbre de travail, ou celui de HEAD dans un dépôt nu
+func nativeMailmap(repo *git.Repository, head *object.Commit) mailmap {
+	if worktree, err := repo.Worktree(); err == nil {
+		data, _ := os.ReadFile(filepath.Join(worktree.Filesystem.Root(), ".mailmap"))
+		return parseMailmap(string(data))
+	}
+	if file, err := head.File(".mailmap"); err == nil {
+		content, _ := file.Contents()
+		return parseMailmap(content)
+	}
+	return nil
+}
+
+// Identités de .mailmap, par adresse du commit (en minuscules) puis nom du commit
+// (en minuscules, vide pour toute entrée de cette adresse)
+type mailmap map[string]map[string]mailmapIdentity
+
+// Nom et adresse à afficher; vides, ceux du commit sont gardés
+type mailmapIdentity struct {
+	Name  string
+	Email string
+}
+
+var mailmapEmailPattern = regexp.MustCompile(`([^<>]*)<([^<>]*)>`)
+
+// Formes reconnues, comme gitmailmap(5):
+//
+//	Nom <adresse du commit>
+//	<adresse> <adresse du commit>
+//	Nom <adresse> <adresse du commit>
+//	Nom <adresse> Nom du commit <adresse du commit>
+func parseMailmap(content string) mailmap {
+	m := make(mailmap)
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		matches := mailmapEmailPattern.FindAllStringSubmatch(line, 2)
+		var identity mailmapIdentity
+		var commitName, commitEmail string
+		switch len(matches) {
+		case 1:
+			identity.Name, commitEmail = strings.TrimSpace(matches[0][1]), matches[0][2]
+		case 2:
+			identity = mailmapIdentity{Name: strings.TrimSpace(matches[0][1]), Email: matches[0][2]}
+			commitName, commitEmail = strings.TrimSpace(matches[1][1]), matches[1][2]
+		default:
+			continue
+		}
+		key := strings.ToLower(commitEmail)
+		if m[key] == nil {
+			m[key] = make(map[string]mailmapIdentity)
+		}
+		m[key][strings.ToLower(commitName)] = identity
+	}
+	return m
+}
+
+// Une entrée avec le nom du commit l'emporte sur celle de la seule adresse
+func (m mailmap) resolve(name, email string) (string, string) {
+	entries := m[strings.ToLower(email)]
+	identity, ok := entries[strings.ToLower(name)]
+	if !ok {
+		identity = entries[""]
+	}
+	if identity.Name != "" {
+		name = identity.Name
+	}
+	if identity.Email != "" {
+		email = identity.Email
+	}
+	return name, email
+}
+
+// Même présentation que git show --stat avec le format de l'exec
+func (nb *nativeBackend) ShowCommit(rev string) (string, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return "", err
+	}
+	commit, err := nativeCommit(repo, rev)
+	if err != nil {
+		return "", err
+	}
+	stats, err := commit.Stats()
+	if err != nil {
+		return "", err
+	}
+	
+	var out strings.Builder
+	subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+	fmt.Fprintf(&out, "%s - %s\n%s <%s>\n%s\n", commit.Hash.String()[:7], subject, commit.Author.Name, commit.Author.Email, commit.Author.When.Format(gitDateFormat))
+	if len(stats) == 0 {
+		return out.String(), nil
+	}
+	
+	plural := func(n int) string {
+		if n == 1 {
+			return ""
+		}
+		return "s"
+	}
+	insertions, deletions := 0, 0
+	for _, file := range stats {
+		insertions += file.Addition
+		deletions += file.Deletion
+	}
+	out.WriteString("\n" + stats.String())
+	fmt.Fprintf(&out, " %d file%s changed", len(stats), plural(len(stats)))
+	// Comme git: les deux compteurs quand aucun n'est positif
+	if insertions > 0 || deletions == 0 {
+		fmt.Fprintf(&out, ", %d insertion%s(+)", insertions, plural(insertions))
+	}
+	if deletions > 0 || insertions == 0 {
+		fmt.Fprintf(&out, ", %d deletion%s(-)", deletions, plural(deletions))
+	}
+	out.WriteString("\n")
+	return out.String(), nil
+}
+
+// Format de date par défaut de git (%ad)
+const gitDateFormat = "Mon Jan 2 15:04:05 2006 -0700"
+
+// Un premier commit est comparé à l'arbre vide
+func (nb *nativeBackend) CommitDiff(rev string) (string, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return "", err
+	}
+	commit, err := nativeCommit(repo, rev)
+	if err != nil {
+		return "", err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", err
+	}
+	parentTree := &object.Tree{}
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return "", err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return "", err
+		}
+	}
+	patch, err := parentTree.Patch(tree)
+	if err != nil {
+		return "", err
+	}
+	return patch.String(), nil
+}
+
+func (nb *nativeBackend) SearchCommits(query string, byPath bool) ([]CommitInfo, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return nil, err
+	}
+	head, err := nativeHead(repo)
+	if head == nil {
+		return nil, err
+	}
+	
+	options := &git.LogOptions{From: head.Hash, Order: git.LogOrderCommitterTime}
+	if byPath {
+		options.PathFilter = func(path string) bool { return strings.Contains(path, query) }
+	}
+	commits, err := repo.Log(options)
+	if err != nil {
+		return nil, err
+	}
+	decorations := nativeDecorations(repo)
+	lower := strings.ToLower(query)
+	var result []CommitInfo
+	err = commits.ForEach(func(c *object.Commit) error {
+		if byPath || strings.Contains(strings.ToLower(c.Message), lower) {
+			result = append(result, nativeCommitInfo(c, decorations))
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (nb *nativeBackend) Branches() ([]BranchInfo, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return nil, err
+	}
+	current, _ := nativeBranch(repo)
+	refs, err := repo.Branches()
+	if err != nil {
+		return nil, err
+	}
+	
+	var branches []BranchInfo
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		branch := BranchInfo{
+			Name:    ref.Name().Short(),
+			Current: ref.Name().Short() == current,
+			Hash:    ref.Hash().String()[:7],
+		}
+		if commit, err := repo.CommitObject(ref.Hash()); err == nil {
+			branch.Subject, _, _ = strings.Cut(strings.TrimSpace(commit.Message), "\n")
+			branch.Author = commit.Author.Name
+			branch.Date = commit.Author.When
+		}
+		branches = append(branches, branch)
+		return nil
+	})
+	sort.Slice(branches, func(i, j int) bool { return branches[i].Name < branches[j].Name })
+	return branches, err
+}
+
+func (nb *nativeBackend) Add(paths ...string) error {
+	_, worktree, err := nb.worktree()
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	for _, path := range paths {
+		rel, err := nb.repoPath(worktree, path)
+		if err != nil {
+			return err
+		}
+		err = worktree.AddWithOptions(&git.AddOptions{Path: rel})
+		if err == index.ErrEntryNotFound {
+			return fmt.Errorf(tr("le chemin '%s' ne correspond à aucun fichier"), path)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remet les chemins indexés dans leur état de HEAD (retirés de l'index avant le premier commit)
+func (nb *nativeBackend) Unstage(paths ...string) error {
+	repo, worktree, err := nb.worktree()
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	entries, err := nativeStatus(repo, worktree)
+	if err != nil {
+		return err
+	}
+	
+	var files []string
+	for _, path := range paths {
+		rel, err := nb.repoPath(worktree, path)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.Index == ' ' || entry.Index == '?' {
+				continue
+			}
+			for _, name := range []string{entry.Path, entry.OrigPath} {
+				if name != "" && (rel == "." || name == rel || strings.HasPrefix(name, rel+"/")) {
+					files = append(files, name)
+				}
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	
+	if head, err := nativeHead(repo); err != nil {
+		return err
+	} else if head != nil {
+		return worktree.Restore(&git.RestoreOptions{Staged: true, Files: files})
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		idx.Remove(file)
+	}
+	return repo.Storer.SetIndex(idx)
+}
+
+func (nb *nativeBackend) Diff(path string, cached bool) (string, error) {
+	return "", fmt.Errorf("%w: diff", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) ApplyToIndex(patch string, reverse bool) error {
+	return fmt.Errorf("%w: apply", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) Commit(message string) error {
+	repo, worktree, err := nb.worktree()
+	if err != nil {
+		return err
+	}
+	if head, err := nativeHead(repo); err != nil {
+		return err
+	} else if head == nil {
+		if idx, err := repo.Storer.Index(); err != nil || len(idx.Entries) == 0 {
+			return errors.New(tr("rien à commiter"))
+		}
+	}
+	
+	author, err := nativeSignature(repo, "AUTHOR")
+	if err != nil {
+		return err
+	}
+	committer, err := nativeSignature(repo, "COMMITTER")
+	if err != nil {
+		return err
+	}
+	_, err = worktree.Commit(strings.TrimRight(message, "\n")+"\n", &git.CommitOptions{Author: author, Committer: committer})
+	if err == git.ErrEmptyCommit {
+		return errors.New(tr("rien à commiter"))
+	}
+	return err
+}
+
+// Une branche locale est activée; toute autre révision détache HEAD
+func (nb *nativeBackend) Checkout(ref string) error {
+	repo, worktree, err := nb.worktree()
+	if err != nil {
+		return err
+	}
+	target, err := nativeCommit(repo, ref)
+	if err != nil {
+		return err
+	}
+	if err := nativeCheckClean(repo, worktree); err != nil {
+		return err
+	}
+	
+	options := &git.CheckoutOptions{Hash: target.Hash}
+	branch := plumbing.NewBranchReferenceName(ref)
+	if _, err := repo.Reference(branch, false); err == nil {
+		options = &git.CheckoutOptions{Branch: branch}
+	}
+	return worktree.Checkout(options)
+}
+
+// Les changements locaux sont gardés quand la branche part de HEAD, comme git checkout -b
+func (nb *nativeBackend) CreateBranch(name, startPoint string) error {
+	repo, worktree, err := nb.worktree()
+	if err != nil {
+		return err
+	}
+	branch := plumbing.NewBranchReferenceName(name)
+	if _, err := repo.Reference(branch, false); err == nil {
+		return fmt.Errorf(tr("la branche '%s' existe déjà"), name)
+	}
+	
+	head, err := nativeHead(repo)
+	if err != nil {
+		return err
+	}
+	// Branche orpheline sur un dépôt sans commit
+	if head == nil && startPoint == "" {
+		return repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branch))
+	}
+	
+	options := &git.CheckoutOptions{Branch: branch, Create: true, Keep: true}
+	if startPoint != "" {
+		target, err := nativeCommit(repo, startPoint)
+		if err != nil {
+			return err
+		}
+		if head == nil || target.Hash != head.Hash {
+			if err := nativeCheckClean(repo, worktree); err != nil {
+				return err
+			}
+			options.Keep = false
+		}
+		options.Hash = target.Hash
+	}
+	return worktree.Checkout(options)
+}
+
+func (nb *nativeBackend) DeleteBranch(name string, force bool) error {
+	repo, err := nb.open()
+	if err != nil {
+		return err
+	}
+	branch := plumbing.NewBranchReferenceName(name)
+	tip, err := repo.Reference(branch, false)
+	if err != nil {
+		return fmt.Errorf(tr("branche introuvable: %s"), name)
+	}
+	if current, _ := nativeBranch(repo); current == name {
+		return errors.New(tr("impossible de supprimer la branche courante"))
+	}
+	if !force {
+		head, err := repo.Head()
+		if err != nil || !nativeIsAncestor(repo, tip.Hash(), head.Hash()) {
+			return fmt.Errorf(tr("la branche '%s' n'est pas entièrement fusionnée"), name)
+		}
+	}
+	if err := repo.Storer.RemoveReference(branch); err != nil {
+		return err
+	}
+	// Comme git branch -d, la configuration de suivi part avec la branche
+	if err := repo.DeleteBranch(name); err != nil && err != git.ErrBranchNotFound {
+		return err
+	}
+	return nil
+}
+
+// Seul le fast-forward est pris en charge; ff-only le rend explicite
+func (nb *nativeBackend) Merge(branch, strategy string) error {
+	if err := nb.CanMerge(branch, strategy); err != nil {
+		return err
+	}
+	repo, worktree, err := nb.worktree()
+	if err != nil {
+		return err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	target, err := nativeCommit(repo, branch)
+	if err != nil {
+		return err
+	}
+	
+	switch {
+	case nativeIsAncestor(repo, target.Hash, head.Hash()):
+		return nil // Déjà à jour
+	case nativeIsAncestor(repo, head.Hash(), target.Hash):
+		if err := nativeCheckClean(repo, worktree); err != nil {
+			return err
+		}
+		return worktree.Reset(&git.ResetOptions{Commit: target.Hash, Mode: git.MergeReset})
+	default:
+		return fmt.Errorf(tr("fast-forward impossible: '%s' a divergé"), branch)
+	}
+}
+
+// Seules les fusions fast-forward (ou déjà à jour) sont possibles; ff-only sur des
+// branches divergentes est laissé à Merge, qui le refuse comme git
+func (nb *nativeBackend) CanMerge(branch, strategy string) error {
+	if strategy == mergeNoFF || strategy == mergeSquash || strategy == mergeRebase {
+		return fmt.Errorf("%w: merge --%s", errNativeUnsupported, strategy)
+	}
+	repo, err := nb.open()
+	if err != nil {
+		return err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	target, err := nativeCommit(repo, branch)
+	if err != nil {
+		return err
+	}
+	if strategy == mergeFFOnly || nativeIsAncestor(repo, target.Hash, head.Hash()) || nativeIsAncestor(repo, head.Hash(), target.Hash) {
+		return nil
+	}
+	return fmt.Errorf(tr("%w: fusion non fast-forward de '%s'"), errNativeUnsupported, branch)
+}
+
+func (nb *nativeBackend) PreviewMerge(branch string) (MergePreview, error) {
+	return MergePreview{}, fmt.Errorf("%w: merge-tree", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) Rebase(upstream, branch string) error {
+	return fmt.Errorf("%w: rebase", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) CherryPick(hashes []string, recordOrigin bool) error {
+	return fmt.Errorf("%w: cherry-pick", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) Revert(hash string, mainline int) error {
+	return fmt.Errorf("%w: revert", errNativeUnsupported)
+}
+
+var nativeResetModes = map[string]git.ResetMode{
+	"soft":  git.SoftReset,
+	"mixed": git.MixedReset,
+	"hard":  git.HardReset,
+}
+
+func (nb *nativeBackend) Reset(mode, target string) error {
+	resetMode, ok := nativeResetModes[mode]
+	if !ok {
+		return fmt.Errorf(tr("mode de reset inconnu: %s"), mode)
+	}
+	repo, worktree, err := nb.worktree()
+	if err != nil {
+		return err
+	}
+	commit, err := nativeCommit(repo, target)
+	if err != nil {
+		return err
+	}
+	options := &git.ResetOptions{Commit: commit.Hash, Mode: resetMode}
+	if resetMode == git.HardReset {
+		// Sans liste de chemins, go-git efface aussi les fichiers non suivis et ignorés:
+		// seuls ceux de l'index et du commit visé sont remis en état, comme avec git
+		if options.Files, err = nativeTrackedPaths(repo, commit); err != nil {
+			return err
+		}
+	}
+	return worktree.Reset(options)
+}
+
+// Chemins de l'index et de l'arbre d'un commit
+func nativeTrackedPaths(repo *git.Repository, commit *object.Commit) ([]string, error) {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var paths []string
+	for _, entry := range idx.Entries {
+		if !seen[entry.Name] {
+			seen[entry.Name] = true
+			paths = append(paths, entry.Name)
+		}
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	err = tree.Files().ForEach(func(file *object.File) error {
+		if !seen[file.Name] {
+			seen[file.Name] = true
+			paths = append(paths, file.Name)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+func (nb *nativeBackend) WalkHeadBlobs(visit func(path string, data []byte) error) error {
+	repo, err := nb.open()
+	if err != nil {
+		return err
+	}
+	head, err := nativeHead(repo)
+	if head == nil {
+		return err
+	}
+	tree, err := head.Tree()
+	if err != nil {
+		return err
+	}
+	return tree.Files().ForEach(func(file *object.File) error {
+		if file.Mode == filemode.Symlink || file.Mode == filemode.Submodule {
+			return nil
+		}
+		contents, err := file.Contents()
+		if err != nil {
+			return err
+		}
+		return visit(file.Name, []byte(contents))
+	})
+}
+
+func (nb *nativeBackend) LsFiles() ([]string, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for i, entry := range idx.Entries {
+		// Une seule ligne par chemin, même en cas de conflit
+		if i > 0 && idx.Entries[i-1].Name == entry.Name {
+			continue
+		}
+		files = append(files, entry.Name)
+	}
+	return files, nil
+}
+
+// Objets libres de objects/xx/ et packs avec leur index
+func (nb *nativeBackend) CountObjects() (ObjectStats, error) {
+	var stats ObjectStats
+	repo, err := nb.open()
+	if err != nil {
+		return stats, err
+	}
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return stats, fmt.Errorf("%w: count-objects", errNativeUnsupported)
+	}
+	fs := storage.Filesystem()
+	
+	dirs, err := fs.ReadDir("objects")
+	if err != nil {
+		return stats, err
+	}
+	for _, dir := range dirs {
+		if len(dir.Name()) != 2 || !dir.IsDir() {
+			continue
+		}
+		objects, _ := fs.ReadDir(fs.Join("objects", dir.Name()))
+		for _, object := range objects {
+			stats.Count++
+			stats.LooseSize += object.Size()
+		}
+	}
+	
+	packs, err := storage.ObjectPacks()
+	if err != nil {
+		return stats, err
+	}
+	for _, pack := range packs {
+		base := fs.Join("objects", "pack", "pack-"+pack.String())
+		for _, ext := range []string{".pack", ".idx"} {
+			if info, err := fs.Stat(base + ext); err == nil {
+				stats.PackSize += info.Size()
+			}
+		}
+		file, err := fs.Open(base + ".idx")
+		if err != nil {
+			return stats, err
+		}
+		packIndex := idxfile.NewMemoryIndex()
+		err = idxfile.NewDecoder(file).Decode(packIndex)
+		file.Close()
+		if err != nil {
+			return stats, err
+		}
+		count, _ := packIndex.Count()
+		stats.Packs++
+		stats.InPack += int(count)
+	}
+	return stats, nil
+}
+
+func (nb *nativeBackend) Remotes() ([]RemoteInfo, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return nil, err
+	}
+	list, err := repo.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	var remotes []RemoteInfo
+	for _, remote := range list {
+		if config := remote.Config(); len(config.URLs) > 0 {
+			remotes = append(remotes, RemoteInfo{Name: config.Name, URL: config.URLs[0]})
+		}
+	}
+	sort.Slice(remotes, func(i, j int) bool { return remotes[i].Name < remotes[j].Name })
+	return remotes, nil
+}
+
+func (nb *nativeBackend) AddRemote(name, url string) error {
+	repo, err := nb.open()
+	if err != nil {
+		return err
+	}
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{Name: name, URLs: []string{url}})
+	if err == git.ErrRemoteExists {
+		return fmt.Errorf(tr("le dépôt distant '%s' existe déjà"), name)
+	}
+	return err
+}
+
+// Dépôt distant et branche suivis par la branche courante; origin et la branche courante à défaut
+func nativeTracking(repo *git.Repository, remote, branch string) (string, string, error) {
+	current, err := nativeBranch(repo)
+	if err != nil {
+		return "", "", err
+	}
+	if current == "" && branch == "" {
+		return "", "", errors.New(tr("HEAD détachée: précisez la branche"))
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", "", err
+	}
+	if tracked := cfg.Branches[current]; tracked != nil && tracked.Remote != "" {
+		if remote == "" {
+			remote = tracked.Remote
+		}
+		if branch == "" && remote == tracked.Remote && tracked.Merge != "" {
+			branch = tracked.Merge.Short()
+		}
+	}
+	if remote == "" {
+		remote = git.DefaultRemoteName
+	}
+	if branch == "" {
+		branch = current
+	}
+	return remote, branch, nil
+}
+
+func (nb *nativeBackend) Fetch(remote string) error {
+	repo, err := nb.open()
+	if err != nil {
+		return err
+	}
+	names := []string{remote}
+	if remote == "" {
+		remotes, err := nb.Remotes()
+		if err != nil {
+			return err
+		}
+		names = names[:0]
+		for _, remote := range remotes {
+			names = append(names, remote.Name)
+		}
+	}
+	for _, name := range names {
+		err := repo.Fetch(&git.FetchOptions{RemoteName: name, Prune: true})
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fast-forward seulement: go-git ne sait pas fusionner des historiques divergents
+func (nb *nativeBackend) Pull(remote, branch string, rebase bool) error {
+	if rebase {
+		return fmt.Errorf("%w: pull --rebase", errNativeUnsupported)
+	}
+	repo, worktree, err := nb.worktree()
+	if err != nil {
+		return err
+	}
+	remote, branch, err = nativeTracking(repo, remote, branch)
+	if err != nil {
+		return err
+	}
+	if err := nativeCheckClean(repo, worktree); err != nil {
+		return err
+	}
+	err = worktree.Pull(&git.PullOptions{RemoteName: remote, ReferenceName: plumbing.NewBranchReferenceName(branch)})
+	switch err {
+	case git.NoErrAlreadyUpToDate:
+		return nil
+	case git.ErrNonFastForwardUpdate:
+		return fmt.Errorf(tr("%w: fusion non fast-forward de '%s'"), errNativeUnsupported, remote+"/"+branch)
+	}
+	return err
+}
+
+func (nb *nativeBackend) Push(remote, branch string, setUpstream bool) error {
+	repo, err := nb.open()
+	if err != nil {
+		return err
+	}
+	if branch == "" {
+		if branch, err = nativeBranch(repo); err != nil {
+			return err
+		}
+	}
+	remote, branch, err = nativeTracking(repo, remote, branch)
+	if err != nil {
+		return err
+	}
+	
+	spec := gitconfig.RefSpec(fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch))
+	err = repo.Push(&git.PushOptions{RemoteName: remote, RefSpecs: []gitconfig.RefSpec{spec}})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return err
+	}
+	if !setUpstream {
+		return nil
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	cfg.Branches[branch] = &gitconfig.Branch{Name: branch, Remote: remote, Merge: plumbing.NewBranchReferenceName(branch)}
+	return repo.SetConfig(cfg)
+}
+
+func (nb *nativeBackend) Upstream() (UpstreamInfo, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return UpstreamInfo{}, err
+	}
+	return nativeUpstream(repo), nil
+}
+
+// Branche suivie (branch.<nom>.remote/merge) et avance/retard de HEAD
+func nativeUpstream(repo *git.Repository) UpstreamInfo {
+	var info UpstreamInfo
+	current, err := nativeBranch(repo)
+	if err != nil || current == "" {
+		return info
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return info
+	}
+	tracked := cfg.Branches[current]
+	if tracked == nil || tracked.Remote == "" || tracked.Merge == "" {
+		return info
+	}
+	
+	info.Name = tracked.Remote + "/" + tracked.Merge.Short()
+	name := plumbing.NewRemoteReferenceName(tracked.Remote, tracked.Merge.Short())
+	if tracked.Remote == "." {
+		info.Name, name = tracked.Merge.Short(), tracked.Merge
+	}
+	upstream, err := repo.Reference(name, true)
+	if err != nil {
+		return info
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return info
+	}
+	info.Ahead = nativeCountExclusive(repo, head.Hash(), upstream.Hash())
+	info.Behind = nativeCountExclusive(repo, upstream.Hash(), head.Hash())
+	return info
+}
+
+func (nb *nativeBackend) AbortRebase() error {
+	return fmt.Errorf("%w: rebase", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) Refs(prefix string) (map[string]string, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && strings.HasPrefix(ref.Name().String(), prefix) {
+			result[ref.Name().String()] = ref.Hash().String()
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (nb *nativeBackend) UpdateRef(name, hash string) error {
+	repo, err := nb.open()
+	if err != nil {
+		return err
+	}
+	return repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(name), plumbing.NewHash(hash)))
+}
+
+func (nb *nativeBackend) DeleteRef(name string) error {
+	repo, err := nb.open()
+	if err != nil {
+		return err
+	}
+	return repo.Storer.RemoveReference(plumbing.ReferenceName(name))
+}
+
+// Les commits de stash ne sont pas construits: seul un arbre propre est accepté
+func (nb *nativeBackend) SnapshotWorktree() (string, error) {
+	status, err := nb.Status()
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range status.Entries {
+		if entry.Index != '?' {
+			return "", fmt.Errorf("%w: stash", errNativeUnsupported)
+		}
+	}
+	return "", nil
+}
+
+func (nb *nativeBackend) RestoreSnapshot(hash string) error {
+	return fmt.Errorf("%w: stash apply", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) Stashes() ([]StashInfo, error) {
+	return nil, fmt.Errorf("%w: stash list", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) StashSave(message string, includeUntracked bool) error {
+	return fmt.Errorf("%w: stash push", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) StashShow(ref string) (string, error) {
+	return "", fmt.Errorf("%w: stash show", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) StashApply(ref string, pop bool) error {
+	return fmt.Errorf("%w: stash apply", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) StashDrop(ref string) error {
+	return fmt.Errorf("%w: stash drop", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) StashBranch(name, ref string) error {
+	return fmt.Errorf("%w: stash branch", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) ConflictStages(path string) (ConflictStages, error) {
+	return ConflictStages{}, fmt.Errorf("%w: ls-files -u", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) MergeFile(ours, base, theirs string) (string, error) {
+	return "", fmt.Errorf("%w: merge-file", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) ContinueOperation(op string) error {
+	return fmt.Errorf("%w: %s --continue", errNativeUnsupported, op)
+}
+
+func (nb *nativeBackend) SkipOperation(op string) error {
+	return fmt.Errorf("%w: %s --skip", errNativeUnsupported, op)
+}
+
+func (nb *nativeBackend) AbortOperation(op string) error {
+	return fmt.Errorf("%w: %s --abort", errNativeUnsupported, op)
+}
+
+func (nb *nativeBackend) RebaseInteractive(onto string, steps []RebaseStep) error {
+	return fmt.Errorf("%w: rebase -i", errNativeUnsupported)
+}
+
+func (nb *nativeBackend) Tags() ([]TagInfo, error) {
+	repo, err := nb.open()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	
+	var tags []TagInfo
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		tag := TagInfo{Name: ref.Name().Short(), Commit: nativePeel(repo, ref.Hash()).String()}
+		if annotated, err := repo.TagObject(ref.Hash()); err == nil {
+			tag.Annotated, tag.Message, tag.Date = true, strings.TrimSpace(annotated.Message), annotated.Tagger.When
+		} else if commit, err := repo.CommitObject(ref.Hash()); err == nil {
+			tag.Message, tag.Date = strings.TrimSpace(commit.Message), commit.Committer.When
+		}
+		tags = append(tags, tag)
+		return nil
+	})
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, err
+}
+
+// Un tag annoté est signé par le committer, comme avec git tag -a
+func (nb *nativeBackend) CreateTag(name, target, message string) error {
+	repo, err := nb.open()
+	if err != nil {
+		return err
+	}
+	if target == "" {
+		target = "HEAD"
+	}
+	commit, err := nativeCommit(repo, target)
+	if err != nil {
+		return err
+	}
+	var options *git.CreateTagOptions
+	if message != "" {
+		tagger, err := nativeSignature(repo, "COMMITTER")
+		if err != nil {
+			return err
+		}
+		options = &git.CreateTagOptions{Tagger: tagger, Message: message}
+	}
+	_, err = repo.CreateTag(name, commit.Hash, options)
+	if err == git.ErrTagExists {
+		return fmt.Errorf(tr("le tag '%s' existe déjà"), name)
+	}
+	return err
+}
+
+func (nb *nativeBackend) DeleteTag(name string) error {
+	repo, err := nb.open()
+	if err != nil {
+		return err
+	}
+	err = repo.DeleteTag(name)
+	if err == git.ErrTagNotFound {
+		return fmt.Errorf(tr("tag introuvable: %s"), name)
+	}
+	return err
+}
+
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".lock"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Rapports structurés, partagés par l'affichage texte et la sortie JSON.
+// Le champ "schema" identifie le format; il change si un champ est retiré ou renommé.
+
+const (
+	formatText     = "text"
+	formatJSON     = "json"
+	formatMarkdown = "markdown"
+)
+
+type StatusReport struct {
+	Schema     string        `json:"schema"`
+	Branch     string        `json:"branch"`
+	Project    string        `json:"project"`
+	Commits    int           `json:"commits"`
+	Files      int           `json:"files"`
+	Branches   int           `json:"branches"`
+	Clean      bool          `json:"clean"`
+	Changes    ChangeSummary `json:"changes"`
+	Upstream   *UpstreamInfo `json:"upstream"`
+	LastCommit *CommitInfo   `json:"last_commit"`
+}
+
+type BranchesReport struct {
+	Schema   string       `json:"schema"`
+	Current  string       `json:"current"`
+	Branches []BranchInfo `json:"branches"`
+}
+
+type LogReport struct {
+	Schema  string       `json:"schema"`
+	Branch  string       `json:"branch"`
+	Commits []CommitInfo `json:"commits"`
+}
+
+type MergePreviewReport struct {
+	Schema string `json:"schema"`
+	Branch string `json:"branch"`
+	Into   string `json:"into"`
+	MergePreview
+}
+
+type TagsReport struct {
+	Schema string    `json:"schema"`
+	Tags   []TagInfo `json:"tags"`
+}
+
+type InsightsReport struct {
+	Schema          string               `json:"schema"`
+	Commits         int                  `json:"commits"`
+	Files           int                  `json:"files"`
+	BranchList      []BranchInfo         `json:"branches"`
+	Languages       []LanguageStats      `json:"languages"`
+	ExcludedFiles   int                  `json:"excluded_files"`
+	Authors         []AuthorStats        `json:"authors"`
+	BusFactor       []DirectoryOwnership `json:"bus_factor"`
+	Churn           *ChurnReport         `json:"churn"`
+	CommitsLastWeek int                  `json:"commits_last_week"`
+	PackSizeBytes   int64                `json:"pack_size_bytes"`
+	Objects         ObjectStats          `json:"objects"`
+}
+
+func (ga *GitAssistant) buildStatusReport(status RepoStatus) StatusReport {
+	commits, files, branches := ga.getRepoStats()
+	report := StatusReport{
+		Schema:   "gitctrl.status/v1",
+		Branch:   ga.getCurrentBranch(),
+		Project:  filepath.Base(ga.workingDir),
+		Commits:  commits,
+		Files:    files,
+		Branches: branches,
+		Clean:    len(status.Entries) == 0,
+		Changes:  categorizeChanges(status.Entries),
+	}
+	if status.Upstream.Name != "" {
+		upstream := status.Upstream
+		report.Upstream = &upstream
+	}
+	if lastCommits, err := ga.backend.Log(1); err == nil && len(lastCommits) > 0 {
+		last := normalizeCommit(lastCommits[0])
+		report.LastCommit = &last
+	}
+	return report
+}
+
+func (ga *GitAssistant) buildBranchesReport() (BranchesReport, error) {
+	branches, err := ga.backend.Branches()
+	if err != nil {
+		return BranchesReport{}, err
+	}
+	if branches == nil {
+		branches = []BranchInfo{}
+	}
+	return BranchesReport{Schema: "gitctrl.branches/v1", Current: ga.getCurrentBranch(), Branches: branches}, nil
+}
+
+func (ga *GitAssistant) buildLogReport(depth int) (LogReport, error) {
+	commits, err := ga.backend.Log(depth)
+	if err != nil {
+		return LogReport{}, err
+	}
+	report := LogReport{Schema: "gitctrl.log/v1", Branch: ga.getCurrentBranch(), Commits: []CommitInfo{}}
+	for _, commit := range commits {
+		report.Commits = append(report.Commits, normalizeCommit(commit))
+	}
+	return report, nil
+}
+
+func buildMergePreviewReport(branchName, current string, preview MergePreview) MergePreviewReport {
+	report := MergePreviewReport{Schema: "gitctrl.merge-preview/v1", Branch: branchName, Into: current, MergePreview: preview}
+	if report.Commits == nil {
+		report.Commits = []CommitInfo{}
+	}
+	for i, commit := range report.Commits {
+		report.Commits[i] = normalizeCommit(commit)
+	}
+	if report.Files == nil {
+		report.Files = []FileChange{}
+	}
+	if report.Conflicts == nil {
+		report.Conflicts = []string{}
+	}
+	return report
+}
+
+// churnSince borne la fenêtre des points chauds (nulle: tout l'historique)
+func (ga *GitAssistant) buildInsightsReport(churnSince time.Time) InsightsReport {
+	report := InsightsReport{
+		Schema:     "gitctrl.insights/v2",
+		BranchList: []BranchInfo{},
+		Languages:  []LanguageStats{},
+		Authors:    []AuthorStats{},
+		BusFactor:  []DirectoryOwnership{},
+	}
+	report.Commits, _ = ga.backend.CommitCount()
+	
+	if branches, err := ga.backend.Branches(); err == nil && branches != nil {
+		report.BranchList = branches
+	}
+	files, err := ga.backend.LsFiles()
+	if err == nil {
+		report.Files = len(files)
+	}
+	if languages, excluded, err := ga.languageBreakdown(); err == nil {
+		report.Languages, report.ExcludedFiles = languages, excluded
+	}
+	
+	// Historique détaillé indisponible: les sections restent vides et Churn nul
+	if history, err := ga.backend.LogStats(time.Time{}); err == nil {
+		report.Authors = aggregateAuthors(history)
+		report.BusFactor = busFactors(history, files)
+		if !churnSince.IsZero() {
+			history, err = ga.backend.LogStats(churnSince)
+		}
+		if err == nil {
+			churn := analyzeChurn(history, files, churnSince)
+			report.Churn = &churn
+		}
+	}
+	
+	if history, err := ga.backend.Log(0); err == nil {
+		weekAgo := time.Now().AddDate(0, 0, -7)
+		for _, commit := range history {
+			if commit.Date.After(weekAgo) {
+				report.CommitsLastWeek++
+			}
+		}
+	}
+	
+	if objects, err := ga.backend.CountObjects(); err == nil {
+		report.Objects = objects
+		report.PackSizeBytes = objects.PackSize
+	}
+	return report
+}
+
+// Listes vides plutôt que null pour garder un schéma stable
+func normalizeCommit(commit CommitInfo) CommitInfo {
+	if commit.Parents == nil {
+		commit.Parents = []string{}
+	}
+	if commit.Refs == nil {
+		commit.Refs = []string{}
+	}
+	return commit
+}
+
+func (ga *GitAssistant) writeJSON(value interface{}) error {
+	return encodeJSON(ga.out, value, true)
+}
+
+// Sans indentation: un document par ligne (journal)
+func encodeJSON(w io.Writer, value interface{}, indent bool) error {
+	encoder := json.NewEncoder(w)
+	if indent {
+		encoder.SetIndent("", "  ")
+	}
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(value)
+}
+
+// Interface en ligne de commande (mode non interactif)
+
+// Codes de sortie du mode commande
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+const cliUsage = `Usage: gitctrl [-C répertoire] [--backend auto|exec|native] [--lang fr|en] <commande> [options]
+
+Sans commande, le menu interactif est lancé.
+Le backend peut aussi être choisi avec la variable GITCTRL_BACKEND
+ou la clé backend de la configuration; la langue avec ui.lang, GITCTRL_LANG
+ou LANG.
+
+Commandes:
+  status [--format json]          Statut intelligent du dépôt
+  commit [--preset nom|-m msg] [--staged]
+                                  Ajoute tout et commite (--staged: seulement l'index)
+  commit --type feat [--scope s] [--breaking "desc"] -m sujet
+                                  Commit Conventional Commits validé
+  branch list [--format json]     Liste les branches
+  branch feature <nom>            Crée et active feature/<nom>
+  branch bugfix <description>     Crée et active bugfix/<description>
+  branch <type> <nom>             Idem pour les autres types configurés
+  branch switch [--autostash] <nom>
+                                  Change de branche (--autostash: met de côté puis
+                                  réapplique les changements locaux qui bloquent)
+  branch delete [--force] <nom>   Supprime une branche
+  branch merge [--strategy s] <nom>
+                                  Fusionne une branche dans la branche courante
+                                  (merge, ff-only, no-ff, squash ou rebase)
+  branch merge --preview [--format json] <nom>
+                                  Commits qui arrivent, fichiers touchés et conflits
+                                  prévus, sans fusionner
+  log [-n N] [--format json]      Affiche l'historique (log.depth commits par défaut)
+  remote [list]                   Liste les dépôts distants
+  remote add <nom> <url>          Ajoute un dépôt distant
+  fetch [dépôt]                   Récupère depuis un dépôt distant (tous par défaut)
+  pull [--rebase] [dépôt]         Met à jour la branche courante
+  push [-u] [dépôt]               Pousse la branche courante (-u automatique
+                                  pour les nouvelles branches feature/ et bugfix/)
+  sync [-m message]               Ajoute tout, commite, pull --rebase et push;
+                                  annule tout si une étape échoue
+  insights [--since date] [--format json]
+                                  Analyse du projet (points chauds depuis insights.churn_days)
+  undo [list | <n°>]              Restaure l'état d'avant le dernier reset, rebase,
+                                  cherry-pick, revert, la dernière suppression de branche
+                                  ou fusion (list: instantanés)
+  journal [--type t] [--since date] [-n N] [--format json]
+                                  Journal des actions du dépôt (.git/gitctrl/journal.jsonl)
+  stash [list]                    Liste les entrées mises de côté
+  stash save [-u] [-m message]    Met de côté les changements (-u: et les non suivis)
+  stash show|apply|pop|drop [n]   Diff, application, pop ou suppression de stash@{n}
+  stash branch <nom> [n]          Crée une branche depuis stash@{n} (0 par défaut)
+  conflicts [list]                Fichiers en conflit et opération en cours
+  conflicts show <fichier>        Conflits du fichier: nous, base et eux
+  conflicts ours|theirs|both <fichier>...
+                                  Résout en gardant notre version, la leur ou les deux
+  conflicts continue|abort        Termine ou abandonne la fusion (ou le rebase...)
+  conflicts skip                  Passe le commit qui a arrêté un rebase,
+                                  un cherry-pick ou un revert
+  cherry-pick [-x] <commit>...    Applique des commits sur la branche courante
+                                  (-x: note leur provenance)
+  revert [-m parent] <commit>     Annule un commit par un nouveau commit (-m: parent
+                                  principal d'un commit de fusion)
+  tag [list] [--format json]      Tags, de la version la plus récente à la plus ancienne
+  tag create [-m message] <nom> [commit]
+                                  Crée un tag annoté (message par défaut: notes
+                                  depuis la release précédente)
+  tag delete <nom>                Supprime un tag
+  release [--bump major|minor|patch] [--dry-run]
+                                  Tague HEAD avec la version suivante, déduite des
+                                  types de commits depuis la dernière release
+  changelog [--from ref] [--to ref] [--format markdown|text|json]
+                                  Commits groupés par type, du dernier tag
+                                  à HEAD par défaut
+  changelog --prepend CHANGELOG.md
+                                  Ajoute la section en tête du fichier
+  tui                             Mode plein écran: statut, branches et historique
+  config [list]                   Affiche la configuration et l'origine des valeurs
+  config set [--global] <clé> <valeur>
+                                  Modifie .gitctrl.toml (ou le fichier global)
+  help                            Affiche cette aide
+`
+
+func (ga *GitAssistant) runCLI(args []string) int {
+	global := flag.NewFlagSet("gitctrl", flag.ContinueOnError)
+	global.SetOutput(os.Stderr)
+	global.Usage = func() { fmt.Fprint(os.Stderr, tr(cliUsage)) }
+	dir := global.String("C", "", tr("répertoire de travail"))
+	backendKind := global.String("backend", "", tr("backend Git: auto, exec ou native (défaut: configuration)"))
+	lang := global.String("lang", "", tr("langue des messages: fr ou en (défaut: configuration, puis LANG)"))
+	if err := global.Parse(args); err != nil {
+		return exitUsage
+	}
+	
+	if *lang != "" {
+		if _, ok := catalogs[*lang]; !ok {
+			fmt.Fprintf(os.Stderr, red(tr("❌ Langue inconnue: %s (fr ou en)\n")), *lang)
+			return exitUsage
+		}
+		currentLocale = *lang
+		ga.localeForced = true
+	}
+	if *backendKind != "" {
+		if err := ga.setBackend(*backendKind); err != nil {
+			fmt.Fprintf(os.Stderr, red("❌ %v\n"), err)
+			return exitUsage
+		}
+	}
+	
+	if *dir != "" {
+		absPath, err := filepath.Abs(*dir)
+		if err != nil {
+			return ga.cliError(fmt.Errorf(tr("chemin invalide: %v"), err))
+		}
+		if _, err := os.Stat(absPath); err != nil {
+			return ga.cliError(fmt.Errorf(tr("le répertoire n'existe pas: %s"), absPath))
+		}
+		ga.workingDir = absPath
+		ga.reloadConfig()
+	}
+	
+	rest := global.Args()
+	if len(rest) == 0 {
+		ga.run()
+		return exitOK
+	}
+	
+	command, cmdArgs := rest[0], rest[1:]
+	ga.origin = originCLI
+	ga.autoStash = autoStashNever
+	if command == "help" || command == "-h" || command == "--help" {
+		fmt.Fprint(ga.out, tr(cliUsage))
+		return exitOK
+	}
+	if command == "config" {
+		return ga.cliConfig(cmdArgs)
+	}
+	
+	if !ga.isGitRepo() {
+		return ga.cliError(fmt.Errorf(tr("%s n'est pas un dépôt Git"), ga.workingDir))
+	}
+	
+	switch command {
+	case "status":
+		return ga.cliStatus(cmdArgs)
+	case "commit":
+		return ga.cliCommit(cmdArgs)
+	case "branch":
+		return ga.cliBranch(cmdArgs)
+	case "log":
+		return ga.cliLog(cmdArgs)
+	case "insights":
+		return ga.cliInsights(cmdArgs)
+	case "remote":
+		return ga.cliRemote(cmdArgs)
+	case "fetch", "pull", "push":
+		return ga.cliSync(command, cmdArgs)
+	case "sync":
+		return ga.cliAutoSync(cmdArgs)
+	case "undo":
+		return ga.cliUndo(cmdArgs)
+	case "journal":
+		return ga.cliJournal(cmdArgs)
+	case "stash":
+		return ga.cliStash(cmdArgs)
+	case "conflicts":
+		return ga.cliConflicts(cmdArgs)
+	case "cherry-pick":
+		return ga.cliCherryPick(cmdArgs)
+	case "revert":
+		return ga.cliRevert(cmdArgs)
+	case "tag":
+		return ga.cliTag(cmdArgs)
+	case "release":
+		return ga.cliRelease(cmdArgs)
+	case "changelog":
+		return ga.cliChangelog(cmdArgs)
+	case "tui":
+		if err := ga.runTUI(); err != nil {
+			return ga.cliError(err)
+		}
+		return exitOK
+	default:
+		fmt.Fprintf(os.Stderr, red(tr("❌ Commande inconnue: %s\n")), command)
+		fmt.Fprint(os.Stderr, tr(cliUsage))
+		return exitUsage
+	}
+}
+
+func (ga *GitAssistant) cliError(err error) int {
+	fmt.Fprintf(os.Stderr, red(tr("❌ Erreur: %v\n")), err)
+	return exitError
+}
+
+func newSubcommandFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("gitctrl "+name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
+
+// Ajoute l'option --format et retourne une fonction de validation
+func addFormatFlag(fs *flag.FlagSet) func() (string, bool) {
+	format := fs.String("format", formatText, tr("format de sortie: text ou json"))
+	return func() (string, bool) {
+		if *format != formatText && *format != formatJSON {
+			fmt.Fprintf(os.Stderr, red(tr("❌ Format inconnu: %s (text ou json)\n")), *format)
+			return "", false
+		}
+		return *format, true
+	}
+}
+
+func (ga *GitAssistant) cliStatus(args []string) int {
+	fs := newSubcommandFlags("status")
+	getFormat := addFormatFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	format, ok := getFormat()
+	if !ok {
+		return exitUsage
+	}
+	
+	if format == formatJSON {
+		status, err := ga.backend.Status()
+		if err != nil {
+			return ga.cliError(err)
+		}
+		if err := ga.writeJSON(ga.buildStatusReport(status)); err != nil {
+			return ga.cliError(err)
+		}
+		return exitOK
+	}
+	
+	if err := ga.smartStatus(); err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
+}
+
+func (ga *GitAssistant) cliCommit(args []string) int {
+	fs := newSubcommandFlags("commit")
+	presetName := fs.String("preset", "", tr("nom du message prédéfini"))
+	message := fs.String("m", "", tr("message de commit"))
+	stagedOnly := fs.Bool("staged", false, tr("commiter uniquement les fichiers indexés"))
+	ccType := fs.String("type", "", tr("type Conventional Commits (feat, fix, docs...)"))
+	ccScope := fs.String("scope", "", tr("portée Conventional Commits"))
+	ccBreaking := fs.String("breaking", "", tr("description du changement incompatible"))
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, tr("Usage: gitctrl commit [--preset nom | -m message] [--staged]"))
+		fmt.Fprintln(os.Stderr, tr("Presets disponibles:"))
+		for _, preset := range ga.quickCommits {
+			fmt.Fprintf(os.Stderr, "  %-10s %s\n", preset.Name, tr(preset.Message))
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *presetName != "" && *message != "" {
+		fmt.Fprintln(os.Stderr, red(tr("❌ --preset et -m sont incompatibles")))
+		return exitUsage
+	}
+	if *presetName != "" && *ccType != "" {
+		fmt.Fprintln(os.Stderr, red(tr("❌ --preset et --type sont incompatibles")))
+		return exitUsage
+	}
+	
+	msg := *message
+	if *presetName != "" {
+		preset, ok := ga.findPreset(*presetName)
+		if !ok {
+			fmt.Fprintf(os.Stderr, red(tr("❌ Preset inconnu: %s\n")), *presetName)
+			fs.Usage()
+			return exitUsage
+		}
+		msg = tr(preset.Message)
+	}
+	
+	if *ccType != "" {
+		cc := conventionalCommit{Type: *ccType, Scope: *ccScope, Subject: *message}
+		if *ccBreaking != "" {
+			cc.Breaking = true
+			cc.Footers = []string{"BREAKING CHANGE: " + *ccBreaking}
+		}
+		if err := cc.validate(); err != nil {
+			fmt.Fprintf(os.Stderr, red(tr("❌ Message invalide: %v\n")), err)
+			return exitUsage
+		}
+		msg = cc.String()
+	}
+	
+	status, err := ga.getStatus()
+	if err != nil {
+		return ga.cliError(err)
+	}
+	if len(status) == 0 {
+		fmt.Fprintln(ga.out, tr("ℹ️ Aucun changement à commiter"))
+		return exitOK
+	}
+	
+	commitFunc := ga.commitAllWithMessage
+	if *stagedOnly {
+		commitFunc = ga.commitSelectionWithMessage
+	}
+	if err := commitFunc(msg); err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
+}
+
+func (ga *GitAssistant) cliBranch(args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+	action, actionArgs := args[0], args[1:]
+	
+	fs := newSubcommandFlags("branch " + action)
+	force := fs.Bool("force", false, tr("forcer la suppression d'une branche non fusionnée"))
+	autostash := fs.Bool("autostash", false, tr("mettre de côté les changements locaux qui bloquent le changement de branche"))
+	strategy := fs.String("strategy", ga.config.MergeStrategy, tr("fusion: merge, ff-only, no-ff, squash ou rebase"))
+	previewOnly := fs.Bool("preview", false, tr("fusion: afficher l'aperçu sans fusionner"))
+	getFormat := addFormatFlag(fs)
+	if err := fs.Parse(actionArgs); err != nil {
+		return exitUsage
+	}
+	if *autostash {
+		ga.autoStash = autoStashAlways
+	}
+	name := strings.Join(fs.Args(), " ")
+	format, ok := getFormat()
+	if !ok {
+		return exitUsage
+	}
+	
+	var err error
+	switch action {
+	case "list":
+		if format == formatJSON {
+			var report BranchesReport
+			if report, err = ga.buildBranchesReport(); err == nil {
+				err = ga.writeJSON(report)
+			}
+		} else {
+			err = ga.printBranches()
+		}
+	case "switch", "delete", "merge":
+		if name == "" {
+			fmt.Fprintf(os.Stderr, red(tr("❌ gitctrl branch %s: nom requis\n")), action)
+			return exitUsage
+		}
+		switch action {
+		case "switch":
+			err = ga.switchBranch(name)
+		case "delete":
+			err = ga.removeBranch(name, *force)
+		case "merge":
+			if !isMergeStrategy(*strategy) {
+				fmt.Fprintf(os.Stderr, red(tr("❌ Stratégie de fusion inconnue: %s\n")), *strategy)
+				return exitUsage
+			}
+			if *previewOnly {
+				return ga.cliMergePreview(name, format)
+			}
+			err = ga.mergeInto(name, *strategy)
+		}
+	default:
+		// Types de branches configurés (feature, bugfix...)
+		if bt, ok := ga.config.branchType(action); ok {
+			if name == "" {
+				fmt.Fprintf(os.Stderr, red(tr("❌ gitctrl branch %s: nom requis\n")), action)
+				return exitUsage
+			}
+			if err := ga.createTypedBranch(bt.Prefix, name, tr("Branche créée")); err != nil {
+				return ga.cliError(err)
+			}
+			return exitOK
+		}
+		fmt.Fprintf(os.Stderr, red(tr("❌ Action de branche inconnue: %s\n")), action)
+		fmt.Fprint(os.Stderr, tr(cliUsage))
+		return exitUsage
+	}
+	
+	if err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
+}
+
+func (ga *GitAssistant) cliMergePreview(branchName, format string) int {
+	preview, err := ga.backend.PreviewMerge(branchName)
+	if err != nil {
+		return ga.cliError(err)
+	}
+	current := ga.getCurrentBranch()
+	if format == formatJSON {
+		if err := ga.writeJSON(buildMergePreviewReport(branchName, current, preview)); err != nil {
+			return ga.cliError(err)
+		}
+		return exitOK
+	}
+	ga.printMergePreview(branchName, current, preview)
+	return exitOK
+}
+
+func (ga *GitAssistant) cliLog(args []string) int {
+	fs := newSubcommandFlags("log")
+	depth := fs.Int("n", ga.config.LogDepth, tr("nombre de commits"))
+	getFormat := addFormatFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	format, ok := getFormat()
+	if !ok {
+		return exitUsage
+	}
+	if *depth < 1 {
+		fmt.Fprintln(os.Stderr, red(tr("❌ -n doit être supérieur à 0")))
+		return exitUsage
+	}
+	
+	if format == formatJSON {
+		report, err := ga.buildLogReport(*depth)
+		if err == nil {
+			err = ga.writeJSON(report)
+		}
+		if err != nil {
+			return ga.cliError(err)
+		}
+		return exitOK
+	}
+	
+	if err := ga.printLog(*depth); err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
+}
+
+func (ga *GitAssistant) cliInsights(args []string) int {
+	fs := newSubcommandFlags("insights")
+	since := fs.String("since", "", tr("début des points chauds: date (AAAA-MM-JJ, RFC 3339), durée (90d) ou all"))
+	getFormat := addFormatFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	format, ok := getFormat()
+	if !ok {
+		return exitUsage
+	}
+	
+	churnSince := ga.churnSince()
+	switch *since {
+	case "":
+	case "all":
+		churnSince = time.Time{}
+	default:
+		var err error
+		if churnSince, err = parseJournalDate(*since, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, red("❌ %v\n"), err)
+			return exitUsage
+		}
+	}
+	
+	if format == formatJSON {
+		if err := ga.writeJSON(ga.buildInsightsReport(churnSince)); err != nil {
+			return ga.cliError(err)
+		}
+		return exitOK
+	}
+	
+	if err := ga.projectInsights(churnSince); err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
+}
+
+func (ga *GitAssistant) cliRemote(args []string) int {
+	if len(args) == 0 || args[0] == "list" {
+		if err := ga.printRemotes(); err != nil {
+			return ga.cliError(err)
+		}
+		return exitOK
+	}
+	if args[0] != "add" || len(args) != 3 {
+		fmt.Fprintln(os.Stderr, tr("Usage: gitctrl remote [list] | gitctrl remote add <nom> <url>"))
+		return exitUsage
 	}
-	
-	fmt.Println("🎉 Synchronisation locale terminée!")
-	ga.addToHistory("Synchronisation automatique")
-	return nil
+	if err := ga.addRemote(args[1], args[2]); err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
 }
 
-func (ga *GitAssistant) switchBranch(name string) error {
-	if name == "" {
-		fmt.Print("🔄 Nom de la branche: ")
-		name = ga.getUserInput()
+func (ga *GitAssistant) cliUndo(args []string) int {
+	backups, err := ga.loadBackups()
+	if err != nil {
+		return ga.cliError(err)
 	}
-	
-	if name == "" {
-		return fmt.Errorf("nom de branche requis")
+	if len(args) > 0 && args[0] == "list" {
+		if len(backups) == 0 {
+			fmt.Fprintln(ga.out, tr("ℹ️ Aucun instantané à restaurer"))
+		}
+		ga.printBackups(backups)
+		return exitOK
 	}
 	
-	fmt.Printf("🔄 Changement vers la branche: %s\n", name)
-	_, err := ga.runCommand("git", "checkout", name)
-	if err != nil {
-		return fmt.Errorf("erreur lors du changement de branche: %v", err)
+	choice := 1
+	if len(args) > 0 {
+		choice, err = strconv.Atoi(args[0])
+		if err != nil || len(args) > 1 {
+			fmt.Fprintln(os.Stderr, tr("Usage: gitctrl undo [list | <n°>]"))
+			return exitUsage
+		}
 	}
-	fmt.Println("✅ Branche changée!")
-	ga.addToHistory(fmt.Sprintf("Changement vers: %s", name))
-	return nil
+	if choice < 1 || choice > len(backups) {
+		return ga.cliError(fmt.Errorf(tr("aucun instantané n°%d (voir gitctrl undo list)"), choice))
+	}
+	if err := ga.undo(backups[choice-1]); err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
 }
 
-func (ga *GitAssistant) resetToCommit() error {
-	fmt.Println("📜 Historique récent:")
-	output, err := ga.runCommand("git", "log", "--oneline", "-10")
-	if err != nil {
-		return err
+func (ga *GitAssistant) cliJournal(args []string) int {
+	fs := newSubcommandFlags("journal")
+	action := fs.String("type", "", tr("type d'action (commit, branch, merge, reset...)"))
+	since := fs.String("since", "", tr("depuis une date (AAAA-MM-JJ, RFC 3339) ou une durée (12h, 7d)"))
+	limit := fs.Int("n", 0, tr("nombre d'entrées, les plus récentes (0 = toutes)"))
+	getFormat := addFormatFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
 	}
-	fmt.Println(output)
-	
-	fmt.Println("\n🔄 Types de reset:")
-	fmt.Println("1. 🟢 SOFT - Garde les changements dans le staging")
-	fmt.Println("2. 🟡 MIXED - Garde les changements mais pas dans le staging")
-	fmt.Println("3. 🔴 HARD - Supprime TOUS les changements")
-	fmt.Print("\nType (1-3): ")
-	
-	resetType := ga.getUserInput()
-	var resetFlag string
-	
-	switch resetType {
-	case "1":
-		resetFlag = "--soft"
-	case "2":
-		resetFlag = "--mixed"
-	case "3":
-		resetFlag = "--hard"
-	default:
-		resetFlag = "--mixed"
+	format, ok := getFormat()
+	if !ok {
+		return exitUsage
 	}
-	
-	fmt.Print("🎯 Hash du commit (ou HEAD~n): ")
-	commitHash := ga.getUserInput()
-	
-	if commitHash == "" {
-		return fmt.Errorf("hash requis")
+	if fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, tr("Usage: gitctrl journal [--type <type>] [--since <date>] [-n <nombre>] [--format json]"))
+		return exitUsage
 	}
 	
-	_, err = ga.runCommand("git", "reset", resetFlag, commitHash)
+	filter := journalFilter{Action: *action}
+	if *since != "" {
+		var err error
+		if filter.Since, err = parseJournalDate(*since, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, red("❌ %v\n"), err)
+			return exitUsage
+		}
+	}
+	entries, err := ga.loadJournal()
 	if err != nil {
-		return err
+		return ga.cliError(err)
+	}
+	selected := filterJournal(entries, filter)
+	if *limit > 0 && len(selected) > *limit {
+		selected = selected[len(selected)-*limit:]
 	}
 	
-	fmt.Println("✅ Reset effectué!")
-	ga.addToHistory(fmt.Sprintf("Reset %s vers %s", resetFlag, commitHash))
-	return nil
+	if format == formatJSON {
+		if err := ga.writeJSON(JournalReport{Schema: "gitctrl.journal/v1", Entries: selected}); err != nil {
+			return ga.cliError(err)
+		}
+		return exitOK
+	}
+	if len(selected) == 0 {
+		fmt.Fprintln(ga.out, tr("ℹ️ Aucune action ne correspond au filtre"))
+	}
+	ga.printJournal(selected)
+	return exitOK
 }
 
-func (ga *GitAssistant) createBranchFromCommit() error {
-	fmt.Println("📜 Historique récent:")
-	output, err := ga.runCommand("git", "log", "--oneline", "-10")
-	if err != nil {
-		return err
+func (ga *GitAssistant) cliStash(args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
 	}
-	fmt.Println(output)
+	action, actionArgs := args[0], args[1:]
 	
-	fmt.Print("\n🎯 Hash du commit: ")
-	commitHash := ga.getUserInput()
-	
-	fmt.Print("🌱 Nom de la branche: ")
-	branchName := ga.getUserInput()
+	fs := newSubcommandFlags("stash " + action)
+	message := fs.String("m", "", tr("message de l'entrée"))
+	untracked := fs.Bool("u", false, tr("inclure les fichiers non suivis"))
+	if err := fs.Parse(actionArgs); err != nil {
+		return exitUsage
+	}
+	usage := func() int {
+		fmt.Fprintln(os.Stderr, tr("Usage: gitctrl stash [list | save [-u] [-m message] | show|apply|pop|drop [n] | branch <nom> [n]]"))
+		return exitUsage
+	}
 	
-	if commitHash == "" || branchName == "" {
-		return fmt.Errorf("hash et nom requis")
+	rest, ref := fs.Args(), "stash@{0}"
+	var err error
+	switch action {
+	case "list":
+		var stashes []StashInfo
+		if stashes, err = ga.backend.Stashes(); err == nil {
+			ga.printStashes(stashes)
+		}
+	case "save":
+		if len(rest) > 0 {
+			return usage()
+		}
+		err = ga.saveStash(*message, *untracked)
+	case "show", "apply", "pop", "drop":
+		if len(rest) > 1 {
+			return usage()
+		}
+		if len(rest) == 1 {
+			ref = stashRef(rest[0])
+		}
+		switch action {
+		case "show":
+			var diff string
+			if diff, err = ga.backend.StashShow(ref); err == nil {
+				ga.displayColoredDiff(diff)
+			}
+		case "apply", "pop":
+			err = ga.applyStash(ref, action == "pop")
+		case "drop":
+			err = ga.dropStash(ref)
+		}
+	case "branch":
+		if len(rest) == 0 || len(rest) > 2 {
+			return usage()
+		}
+		if len(rest) == 2 {
+			ref = stashRef(rest[1])
+		}
+		err = ga.branchFromStash(rest[0], ref)
+	default:
+		fmt.Fprintf(os.Stderr, red(tr("❌ Action de stash inconnue: %s\n")), action)
+		return usage()
 	}
 	
-	_, err = ga.runCommand("git", "checkout", "-b", branchName, commitHash)
 	if err != nil {
-		return err
+		return ga.cliError(err)
 	}
-	
-	fmt.Println("✅ Branche créée et activée!")
-	ga.addToHistory(fmt.Sprintf("Branche %s depuis %s", branchName, commitHash))
-	return nil
+	return exitOK
 }
 
-func (ga *GitAssistant) setWorkingDirectory(newPath string) error {
-	if newPath == "" {
-		return fmt.Errorf("chemin vide")
+func (ga *GitAssistant) cliCherryPick(args []string) int {
+	fs := newSubcommandFlags("cherry-pick")
+	recordOrigin := fs.Bool("x", false, tr("noter la provenance dans le message"))
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
 	}
-	
-	// Convertir en chemin absolu
-	absPath, err := filepath.Abs(newPath)
-	if err != nil {
-		return fmt.Errorf("chemin invalide: %v", err)
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, tr("Usage: gitctrl cherry-pick [-x] <commit>..."))
+		return exitUsage
 	}
-	
-	// Vérifier que le répertoire existe
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		return fmt.Errorf("le répertoire n'existe pas: %s", absPath)
+	if err := ga.cherryPick(fs.Args(), *recordOrigin); err != nil {
+		return ga.cliError(err)
 	}
-	
-	ga.workingDir = absPath
-	ga.lastActions = make([]string, 0) // Reset l'historique pour le nouveau projet
-	fmt.Printf(green("✅ Répertoire défini: %s\n"), ga.workingDir)
-	return nil
+	return exitOK
 }
 
-func (ga *GitAssistant) changeDirectory() error {
-	fmt.Print("📁 Nouveau répertoire: ")
-	newPath := ga.getUserInput()
-	return ga.setWorkingDirectory(newPath)
+func (ga *GitAssistant) cliRevert(args []string) int {
+	fs := newSubcommandFlags("revert")
+	mainline := fs.Int("m", 0, tr("parent principal d'un commit de fusion (1 pour le premier)"))
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 1 || *mainline < 0 {
+		fmt.Fprintln(os.Stderr, tr("Usage: gitctrl revert [-m parent] <commit>"))
+		return exitUsage
+	}
+	if err := ga.revert(fs.Arg(0), *mainline); err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
 }
 
-func (ga *GitAssistant) handleNonGitRepo() bool {
-	fmt.Println("\n⚠️ Ce répertoire n'est pas un dépôt Git.")
-	fmt.Println("1. 🔧 Initialiser un dépôt Git ici")
-	fmt.Println("2. 📁 Changer de répertoire")
-	fmt.Println("3. ❌ Quitter")
-	fmt.Print("\nChoisissez (1-3): ")
+func (ga *GitAssistant) cliTag(args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+	action, actionArgs := args[0], args[1:]
 	
-	choice := ga.getUserInput()
+	fs := newSubcommandFlags("tag " + action)
+	message := fs.String("m", "", tr("message du tag (défaut: notes générées)"))
+	getFormat := addFormatFlag(fs)
+	if err := fs.Parse(actionArgs); err != nil {
+		return exitUsage
+	}
+	format, ok := getFormat()
+	if !ok {
+		return exitUsage
+	}
+	usage := func() int {
+		fmt.Fprintln(os.Stderr, tr("Usage: gitctrl tag [list [--format json] | create [-m message] <nom> [commit] | delete <nom>]"))
+		return exitUsage
+	}
 	
-	switch choice {
-	case "1":
-		if err := ga.initRepo(); err != nil {
-			fmt.Printf("❌ Erreur: %v\n", err)
-			return false
+	rest := fs.Args()
+	var err error
+	switch action {
+	case "list":
+		var tags []TagInfo
+		if tags, err = ga.backend.Tags(); err != nil {
+			break
 		}
-		return true
-	case "2":
-		if err := ga.changeDirectory(); err != nil {
-			fmt.Printf("❌ Erreur: %v\n", err)
-			return false
+		sortTags(tags)
+		if format == formatJSON {
+			if tags == nil {
+				tags = []TagInfo{}
+			}
+			err = ga.writeJSON(TagsReport{Schema: "gitctrl.tags/v1", Tags: tags})
+		} else {
+			ga.printTags(tags)
 		}
-		return ga.checkGitRepoOrHandle()
-	case "3":
-		fmt.Println("👋 Au revoir!")
-		return false
+	case "create":
+		if len(rest) == 0 || len(rest) > 2 {
+			return usage()
+		}
+		target := "HEAD"
+		if len(rest) == 2 {
+			target = rest[1]
+		}
+		err = ga.createTag(rest[0], target, *message)
+	case "delete":
+		if len(rest) != 1 {
+			return usage()
+		}
+		err = ga.deleteTag(rest[0])
 	default:
-		fmt.Println("❌ Choix invalide!")
-		return false
+		fmt.Fprintf(os.Stderr, red(tr("❌ Action inconnue: %s\n")), action)
+		return usage()
 	}
-}
-
-func (ga *GitAssistant) checkGitRepoOrHandle() bool {
-	if ga.isGitRepo() {
-		return true
+	
+	if err != nil {
+		return ga.cliError(err)
 	}
-	return ga.handleNonGitRepo()
+	return exitOK
 }
 
-func (ga *GitAssistant) clearScreen() {
-	cmd := exec.Command("clear")
-	cmd.Stdout = os.Stdout
-	cmd.Run()
+// Tague HEAD avec la version suivante et des notes générées depuis la release précédente
+func (ga *GitAssistant) cliRelease(args []string) int {
+	fs := newSubcommandFlags("release")
+	bump := fs.String("bump", "", tr("incrément: major, minor ou patch (défaut: d'après les commits)"))
+	dryRun := fs.Bool("dry-run", false, tr("afficher la version et les notes sans créer le tag"))
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 || (*bump != "" && !isBump(*bump)) {
+		fmt.Fprintln(os.Stderr, tr("Usage: gitctrl release [--bump major|minor|patch] [--dry-run]"))
+		return exitUsage
+	}
+	
+	plan, err := ga.planRelease("HEAD", *bump)
+	if err != nil {
+		return ga.cliError(err)
+	}
+	ga.printReleasePlan(plan)
+	if len(plan.Commits) == 0 {
+		fmt.Fprintf(ga.out, tr("ℹ️ Aucun commit depuis %s: rien à publier\n"), plan.Previous)
+		return exitOK
+	}
+	notes, err := ga.releaseNotes(plan.Next, plan, "HEAD")
+	if err != nil {
+		return ga.cliError(err)
+	}
+	fmt.Fprintf(ga.out, "\n%s\n\n", notes)
+	if *dryRun {
+		return exitOK
+	}
+	if err := ga.createTag(plan.Next, "HEAD", notes); err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
 }
 
-func (ga *GitAssistant) showSmartMenu() {
-	ga.clearScreen()
-	
-	// ASCII Art titre
-	fmt.Println(cyan(`
-   ___ ___ _____ ___ _____ ___ _    
-  / __|_ _|_   _/ __|_   _| _ \ |   
- | (_ || |  | || (__  | | |   / |__ 
-  \___|___| |_| \___| |_| |_|_\____|`))
-	
-	// Header avec infos contextuelles
-	fmt.Printf("\n🚀 === %s ===\n", bold("GIT ASSISTANT INTELLIGENT"))
-	fmt.Printf("📁 Répertoire: %s\n", cyan(ga.workingDir))
+func (ga *GitAssistant) cliChangelog(args []string) int {
+	fs := newSubcommandFlags("changelog")
+	from := fs.String("from", "", tr("début de l'intervalle, exclu (défaut: dernier tag)"))
+	to := fs.String("to", "HEAD", tr("fin de l'intervalle"))
+	title := fs.String("title", "", tr("titre de la section (défaut: --to, ou « Non publié » pour HEAD)"))
+	format := fs.String("format", formatMarkdown, tr("format de sortie: markdown, text ou json"))
+	prepend := fs.String("prepend", "", tr("ajouter la section Markdown en tête de ce fichier (ex: CHANGELOG.md)"))
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, tr("Usage: gitctrl changelog [--from ref] [--to ref] [--title titre] [--format markdown|text|json] [--prepend fichier]"))
+		return exitUsage
+	}
+	switch {
+	case *format != formatMarkdown && *format != formatText && *format != formatJSON:
+		fmt.Fprintf(os.Stderr, red(tr("❌ Format inconnu: %s (markdown, text ou json)\n")), *format)
+		return exitUsage
+	case *prepend != "" && *format != formatMarkdown:
+		fmt.Fprintln(os.Stderr, red(tr("❌ --prepend écrit toujours du Markdown: retirez --format")))
+		return exitUsage
+	}
 	
-	if ga.isGitRepo() {
-		branch := ga.getCurrentBranch()
-		commits, _, _ := ga.getRepoStats()
-		fmt.Printf("🌿 Branche: %s | 📊 %d commits", green(branch), commits)
-		
-		// Vérifier s'il y a des changements
-		status, _ := ga.getStatus()
-		if strings.TrimSpace(status) != "" {
-			fmt.Print(" | " + red("⚠️ Changements non commitées"))
+	start := *from
+	if start == "" {
+		var err error
+		if start, err = ga.defaultChangelogFrom(*to); err != nil {
+			return ga.cliError(err)
 		}
-		fmt.Println()
-		
-		fmt.Printf("\n=== %s ===\n", cyan("ACTIONS RAPIDES"))
-		fmt.Println("1. ⚡ Commit rapide (messages prédéfinis)")
-		
-		fmt.Printf("\n=== %s ===\n", cyan("GESTION AVANCÉE"))
-		fmt.Println("2. 🌿 Gestion intelligente des branches")
-		fmt.Println("3. 📜 Historique interactif")
-		fmt.Println("4. 📊 Analyse du projet")
-		
-		fmt.Printf("\n=== %s ===\n", cyan("NAVIGATION"))
-		fmt.Println("5. 📁 Changer de répertoire")
-		fmt.Println("6. 🔧 Initialiser Git")
-	} else {
-		fmt.Println(red("⚠️ Pas un dépôt Git"))
-		fmt.Printf("\n=== %s ===\n", cyan("ACTIONS DISPONIBLES"))
-		fmt.Println("1. 🔧 Initialiser un dépôt Git ici")
-		fmt.Println("2. 📁 Changer de répertoire")
+	}
+	report, err := ga.buildChangelog(start, *to, *title)
+	if err != nil {
+		return ga.cliError(err)
 	}
 	
-	fmt.Println("\n0. ❌ Quitter")
-	fmt.Print(cyan("\n💫 Choisissez une action: "))
-}
-
-func (ga *GitAssistant) getUserInput() string {
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	return strings.TrimSpace(scanner.Text())
+	switch {
+	case *format == formatJSON:
+		err = ga.writeJSON(report)
+	case report.empty():
+		fmt.Fprintln(ga.out, tr("ℹ️ Aucun commit dans cet intervalle"))
+	case *prepend != "":
+		err = ga.writeChangelog(*prepend, report)
+	default:
+		fmt.Fprint(ga.out, renderChangelog(report, *format == formatMarkdown))
+	}
+	if err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
 }
 
-func (ga *GitAssistant) run() {
-	fmt.Println(bold("🎯 Git Assistant Intelligent démarré!"))
-	
-	// Première action obligatoire : définir le répertoire de travail
-	fmt.Printf("\n📁 === %s ===\n", cyan("SÉLECTION DU RÉPERTOIRE DE TRAVAIL"))
-	fmt.Printf("Répertoire actuel: %s\n", cyan(ga.workingDir))
-	fmt.Print(cyan("Entrez le chemin du dossier de travail: "))
-	
-	newPath := ga.getUserInput()
-	if newPath != "" {
-		if err := ga.setWorkingDirectory(newPath); err != nil {
-			fmt.Printf(red("❌ Erreur: %v\n"), err)
-			fmt.Print("Continuer avec le répertoire actuel? (o/N): ")
-			if strings.ToLower(ga.getUserInput()) != "o" {
-				fmt.Println("👋 Au revoir!")
-				return
-			}
-		}
+func (ga *GitAssistant) cliConflicts(args []string) int {
+	usage := func() int {
+		fmt.Fprintln(os.Stderr, tr("Usage: gitctrl conflicts [list | show <fichier> | ours|theirs|both <fichier>... | continue | skip | abort]"))
+		return exitUsage
+	}
+	if len(args) == 0 {
+		args = []string{"list"}
 	}
+	action, paths := args[0], args[1:]
+	op := ga.pendingOperation()
 	
-	for {
-		ga.showSmartMenu()
-		choice := ga.getUserInput()
-		
-		switch choice {
-		case "1":
-			if ga.isGitRepo() {
-				if err := ga.quickCommit(); err != nil {
-					fmt.Printf(red("❌ Erreur: %v\n"), err)
-				}
-			} else {
-				if err := ga.initRepo(); err != nil {
-					fmt.Printf(red("❌ Erreur: %v\n"), err)
-				}
-			}
-			
-		case "2":
-			if ga.isGitRepo() {
-				if err := ga.intelligentBranching(); err != nil {
-					fmt.Printf(red("❌ Erreur: %v\n"), err)
-				}
-			} else {
-				if err := ga.changeDirectory(); err != nil {
-					fmt.Printf(red("❌ Erreur: %v\n"), err)
-				}
-			}
-			
-		case "3":
-			if ga.isGitRepo() {
-				if err := ga.interactiveLog(); err != nil {
-					fmt.Printf(red("❌ Erreur: %v\n"), err)
-				}
-			} else {
-				fmt.Println(red("❌ Cette action nécessite un dépôt Git"))
+	var err error
+	switch action {
+	case "list":
+		var conflicts []ConflictedFile
+		if conflicts, err = ga.conflictedFiles(); err == nil {
+			if op != "" {
+				fmt.Fprintf(ga.out, tr("Opération en cours: %s\n"), op)
 			}
-			
-		case "4":
-			if ga.isGitRepo() {
-				if err := ga.projectInsights(); err != nil {
-					fmt.Printf(red("❌ Erreur: %v\n"), err)
-				}
-			} else {
-				fmt.Println(red("❌ Cette action nécessite un dépôt Git"))
+			if len(conflicts) == 0 {
+				fmt.Fprintln(ga.out, green(tr("✅ Aucun conflit")))
 			}
-			
-		case "5":
-			if ga.isGitRepo() {
-				if err := ga.changeDirectory(); err != nil {
-					fmt.Printf(red("❌ Erreur: %v\n"), err)
-				}
-			} else {
-				fmt.Println(red("❌ Cette action nécessite un dépôt Git"))
+			ga.printConflicts(conflicts)
+		}
+	case "show":
+		if len(paths) != 1 {
+			return usage()
+		}
+		var stages ConflictStages
+		var hunks []mergeHunk
+		if stages, hunks, err = ga.loadConflict(paths[0]); err == nil {
+			ga.printConflictHunks(stages, hunks)
+		}
+	case sideOurs, sideTheirs, sideBoth:
+		if len(paths) == 0 {
+			return usage()
+		}
+		for _, path := range paths {
+			if err = ga.resolveConflictWith(path, action); err != nil {
+				break
 			}
-			
-		case "6":
-			if ga.isGitRepo() {
-				if err := ga.initRepo(); err != nil {
-					fmt.Printf(red("❌ Erreur: %v\n"), err)
-				}
-			} else {
-				fmt.Println(red("❌ Cette action nécessite un dépôt Git"))
+		}
+	case "continue", "skip", "abort":
+		if len(paths) > 0 {
+			return usage()
+		}
+		if op == "" {
+			return ga.cliError(errors.New(tr("aucune opération en cours")))
+		}
+		switch action {
+		case "continue":
+			err = ga.continueOperation(op)
+		case "skip":
+			if op == opMerge {
+				return ga.cliError(errors.New(tr("une fusion ne se passe pas: poursuivez-la ou abandonnez-la")))
 			}
-			
-		case "0":
-			fmt.Println("👋 Au revoir!")
-			return
-			
+			err = ga.skipOperation(op)
 		default:
-			fmt.Println(red("❌ Option invalide!"))
+			err = ga.abortOperation(op)
 		}
-		
-		fmt.Println("\n⏸️ Appuyez sur Entrée pour continuer...")
-		ga.getUserInput()
+	default:
+		fmt.Fprintf(os.Stderr, red(tr("❌ Action inconnue: %s\n")), action)
+		return usage()
+	}
+	
+	if err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
+}
+
+func (ga *GitAssistant) cliSync(command string, args []string) int {
+	fs := newSubcommandFlags(command)
+	rebase := fs.Bool("rebase", false, tr("pull: rebaser au lieu de fusionner"))
+	setUpstream := fs.Bool("u", false, tr("push: définir la branche suivie"))
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintf(os.Stderr, red(tr("❌ gitctrl %s: un seul dépôt distant attendu\n")), command)
+		return exitUsage
+	}
+	remote := fs.Arg(0)
+	
+	var err error
+	switch command {
+	case "fetch":
+		err = ga.fetchRemote(remote)
+	case "pull":
+		err = ga.pullCurrentBranch(remote, *rebase)
+	case "push":
+		err = ga.pushCurrentBranch(remote, *setUpstream)
+	}
+	if err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
+}
+
+func (ga *GitAssistant) cliAutoSync(args []string) int {
+	fs := newSubcommandFlags("sync")
+	message := fs.String("m", "", tr("message de commit (généré par défaut)"))
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if err := ga.syncWithMessage(*message); err != nil {
+		return ga.cliError(err)
+	}
+	return exitOK
+}
+
+func (ga *GitAssistant) cliConfig(args []string) int {
+	if len(args) == 0 || args[0] == "list" {
+		for _, setting := range configSettings {
+			fmt.Fprintf(ga.out, "%-16s %-8s (%s)\n", setting.Key, setting.get(ga.config), ga.config.source(setting.Key))
+		}
+		for _, preset := range ga.config.Presets {
+			fmt.Fprintf(ga.out, "preset %-9s %s (%s)\n", preset.Name, tr(preset.Message), ga.config.source("presets"))
+		}
+		for _, bt := range ga.config.BranchTypes {
+			fmt.Fprintf(ga.out, tr("branche %-8s %s (%s)\n"), bt.Name, bt.Prefix, ga.config.source("branch_types"))
+		}
+		return exitOK
+	}
+	
+	fs := newSubcommandFlags("config " + args[0])
+	global := fs.Bool("global", false, tr("modifier le fichier global"))
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	setting, ok := findConfigSetting(fs.Arg(0))
+	if args[0] != "set" || fs.NArg() != 2 || !ok {
+		fmt.Fprintln(os.Stderr, tr("Usage: gitctrl config [list] | gitctrl config set [--global] <clé> <valeur>"))
+		fmt.Fprint(os.Stderr, tr("Clés:"))
+		for _, setting := range configSettings {
+			fmt.Fprintf(os.Stderr, " %s", setting.Key)
+		}
+		fmt.Fprintln(os.Stderr)
+		return exitUsage
+	}
+	
+	path := repoConfigPath(ga.workingDir)
+	if *global {
+		path = globalConfigPath()
+	}
+	if err := ga.writeSetting(path, setting, fs.Arg(1)); err != nil {
+		return ga.cliError(err)
 	}
+	return exitOK
 }
 
 func main() {
 	assistant := NewGitAssistant()
-	assistant.run()
+	os.Exit(assistant.runCLI(os.Args[1:]))
 }
-		
\ No newline at end of file
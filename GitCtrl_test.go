@@ -0,0 +1,2433 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Backend factice scriptable: réponses prédéfinies et journal des appels
+
+type fakeBackend struct {
+	branch   string
+	status   []StatusEntry
+	commits  []CommitInfo
+	branches []BranchInfo
+	files    []string
+	remotes  []RemoteInfo
+	upstream UpstreamInfo
+	refs     map[string]string
+	snapshot string
+	stashes  []StashInfo
+	preview  MergePreview
+	errs     map[string]error
+	calls    []string
+}
+
+func (fb *fakeBackend) record(method string, args ...string) error {
+	fb.calls = append(fb.calls, strings.TrimSpace(method+" "+strings.Join(args, " ")))
+	return fb.errs[method]
+}
+
+func (fb *fakeBackend) called(call string) bool {
+	for _, c := range fb.calls {
+		if c == call {
+			return true
+		}
+	}
+	return false
+}
+
+func (fb *fakeBackend) Name() string                   { return "fake" }
+func (fb *fakeBackend) Init() error                    { return fb.record("Init") }
+func (fb *fakeBackend) CurrentBranch() (string, error) { return fb.branch, fb.errs["CurrentBranch"] }
+func (fb *fakeBackend) CommitCount() (int, error)      { return len(fb.commits), nil }
+func (fb *fakeBackend) Status() (RepoStatus, error) {
+	return RepoStatus{Branch: fb.branch, Upstream: fb.upstream, Entries: fb.status}, fb.errs["Status"]
+}
+func (fb *fakeBackend) Branches() ([]BranchInfo, error) {
+	return fb.branches, fb.errs["Branches"]
+}
+func (fb *fakeBackend) LsFiles() ([]string, error) { return fb.files, nil }
+func (fb *fakeBackend) WalkHeadBlobs(visit func(path string, data []byte) error) error {
+	return fb.errs["WalkHeadBlobs"]
+}
+func (fb *fakeBackend) CountObjects() (ObjectStats, error) {
+	return ObjectStats{PackSize: 2048}, nil
+}
+
+func (fb *fakeBackend) Log(limit int) ([]CommitInfo, error) {
+	if limit > 0 && limit < len(fb.commits) {
+		return fb.commits[:limit], nil
+	}
+	return fb.commits, fb.errs["Log"]
+}
+
+func (fb *fakeBackend) LogFrom(rev string, limit int) ([]CommitInfo, error) {
+	err := fb.record("LogFrom", rev, strconv.Itoa(limit))
+	if limit > 0 && limit < len(fb.commits) {
+		return fb.commits[:limit], err
+	}
+	return fb.commits, err
+}
+
+func (fb *fakeBackend) Add(paths ...string) error { return fb.record("Add", paths...) }
+
+func (fb *fakeBackend) Unstage(paths ...string) error { return fb.record("Unstage", paths...) }
+
+func (fb *fakeBackend) Diff(path string, cached bool) (string, error) {
+	return "", fb.record("Diff", path, fmt.Sprint(cached))
+}
+
+func (fb *fakeBackend) ApplyToIndex(patch string, reverse bool) error {
+	return fb.record("ApplyToIndex", fmt.Sprint(reverse))
+}
+
+func (fb *fakeBackend) Commit(message string) error {
+	if err := fb.record("Commit", message); err != nil {
+		return err
+	}
+	fb.status = nil
+	hash := fmt.Sprintf("%040d", len(fb.commits)+1)
+	fb.commits = append([]CommitInfo{{Hash: hash, ShortHash: hash[33:], Subject: message}}, fb.commits...)
+	return nil
+}
+
+func (fb *fakeBackend) Checkout(ref string) error {
+	if err := fb.record("Checkout", ref); err != nil {
+		return err
+	}
+	fb.branch = ref
+	return nil
+}
+
+func (fb *fakeBackend) CreateBranch(name, startPoint string) error {
+	if err := fb.record("CreateBranch", name, startPoint); err != nil {
+		return err
+	}
+	fb.branch = name
+	return nil
+}
+
+func (fb *fakeBackend) DeleteBranch(name string, force bool) error {
+	return fb.record("DeleteBranch", name, fmt.Sprint(force))
+}
+
+func (fb *fakeBackend) Merge(branch, strategy string) error { return fb.record("Merge", branch, strategy) }
+
+func (fb *fakeBackend) CanMerge(branch, strategy string) error { return fb.errs["CanMerge"] }
+
+func (fb *fakeBackend) PreviewMerge(branch string) (MergePreview, error) {
+	return fb.preview, fb.errs["PreviewMerge"]
+}
+
+func (fb *fakeBackend) Rebase(upstream, branch string) error {
+	return fb.record("Rebase", upstream, branch)
+}
+
+func (fb *fakeBackend) CherryPick(hashes []string, recordOrigin bool) error {
+	return fb.record("CherryPick", append(hashes, strconv.FormatBool(recordOrigin))...)
+}
+
+func (fb *fakeBackend) Revert(hash string, mainline int) error {
+	return fb.record("Revert", hash, strconv.Itoa(mainline))
+}
+
+func (fb *fakeBackend) ReadCommit(rev string) (CommitInfo, error) {
+	for _, commit := range fb.commits {
+		if strings.HasPrefix(commit.Hash, rev) {
+			return commit, nil
+		}
+	}
+	return CommitInfo{}, fmt.Errorf("commit introuvable: %s", rev)
+}
+
+func (fb *fakeBackend) Reset(mode, target string) error { return fb.record("Reset", mode, target) }
+
+func (fb *fakeBackend) Remotes() ([]RemoteInfo, error) { return fb.remotes, nil }
+
+func (fb *fakeBackend) AddRemote(name, url string) error {
+	fb.remotes = append(fb.remotes, RemoteInfo{name, url})
+	return fb.record("AddRemote", name, url)
+}
+
+func (fb *fakeBackend) Fetch(remote string) error { return fb.record("Fetch", remote) }
+
+func (fb *fakeBackend) Pull(remote, branch string, rebase bool) error {
+	return fb.record("Pull", remote, branch, fmt.Sprint(rebase))
+}
+
+func (fb *fakeBackend) Push(remote, branch string, setUpstream bool) error {
+	if err := fb.record("Push", remote, branch, fmt.Sprint(setUpstream)); err != nil {
+		return err
+	}
+	if setUpstream {
+		fb.upstream = UpstreamInfo{Name: remote + "/" + branch}
+	}
+	return nil
+}
+
+func (fb *fakeBackend) Upstream() (UpstreamInfo, error) { return fb.upstream, nil }
+
+func (fb *fakeBackend) AbortRebase() error { return fb.record("AbortRebase") }
+
+func (fb *fakeBackend) Refs(prefix string) (map[string]string, error) {
+	refs := make(map[string]string)
+	for name, hash := range fb.refs {
+		if strings.HasPrefix(name, prefix) {
+			refs[name] = hash
+		}
+	}
+	return refs, nil
+}
+
+func (fb *fakeBackend) UpdateRef(name, hash string) error {
+	if fb.refs == nil {
+		fb.refs = make(map[string]string)
+	}
+	fb.refs[name] = hash
+	return fb.record("UpdateRef", name, hash)
+}
+
+func (fb *fakeBackend) DeleteRef(name string) error {
+	delete(fb.refs, name)
+	return fb.record("DeleteRef", name)
+}
+
+func (fb *fakeBackend) SnapshotWorktree() (string, error) { return fb.snapshot, nil }
+
+func (fb *fakeBackend) RestoreSnapshot(hash string) error { return fb.record("RestoreSnapshot", hash) }
+
+func (fb *fakeBackend) Stashes() ([]StashInfo, error) { return fb.stashes, fb.errs["Stashes"] }
+
+func (fb *fakeBackend) StashSave(message string, includeUntracked bool) error {
+	return fb.record("StashSave", message, strconv.FormatBool(includeUntracked))
+}
+
+func (fb *fakeBackend) StashShow(ref string) (string, error) { return "", fb.record("StashShow", ref) }
+
+func (fb *fakeBackend) StashApply(ref string, pop bool) error {
+	return fb.record("StashApply", ref, strconv.FormatBool(pop))
+}
+
+func (fb *fakeBackend) StashDrop(ref string) error { return fb.record("StashDrop", ref) }
+
+func (fb *fakeBackend) StashBranch(name, ref string) error { return fb.record("StashBranch", name, ref) }
+
+func (fb *fakeBackend) ConflictStages(path string) (ConflictStages, error) {
+	return ConflictStages{}, fb.record("ConflictStages", path)
+}
+
+func (fb *fakeBackend) MergeFile(ours, base, theirs string) (string, error) {
+	return "", fb.record("MergeFile")
+}
+
+func (fb *fakeBackend) ContinueOperation(op string) error { return fb.record("ContinueOperation", op) }
+
+func (fb *fakeBackend) SkipOperation(op string) error { return fb.record("SkipOperation", op) }
+
+func (fb *fakeBackend) AbortOperation(op string) error { return fb.record("AbortOperation", op) }
+
+func (fb *fakeBackend) RebaseInteractive(onto string, steps []RebaseStep) error {
+	return fb.record("RebaseInteractive", onto)
+}
+
+func (fb *fakeBackend) LogRange(from, to string) ([]CommitInfo, error) {
+	return fb.commits, fb.errs["LogRange"]
+}
+
+func (fb *fakeBackend) LogStats(since time.Time) ([]CommitStats, error) {
+	return nil, fb.errs["LogStats"]
+}
+
+func (fb *fakeBackend) ShowCommit(rev string) (string, error) {
+	return "stat de " + rev, fb.record("ShowCommit", rev)
+}
+
+func (fb *fakeBackend) CommitDiff(rev string) (string, error) {
+	return "", fb.record("CommitDiff", rev)
+}
+
+func (fb *fakeBackend) SearchCommits(query string, byPath bool) ([]CommitInfo, error) {
+	return nil, fb.record("SearchCommits", query, strconv.FormatBool(byPath))
+}
+
+func (fb *fakeBackend) Tags() ([]TagInfo, error) { return nil, fb.errs["Tags"] }
+
+func (fb *fakeBackend) CreateTag(name, target, message string) error {
+	return fb.record("CreateTag", name, target)
+}
+
+func (fb *fakeBackend) DeleteTag(name string) error { return fb.record("DeleteTag", name) }
+
+// Harnais de sessions interactives
+
+func newTestAssistant(t *testing.T, dir string, backend GitBackend) *GitAssistant {
+	t.Helper()
+	// Configuration isolée: pas de fichier global de l'utilisateur
+	t.Setenv("GITCTRL_CONFIG", filepath.Join(t.TempDir(), "config.toml"))
+	// Les assertions portent sur les messages français, quelle que soit la LANG du poste
+	t.Setenv("GITCTRL_LANG", "fr")
+	ga := NewGitAssistant()
+	ga.workingDir = dir
+	ga.reloadConfig()
+	if backend != nil {
+		ga.backend = backend
+		ga.backendForced = true
+	}
+	return ga
+}
+
+// Joue une session du menu: chaque entrée est une ligne tapée par l'utilisateur.
+// La première ligne répond à la sélection du répertoire de travail.
+func runSession(ga *GitAssistant, inputs ...string) string {
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(strings.Join(inputs, "\n")+"\n"), &out)
+	ga.run()
+	return out.String()
+}
+
+// Dépôt jetable avec une configuration Git isolée
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if !gitBinaryAvailable() {
+		t.Skip("binaire git indisponible")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	
+	dir := t.TempDir()
+	gitRun(t, dir, "-c", "init.defaultBranch=master", "init", "-q")
+	writeFile(t, dir, "README.md", "# test\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertContains(t *testing.T, output, want string) {
+	t.Helper()
+	if !strings.Contains(output, want) {
+		t.Errorf("sortie sans %q:\n%s", want, output)
+	}
+}
+
+// Sessions avec le backend factice
+
+func TestQuickCommitPresetWithFakeBackend(t *testing.T) {
+	fake := &fakeBackend{branch: "master", status: []StatusEntry{{Index: '?', Worktree: '?', Path: "new.go"}}}
+	ga := newTestAssistant(t, t.TempDir(), fake)
+	os.Mkdir(filepath.Join(ga.workingDir, ".git"), 0755)
+	
+	out := runSession(ga, "", "1", "3")
+	
+	if !fake.called("Add .") || !fake.called("Commit ✨ Nouvelle fonctionnalité") {
+		t.Fatalf("appels inattendus: %v", fake.calls)
+	}
+	assertContains(t, out, "✅ Commit effectué!")
+}
+
+func TestQuickCommitWithoutChangesWithFakeBackend(t *testing.T) {
+	fake := &fakeBackend{branch: "master"}
+	ga := newTestAssistant(t, t.TempDir(), fake)
+	os.Mkdir(filepath.Join(ga.workingDir, ".git"), 0755)
+	
+	out := runSession(ga, "", "1")
+	
+	assertContains(t, out, "Aucun changement à commiter")
+	if len(fake.calls) != 0 {
+		t.Fatalf("aucun appel attendu: %v", fake.calls)
+	}
+}
+
+func TestMergeFailureWithFakeBackend(t *testing.T) {
+	fake := &fakeBackend{branch: "master", errs: map[string]error{"Merge": errors.New("fusion impossible")}}
+	ga := newTestAssistant(t, t.TempDir(), fake)
+	os.Mkdir(filepath.Join(ga.workingDir, ".git"), 0755)
+	
+	out := runSession(ga, "", "2", "5", "feature/x")
+	
+	if !fake.called("Merge feature/x merge") {
+		t.Fatalf("fusion non demandée: %v", fake.calls)
+	}
+	// Sans fichier non fusionné, l'erreur du backend est affichée telle quelle, pas comme un conflit
+	assertContains(t, out, "❌ Erreur: fusion impossible\n")
+	if strings.Contains(out, "⚔️ Conflits dans") || strings.Contains(out, "fusionnée dans") {
+		t.Fatalf("fusion annoncée en conflit ou réussie:\n%s", out)
+	}
+}
+
+func TestDeleteBranchCancelledWithFakeBackend(t *testing.T) {
+	fake := &fakeBackend{branch: "master"}
+	ga := newTestAssistant(t, t.TempDir(), fake)
+	os.Mkdir(filepath.Join(ga.workingDir, ".git"), 0755)
+	
+	out := runSession(ga, "", "2", "4", "old", "n")
+	
+	assertContains(t, out, "Suppression annulée")
+	if fake.called("DeleteBranch old false") {
+		t.Fatalf("suppression non attendue: %v", fake.calls)
+	}
+}
+
+// Sessions de bout en bout sur de vrais dépôts
+
+func TestQuickCommitCustomMessage(t *testing.T) {
+	for _, kind := range []string{backendExec, backendNative} {
+		t.Run(kind, func(t *testing.T) {
+			dir := newTestRepo(t)
+			ga := newTestAssistant(t, dir, nil)
+			if err := ga.setBackend(kind); err != nil {
+				t.Fatal(err)
+			}
+			writeFile(t, dir, "main.go", "package main\n")
+			
+			runSession(ga, "", "1", "9", "Ajout du main")
+			
+			if got := gitRun(t, dir, "log", "-1", "--pretty=%s"); got != "Ajout du main" {
+				t.Fatalf("dernier commit = %q", got)
+			}
+			if got := gitRun(t, dir, "status", "--porcelain"); got != "" {
+				t.Fatalf("arbre de travail non propre: %q", got)
+			}
+		})
+	}
+}
+
+func TestCreateFeatureBranchSession(t *testing.T) {
+	dir := newTestRepo(t)
+	ga := newTestAssistant(t, dir, nil)
+	
+	out := runSession(ga, "", "2", "1", "Page de Login")
+	
+	assertContains(t, out, "Branche 'feature/page-de-login' créée et activée!")
+	if got := gitRun(t, dir, "branch", "--show-current"); got != "feature/page-de-login" {
+		t.Fatalf("branche courante = %q", got)
+	}
+}
+
+func TestMergeBranchSession(t *testing.T) {
+	dir := newTestRepo(t)
+	gitRun(t, dir, "checkout", "-q", "-b", "feature/x")
+	writeFile(t, dir, "x.txt", "x\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "travail sur x")
+	gitRun(t, dir, "checkout", "-q", "master")
+	ga := newTestAssistant(t, dir, nil)
+	
+	out := runSession(ga, "", "2", "5", "feature/x")
+	
+	assertContains(t, out, "Branche 'feature/x' fusionnée dans 'master'!")
+	if got := gitRun(t, dir, "log", "-1", "--pretty=%s"); got != "travail sur x" {
+		t.Fatalf("dernier commit = %q", got)
+	}
+}
+
+func TestResetToCommitSession(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "a.txt", "a\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "à annuler")
+	ga := newTestAssistant(t, dir, nil)
+	
+	out := runSession(ga, "", "3", "2", "3", "HEAD~1")
+	
+	assertContains(t, out, "✅ Reset effectué!")
+	if got := gitRun(t, dir, "log", "-1", "--pretty=%s"); got != "initial" {
+		t.Fatalf("dernier commit = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); !os.IsNotExist(err) {
+		t.Fatalf("a.txt devrait être supprimé par le reset --hard")
+	}
+}
+
+func TestCLIExitCodes(t *testing.T) {
+	dir := newTestRepo(t)
+	ga := newTestAssistant(t, dir, nil)
+	ga.setIO(strings.NewReader(""), &bytes.Buffer{})
+	
+	if code := ga.runCLI([]string{"-C", dir, "branch", "feature", "cli"}); code != exitOK {
+		t.Fatalf("branch feature: code %d", code)
+	}
+	if code := ga.runCLI([]string{"-C", dir, "commit", "--preset", "inconnu"}); code != exitUsage {
+		t.Fatalf("preset inconnu: code %d", code)
+	}
+	if code := ga.runCLI([]string{"-C", dir, "branch", "merge", "n-existe-pas"}); code != exitError {
+		t.Fatalf("fusion impossible: code %d", code)
+	}
+	if got := gitRun(t, dir, "branch", "--show-current"); got != "feature/cli" {
+		t.Fatalf("branche courante = %q", got)
+	}
+}
+
+func TestJSONReports(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "notes.txt", "x\n")
+	ga := newTestAssistant(t, dir, nil)
+	
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(""), &out)
+	if code := ga.runCLI([]string{"status", "--format", "json"}); code != exitOK {
+		t.Fatalf("status: code %d", code)
+	}
+	var status StatusReport
+	if err := json.Unmarshal(out.Bytes(), &status); err != nil {
+		t.Fatalf("JSON invalide: %v\n%s", err, out.String())
+	}
+	if status.Schema != "gitctrl.status/v1" || status.Branch != "master" || status.Clean {
+		t.Fatalf("rapport inattendu: %+v", status)
+	}
+	if len(status.Changes.Untracked) != 1 || status.Changes.Untracked[0] != "notes.txt" {
+		t.Fatalf("non suivis = %v", status.Changes.Untracked)
+	}
+	
+	out.Reset()
+	if code := ga.runCLI([]string{"log", "-n", "5", "--format", "json"}); code != exitOK {
+		t.Fatalf("log: code %d", code)
+	}
+	var logReport LogReport
+	if err := json.Unmarshal(out.Bytes(), &logReport); err != nil {
+		t.Fatalf("JSON invalide: %v\n%s", err, out.String())
+	}
+	if len(logReport.Commits) != 1 || logReport.Commits[0].Subject != "initial" {
+		t.Fatalf("commits = %+v", logReport.Commits)
+	}
+	
+	if code := ga.runCLI([]string{"insights", "--format", "xml"}); code != exitUsage {
+		t.Fatalf("format inconnu: code %d", code)
+	}
+}
+
+func TestPushSetsUpstreamForWorkBranchesWithFakeBackend(t *testing.T) {
+	fake := &fakeBackend{branch: "feature/api", remotes: []RemoteInfo{{"origin", "file:///tmp/x.git"}}}
+	ga := newTestAssistant(t, t.TempDir(), fake)
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(""), &out)
+	
+	if err := ga.pushCurrentBranch("", false); err != nil {
+		t.Fatal(err)
+	}
+	if !fake.called("Push origin feature/api true") {
+		t.Fatalf("appels: %v", fake.calls)
+	}
+	
+	// Branche déjà suivie: push simple
+	if err := ga.pushCurrentBranch("", false); err != nil {
+		t.Fatal(err)
+	}
+	if !fake.called("Push   false") {
+		t.Fatalf("appels: %v", fake.calls)
+	}
+}
+
+func TestRemoteSyncWithBareRepository(t *testing.T) {
+	dir := newTestRepo(t)
+	bare := filepath.Join(t.TempDir(), "origin.git")
+	gitRun(t, dir, "init", "-q", "--bare", bare)
+	url := "file://" + filepath.ToSlash(bare)
+	ga := newTestAssistant(t, dir, nil)
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(""), &out)
+	
+	if code := ga.runCLI([]string{"remote", "add", "origin", url}); code != exitOK {
+		t.Fatalf("remote add: code %d", code)
+	}
+	if code := ga.runCLI([]string{"branch", "feature", "sync"}); code != exitOK {
+		t.Fatalf("branch feature: code %d", code)
+	}
+	if code := ga.runCLI([]string{"push"}); code != exitOK {
+		t.Fatalf("push: code %d\n%s", code, out.String())
+	}
+	if got := gitRun(t, dir, "rev-parse", "--abbrev-ref", "@{upstream}"); got != "origin/feature/sync" {
+		t.Fatalf("upstream = %q", got)
+	}
+	
+	// Un autre clone pousse un commit: on est en retard d'un commit
+	other := filepath.Join(t.TempDir(), "other")
+	gitRun(t, dir, "clone", "-q", "-b", "feature/sync", url, other)
+	writeFile(t, other, "remote.txt", "distant\n")
+	gitRun(t, other, "add", ".")
+	gitRun(t, other, "commit", "-q", "-m", "commit distant")
+	gitRun(t, other, "push", "-q")
+	
+	writeFile(t, dir, "local.txt", "local\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "commit local")
+	if code := ga.runCLI([]string{"fetch"}); code != exitOK {
+		t.Fatalf("fetch: code %d", code)
+	}
+	upstream, err := ga.backend.Upstream()
+	if err != nil || upstream.Ahead != 1 || upstream.Behind != 1 {
+		t.Fatalf("upstream = %+v, %v", upstream, err)
+	}
+	
+	if code := ga.runCLI([]string{"pull", "--rebase"}); code != exitOK {
+		t.Fatalf("pull: code %d\n%s", code, out.String())
+	}
+	if got := gitRun(t, dir, "log", "--pretty=%s", "-3"); got != "commit local\ncommit distant\ninitial" {
+		t.Fatalf("historique après rebase:\n%s", got)
+	}
+	if code := ga.runCLI([]string{"push"}); code != exitOK {
+		t.Fatalf("push final: code %d", code)
+	}
+	
+	// Le backend natif lit la même configuration
+	native := &nativeBackend{dir: ga.currentDir}
+	remotes, _ := native.Remotes()
+	upstream, _ = native.Upstream()
+	if len(remotes) != 1 || remotes[0].URL != url || upstream.Name != "origin/feature/sync" || upstream.Ahead != 0 {
+		t.Fatalf("natif: remotes=%v upstream=%+v", remotes, upstream)
+	}
+}
+
+func TestSyncRollbackWhenPushFailsWithFakeBackend(t *testing.T) {
+	fake := &fakeBackend{
+		branch:   "main",
+		status:   []StatusEntry{{Index: 'M', Worktree: ' ', Path: "main.go"}},
+		commits:  []CommitInfo{{Hash: "base"}},
+		remotes:  []RemoteInfo{{"origin", "file:///tmp/x.git"}},
+		upstream: UpstreamInfo{Name: "origin/main"},
+		errs:     map[string]error{"Push": errors.New("rejected")},
+	}
+	ga := newTestAssistant(t, t.TempDir(), fake)
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(""), &out)
+	
+	if err := ga.autoSync(); err == nil {
+		t.Fatal("la synchronisation aurait dû échouer")
+	}
+	syncCommit := fake.commits[0].Hash
+	for _, call := range []string{"Add .", "Pull   true", "Reset hard " + syncCommit, "Reset mixed base"} {
+		if !fake.called(call) {
+			t.Errorf("appel %q manquant: %v", call, fake.calls)
+		}
+	}
+	if fake.called("AbortRebase") {
+		t.Error("aucun rebase à abandonner après un échec du push")
+	}
+	for _, want := range []string{"↩️ Commit", "❌ Push - rejected", "Dépôt restauré"} {
+		assertContains(t, out.String(), want)
+	}
+}
+
+func TestSyncSession(t *testing.T) {
+	dir := newTestRepo(t)
+	bare := filepath.Join(t.TempDir(), "origin.git")
+	gitRun(t, dir, "init", "-q", "--bare", bare)
+	gitRun(t, dir, "remote", "add", "origin", "file://"+filepath.ToSlash(bare))
+	writeFile(t, dir, "notes.txt", "nouvelles notes\n")
+	writeFile(t, dir, "README.md", "modifié\n")
+	
+	ga := newTestAssistant(t, dir, nil)
+	out := runSession(ga, dir, "9", "", "0")
+	assertContains(t, out, "⏭️ Pull (rebase) - aucune branche suivie")
+	assertContains(t, out, "🎉 Synchronisation terminée!")
+	
+	if got := gitRun(t, dir, "log", "-1", "--pretty=%s"); got != "🔄 Sync: 1 ajouté(s), 1 modifié(s) (notes.txt, README.md)" {
+		t.Fatalf("message généré = %q", got)
+	}
+	if got := gitRun(t, bare, "rev-parse", "master"); got != gitRun(t, dir, "rev-parse", "HEAD") {
+		t.Fatalf("commit non poussé: %s", got)
+	}
+}
+
+func TestSyncRollbackOnPullConflict(t *testing.T) {
+	dir := newTestRepo(t)
+	bare := filepath.Join(t.TempDir(), "origin.git")
+	gitRun(t, dir, "init", "-q", "--bare", bare)
+	url := "file://" + filepath.ToSlash(bare)
+	gitRun(t, dir, "remote", "add", "origin", url)
+	gitRun(t, dir, "push", "-q", "-u", "origin", "master")
+	
+	// Modification concurrente du même fichier depuis un autre clone
+	other := filepath.Join(t.TempDir(), "other")
+	gitRun(t, dir, "clone", "-q", url, other)
+	writeFile(t, other, "README.md", "version distante\n")
+	gitRun(t, other, "commit", "-q", "-am", "distant")
+	gitRun(t, other, "push", "-q")
+	gitRun(t, dir, "fetch", "-q")
+	
+	head := gitRun(t, dir, "rev-parse", "HEAD")
+	writeFile(t, dir, "README.md", "version locale\n")
+	writeFile(t, dir, "todo.txt", "à faire\n")
+	
+	ga := newTestAssistant(t, dir, nil)
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(""), &out)
+	if err := ga.autoSync(); err == nil {
+		t.Fatalf("conflit attendu:\n%s", out.String())
+	}
+	
+	if got := gitRun(t, dir, "rev-parse", "HEAD"); got != head {
+		t.Fatalf("HEAD = %s, attendu %s", got, head)
+	}
+	if got := gitRun(t, dir, "status", "--porcelain"); got != "M README.md\n?? todo.txt" {
+		t.Fatalf("changements locaux non restaurés:\n%s", got)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "README.md")); string(content) != "version locale\n" {
+		t.Fatalf("README.md = %q", content)
+	}
+}
+
+func TestParsePorcelainV2(t *testing.T) {
+	output := strings.Join([]string{
+		"# branch.oid 1234567890abcdef1234567890abcdef12345678",
+		"# branch.head feature/x",
+		"# branch.upstream origin/feature/x",
+		"# branch.ab +2 -1",
+		"1 .M N... 100644 100644 100644 aaaa aaaa src/main.go",
+		"1 A. N... 000000 100644 100644 0000 bbbb dossier avec espace/é.txt",
+		"2 R. N... 100644 100644 100644 cccc cccc R87 nouveau.go",
+		"ancien.go",
+		"u UU N... 100644 100644 100644 100644 d1 d2 d3 conflit.go",
+		"1 .M SC.U 160000 160000 160000 eeee eeee lib",
+		"? notes.txt",
+		"",
+	}, "\x00")
+	
+	status, err := parsePorcelainV2(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Branch != "feature/x" || status.Upstream != (UpstreamInfo{"origin/feature/x", 2, 1}) {
+		t.Fatalf("en-têtes: %+v", status)
+	}
+	
+	summary := categorizeChanges(status.Entries)
+	if got := strings.Join(summary.Unstaged, ","); got != "src/main.go,lib" {
+		t.Errorf("non indexés = %s", got)
+	}
+	if got := strings.Join(summary.Staged, ","); got != "dossier avec espace/é.txt,nouveau.go" {
+		t.Errorf("indexés = %s", got)
+	}
+	if len(summary.Renamed) != 1 || summary.Renamed[0] != (RenamedFile{"ancien.go", "nouveau.go", 87, false}) {
+		t.Errorf("renommés = %+v", summary.Renamed)
+	}
+	if len(summary.Conflicted) != 1 || summary.Conflicted[0] != (ConflictedFile{"conflit.go", "UU"}) {
+		t.Errorf("conflits = %+v", summary.Conflicted)
+	}
+	if len(summary.Submodules) != 1 || !summary.Submodules[0].CommitChanged || summary.Submodules[0].Modified || !summary.Submodules[0].Untracked {
+		t.Errorf("sous-modules = %+v", summary.Submodules)
+	}
+	
+	if _, err := parsePorcelainV2("1 .M N... tronqué"); err == nil {
+		t.Error("entrée tronquée acceptée")
+	}
+}
+
+func TestStatusBackendsAgree(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "a.txt", "a\n")
+	writeFile(t, dir, "b.txt", "b\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "fichiers")
+	
+	writeFile(t, dir, "README.md", "modifié seulement dans l'arbre\n")
+	gitRun(t, dir, "mv", "a.txt", "renommé.txt")
+	gitRun(t, dir, "rm", "-q", "b.txt")
+	writeFile(t, dir, "nouveau dossier/ça marche.txt", "x\n")
+	
+	want := ChangeSummary{
+		Added: []string{}, Modified: []string{"README.md"}, Deleted: []string{"b.txt"},
+		Untracked:  []string{"nouveau dossier/ça marche.txt"},
+		Renamed:    []RenamedFile{{"a.txt", "renommé.txt", 100, false}},
+		Conflicted: []ConflictedFile{}, Submodules: []SubmoduleChange{},
+		Staged: []string{"b.txt", "renommé.txt"}, Unstaged: []string{"README.md"},
+	}
+	for _, backend := range []GitBackend{&execBackend{dir: func() string { return dir }}, &nativeBackend{dir: func() string { return dir }}} {
+		status, err := backend.Status()
+		if err != nil {
+			t.Fatalf("%s: %v", backend.Name(), err)
+		}
+		got := categorizeChanges(status.Entries)
+		sort.Strings(got.Staged)
+		if fmt.Sprint(got) != fmt.Sprint(want) || status.Branch != "master" {
+			t.Errorf("%s:\n got %+v\nwant %+v", backend.Name(), got, want)
+		}
+	}
+	
+	// Le statut affiche indexés et non indexés séparément
+	ga := newTestAssistant(t, dir, nil)
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(""), &out)
+	if err := ga.smartStatus(); err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, out.String(), "a.txt → renommé.txt (100%)")
+	assertContains(t, out.String(), "✏️ Non indexés (1)")
+}
+
+func TestNativeStatusReportsConflicts(t *testing.T) {
+	dir := newTestRepo(t)
+	gitRun(t, dir, "checkout", "-q", "-b", "autre")
+	writeFile(t, dir, "README.md", "autre\n")
+	gitRun(t, dir, "commit", "-q", "-am", "autre")
+	gitRun(t, dir, "checkout", "-q", "master")
+	writeFile(t, dir, "README.md", "master\n")
+	gitRun(t, dir, "commit", "-q", "-am", "master")
+	exec.Command("git", "-C", dir, "merge", "autre").Run()
+	
+	for _, backend := range []GitBackend{&execBackend{dir: func() string { return dir }}, &nativeBackend{dir: func() string { return dir }}} {
+		status, err := backend.Status()
+		if err != nil {
+			t.Fatalf("%s: %v", backend.Name(), err)
+		}
+		conflicts := categorizeChanges(status.Entries).Conflicted
+		if len(conflicts) != 1 || conflicts[0] != (ConflictedFile{"README.md", "UU"}) {
+			t.Errorf("%s: conflits = %+v", backend.Name(), conflicts)
+		}
+	}
+}
+
+// Mêmes opérations sur deux copies d'un dépôt, une par backend: arbres, historique,
+// branches et statut doivent coïncider
+func TestNativeMergeRefusedBeforeSnapshot(t *testing.T) {
+	dir := newTestRepo(t)
+	gitRun(t, dir, "checkout", "-q", "-b", "feature")
+	writeFile(t, dir, "f.txt", "f\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "ajoute f")
+	gitRun(t, dir, "checkout", "-q", "master")
+	writeFile(t, dir, "m.txt", "m\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "ajoute m")
+	ga := newTestAssistant(t, dir, &nativeBackend{dir: func() string { return dir }})
+	
+	// Branches divergentes ou stratégie sans fast-forward: refus clair, sans instantané ni journal
+	for _, strategy := range []string{mergeDefault, mergeNoFF, mergeSquash, mergeRebase} {
+		err := ga.mergeInto("feature", strategy)
+		if !errors.Is(err, errNativeUnsupported) {
+			t.Fatalf("%s: %v", strategy, err)
+		}
+		assertContains(t, err.Error(), "backend exec requis")
+	}
+	if got := gitRun(t, dir, "for-each-ref", "refs/gitctrl/backup/"); got != "" {
+		t.Fatalf("instantané enregistré: %s", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "gitctrl", "journal.jsonl")); !os.IsNotExist(err) {
+		t.Fatalf("fusion journalisée: %v", err)
+	}
+	
+	// Le fast-forward reste possible
+	gitRun(t, dir, "checkout", "-q", "-b", "suite", "master~1")
+	if err := ga.mergeInto("feature", mergeDefault); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitRun(t, dir, "rev-parse", "HEAD"); got != gitRun(t, dir, "rev-parse", "feature") {
+		t.Fatalf("fast-forward non effectué")
+	}
+}
+
+func TestBackendsParity(t *testing.T) {
+	template := newTestRepo(t)
+	states := make(map[string]string)
+	for _, kind := range []string{backendExec, backendNative} {
+		dir := t.TempDir()
+		if out, err := exec.Command("cp", "-a", template+"/.", dir).CombinedOutput(); err != nil {
+			t.Fatalf("copie du dépôt: %v: %s", err, out)
+		}
+		backend, err := newGitBackend(kind, func() string { return dir })
+		if err != nil {
+			t.Fatal(err)
+		}
+		step := func(name string, err error) {
+			t.Helper()
+			if err != nil {
+				t.Fatalf("%s: %s: %v", kind, name, err)
+			}
+		}
+		
+		writeFile(t, dir, "src/app.go", "package app\n")
+		step("Add", backend.Add())
+		step("Commit", backend.Commit("ajoute app"))
+		step("CreateBranch", backend.CreateBranch("feature/x", ""))
+		writeFile(t, dir, "src/app.go", "package app\n\nfunc Run() {}\n")
+		step("Add", backend.Add("src"))
+		step("Commit", backend.Commit("ajoute Run"))
+		
+		// Un changement suivi bloque le checkout; rétabli, il le laisse passer
+		writeFile(t, dir, "src/app.go", "brouillon\n")
+		if err := backend.Checkout("master"); !errors.Is(err, errLocalChanges) {
+			t.Fatalf("%s: checkout avec changements locaux: %v", kind, err)
+		}
+		writeFile(t, dir, "src/app.go", "package app\n\nfunc Run() {}\n")
+		step("Checkout", backend.Checkout("master"))
+		step("Merge", backend.Merge("feature/x", mergeDefault))
+		step("CreateBranch", backend.CreateBranch("ancien", "HEAD~1"))
+		step("Checkout", backend.Checkout("master"))
+		step("DeleteBranch", backend.DeleteBranch("ancien", false))
+		
+		writeFile(t, dir, "a.txt", "a\n")
+		step("Add", backend.Add("a.txt"))
+		step("Commit", backend.Commit("temporaire"))
+		step("Reset soft", backend.Reset("soft", "HEAD~1"))
+		step("Reset mixed", backend.Reset("mixed", "HEAD"))
+		step("Reset hard", backend.Reset("hard", "HEAD~1"))
+		
+		status, err := backend.Status()
+		step("Status", err)
+		branches, err := backend.Branches()
+		step("Branches", err)
+		commits, err := backend.Log(0)
+		step("Log", err)
+		var names, subjects []string
+		for _, branch := range branches {
+			names = append(names, fmt.Sprintf("%s %v", branch.Name, branch.Current))
+		}
+		for _, commit := range commits {
+			subjects = append(subjects, commit.Subject)
+		}
+		states[kind] = fmt.Sprintf("arbres:\n%s\nbranches: %v\nlog: %v\nstatut: %s %+v",
+			gitRun(t, dir, "log", "--format=%T %s", "--all"), names, subjects, status.Branch, categorizeChanges(status.Entries))
+	}
+	if states[backendExec] != states[backendNative] {
+		t.Fatalf("les backends divergent:\nexec:\n%s\n\nnative:\n%s", states[backendExec], states[backendNative])
+	}
+	assertContains(t, states[backendNative], "log: [ajoute app initial]")
+	assertContains(t, states[backendNative], "Untracked:[a.txt]")
+}
+
+// Présentation, diff et recherche lus par les deux backends sur le même dépôt
+func TestBackendsShowDiffAndSearchAgree(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "src/app.go", "package app\n\nfunc Run() {}\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "Ajoute l'application")
+	writeFile(t, dir, "src/app.go", "package app\n\nfunc Run() error { return nil }\n")
+	writeFile(t, dir, "README.md", "# test\n\nUsage\n")
+	gitRun(t, dir, "commit", "-q", "-am", "corrige Run")
+	
+	results := make(map[string]string)
+	for _, backend := range []GitBackend{&execBackend{dir: func() string { return dir }}, &nativeBackend{dir: func() string { return dir }}} {
+		var out strings.Builder
+		for _, rev := range []string{"HEAD", "HEAD~2"} {
+			show, err := backend.ShowCommit(rev)
+			if err != nil {
+				t.Fatalf("%s: ShowCommit %s: %v", backend.Name(), rev, err)
+			}
+			diff, err := backend.CommitDiff(rev)
+			if err != nil {
+				t.Fatalf("%s: CommitDiff %s: %v", backend.Name(), rev, err)
+			}
+			out.WriteString(show + "\n")
+			// Les lignes "index" abrègent les hash différemment
+			for _, line := range strings.Split(diff, "\n") {
+				if !strings.HasPrefix(line, "index ") {
+					out.WriteString(line + "\n")
+				}
+			}
+		}
+		for _, search := range []struct {
+			query  string
+			byPath bool
+		}{{"APPLICATION", false}, {"app.go", true}, {"absent", false}} {
+			commits, err := backend.SearchCommits(search.query, search.byPath)
+			if err != nil {
+				t.Fatalf("%s: SearchCommits %q: %v", backend.Name(), search.query, err)
+			}
+			fmt.Fprintf(&out, "%s:", search.query)
+			for _, commit := range commits {
+				out.WriteString(" " + commit.Subject)
+			}
+			out.WriteString("\n")
+		}
+		results[backend.Name()] = out.String()
+	}
+	if results[backendExec] != results[backendNative] {
+		t.Fatalf("les backends divergent:\nexec:\n%s\nnative:\n%s", results[backendExec], results[backendNative])
+	}
+	assertContains(t, results[backendNative], " 2 files changed, 3 insertions(+), 1 deletion(-)")
+	assertContains(t, results[backendNative], "+func Run() error { return nil }")
+	assertContains(t, results[backendNative], "app.go: corrige Run Ajoute l'application")
+}
+
+func TestStagingScreenTogglesFilesAndDirectories(t *testing.T) {
+	for _, kind := range []string{backendExec, backendNative} {
+		t.Run(kind, func(t *testing.T) {
+			dir := newTestRepo(t)
+			writeFile(t, dir, "README.md", "modifié\n")
+			writeFile(t, dir, "src/a.go", "package a\n")
+			writeFile(t, dir, "src/b.go", "package b\n")
+			writeFile(t, dir, "secret.env", "TOKEN=x\n")
+			ga := newTestAssistant(t, dir, nil)
+			if err := ga.setBackend(kind); err != nil {
+				t.Fatal(err)
+			}
+			
+			// Statut trié: README.md, puis non suivis secret.env, src/a.go, src/b.go
+			runSession(ga, dir, "11", "1", "src/", "src/b.go", "c", "9", "sélection", "", "0")
+			
+			if got := gitRun(t, dir, "show", "--name-only", "--pretty=%s", "HEAD"); got != "sélection\n\nREADME.md\nsrc/a.go" {
+				t.Fatalf("commit:\n%s", got)
+			}
+			if got := gitRun(t, dir, "status", "--porcelain"); got != "?? secret.env\n?? src/b.go" {
+				t.Fatalf("reste:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestStageSingleHunk(t *testing.T) {
+	dir := newTestRepo(t)
+	var lines []string
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, fmt.Sprintf("ligne %d", i))
+	}
+	writeFile(t, dir, "long.txt", strings.Join(lines, "\n")+"\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "long")
+	
+	lines[1], lines[27] = "début modifié", "fin modifiée"
+	writeFile(t, dir, "long.txt", strings.Join(lines, "\n")+"\n")
+	
+	ga := newTestAssistant(t, dir, nil)
+	// Premier morceau refusé, second indexé
+	runSession(ga, dir, "11", "p 1", "n", "o", "q", "", "0")
+	
+	staged := gitRun(t, dir, "diff", "--cached")
+	if !strings.Contains(staged, "+fin modifiée") || strings.Contains(staged, "début modifié") {
+		t.Fatalf("index:\n%s", staged)
+	}
+	
+	// Désindexer ce même morceau
+	runSession(ga, dir, "11", "u 1", "o", "q", "", "0")
+	if got := gitRun(t, dir, "diff", "--cached"); got != "" {
+		t.Fatalf("index non vide:\n%s", got)
+	}
+}
+
+func TestConventionalCommitValidation(t *testing.T) {
+	valid := conventionalCommit{Type: "feat", Scope: "api", Subject: "ajoute la pagination"}
+	if err := valid.validate(); err != nil {
+		t.Fatalf("message valide refusé: %v", err)
+	}
+	
+	cases := map[string]conventionalCommit{
+		"type inconnu":    {Type: "feature", Subject: "x"},
+		"portée invalide": {Type: "fix", Scope: "Mon API", Subject: "corrige"},
+		"sujet requis":    {Type: "fix"},
+		"point final":     {Type: "fix", Subject: "corrige le bug."},
+		"minuscule":       {Type: "fix", Subject: "Corrige le bug"},
+		"trop longue":     {Type: "fix", Subject: strings.Repeat("a", 70)},
+		"pied de page":    {Type: "fix", Subject: "corrige", Footers: []string{"pas un pied de page"}},
+		"incompatible":    {Type: "feat", Subject: "change l'api", Breaking: true},
+	}
+	for name, cc := range cases {
+		if err := cc.validate(); err == nil {
+			t.Errorf("%s: message invalide accepté: %q", name, cc.String())
+		}
+	}
+	
+	if got := suggestScope([]string{"src/api/a.go", "src/api/b.go", "docs/x.md", "README.md"}); got != "api" {
+		t.Errorf("portée suggérée = %q", got)
+	}
+}
+
+func TestConventionalCommitSession(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "api/routes.go", "package api\n")
+	ga := newTestAssistant(t, dir, nil)
+	
+	// Quick commit → composer: type feat, portée suggérée, incompatible,
+	// sujet refusé puis corrigé, corps, tickets, description incompatible
+	runSession(ga, dir, "1", "10", "1", "", "o", "Ajoute les routes.", "première ligne", "seconde ligne", "",
+		"#12 #34", "les anciennes routes disparaissent", "Reviewed-by: Alice", "", "ajoute les routes", "", "", "0")
+		
+	want := "feat(api)!: ajoute les routes\n\npremière ligne\nseconde ligne\n\n" +
+		"Refs: #12, #34\nBREAKING CHANGE: les anciennes routes disparaissent\nReviewed-by: Alice"
+	if got := gitRun(t, dir, "log", "-1", "--pretty=%B"); got != want {
+		t.Fatalf("message:\n%s\nattendu:\n%s", got, want)
+	}
+	
+	writeFile(t, dir, "api/routes.go", "package api // v2\n")
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(""), &out)
+	if code := ga.runCLI([]string{"commit", "--type", "fix", "-m", "Mauvais sujet."}); code != exitUsage {
+		t.Fatalf("sujet invalide: code %d", code)
+	}
+	if code := ga.runCLI([]string{"commit", "--type", "fix", "--scope", "api", "-m", "corrige les routes"}); code != exitOK {
+		t.Fatalf("commit: code %d", code)
+	}
+	if got := gitRun(t, dir, "log", "-1", "--pretty=%s"); got != "fix(api): corrige les routes" {
+		t.Fatalf("sujet = %q", got)
+	}
+	
+	// Portée invalide redemandée aussitôt; portée trop longue pour la première ligne
+	// redemandée au lieu du sujet, puis retirée
+	writeFile(t, dir, "api/routes.go", "package api // v3\n")
+	long := "une-portee-beaucoup-trop-longue-pour-la-premiere-ligne"
+	session := runSession(ga, dir, "1", "10", "fix", "Bad Scope", long, "n", "corrige la validation des portées", "", "", "", "-", "", "", "0")
+	assertContains(t, session, "❌ portée invalide 'Bad Scope'")
+	assertContains(t, session, "❌ première ligne trop longue")
+	assertContains(t, session, "🎯 Nouvelle portée (Entrée pour garder '"+long+"', - pour aucune): ")
+	if got := gitRun(t, dir, "log", "-1", "--pretty=%s"); got != "fix: corrige la validation des portées" {
+		t.Fatalf("sujet = %q", got)
+	}
+	
+	// Portée conservée: le sujet reste la porte de sortie
+	writeFile(t, dir, "api/routes.go", "package api // v4\n")
+	session = runSession(ga, dir, "1", "10", "fix", long, "n", "corrige encore", "", "", "", "", "", "", "0")
+	assertContains(t, session, "📝 Nouveau sujet (vide pour annuler): ")
+	assertContains(t, session, "commit annulé")
+}
+
+func TestParseTOMLAndEdit(t *testing.T) {
+	content := `# Réglages de l'équipe
+backend = "exec"
+
+[log]
+depth = 30 # plus d'historique
+
+[[presets]]
+name = "wip"
+message = 'Travail en cours # pas un commentaire'
+`
+	doc, err := parseTOML(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Tables["log"]["depth"] != int64(30) || doc.ArrayTables["presets"][0]["message"] != "Travail en cours # pas un commentaire" {
+		t.Fatalf("document: %+v", doc)
+	}
+	for _, invalid := range []string{"[log\n", "clé sans valeur\n", "a = \"non fermé\n", "[a]\n[a]\n", "x = [1, 2]\n"} {
+		if _, err := parseTOML(invalid); err == nil {
+			t.Errorf("accepté: %q", invalid)
+		}
+	}
+	
+	// Modification en place: commentaires conservés, table créée au besoin
+	edited := setTOMLValue(content, "log.depth", "50")
+	edited = setTOMLValue(edited, "ui.colors", "false")
+	edited = removeTOMLValue(edited, "backend")
+	edited = replaceTOMLArray(edited, "presets", presetBlocks([]commitPreset{{"fix", "🐛 Fix"}}))
+	want := `# Réglages de l'équipe
+
+[log]
+depth = 50
+
+[ui]
+colors = false
+
+[[presets]]
+name = "fix"
+message = "🐛 Fix"
+`
+	if edited != want {
+		t.Fatalf("édition:\n%s\nattendu:\n%s", edited, want)
+	}
+}
+
+func TestLayeredConfig(t *testing.T) {
+	dir := newTestRepo(t)
+	global := filepath.Join(t.TempDir(), "config.toml")
+	writeFile(t, filepath.Dir(global), "config.toml", `
+[log]
+depth = 5
+
+[reset]
+mode = "soft"
+
+[[branch_types]]
+name = "hotfix"
+prefix = "hotfix/"
+upstream = true
+`)
+	writeFile(t, dir, ".gitctrl.toml", "[log]\ndepth = 8\ninconnue = 1\n")
+	env := map[string]string{"GITCTRL_RESET_MODE": "hard", "NO_COLOR": "1"}
+	
+	cfg, errs := loadConfig([]string{global, filepath.Join(dir, ".gitctrl.toml")}, func(name string) string { return env[name] })
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "clé inconnue 'log.inconnue'") {
+		t.Fatalf("erreurs: %v", errs)
+	}
+	if cfg.LogDepth != 8 || cfg.ResetMode != "hard" || cfg.Colors || cfg.source("reset.mode") != "GITCTRL_RESET_MODE" {
+		t.Fatalf("config: %+v", cfg)
+	}
+	if len(cfg.BranchTypes) != 1 || cfg.BranchTypes[0].Label != "🌿 Créer branche hotfix" || len(cfg.Presets) != 8 {
+		t.Fatalf("tableaux: %+v %+v", cfg.BranchTypes, cfg.Presets)
+	}
+}
+
+func TestSettingsScreenSession(t *testing.T) {
+	dir := newTestRepo(t)
+	ga := newTestAssistant(t, dir, nil)
+	
+	// log.depth = 3 dans le dépôt, puis un type de branche "hotfix" utilisé dans le menu
+	runSession(ga, dir, "12", "2", "2", "3", "b", "a", "hotfix", "", "", "o", "2", "", "",
+		"2", "3", "urgent", "", "0")
+		
+	data, err := os.ReadFile(filepath.Join(dir, ".gitctrl.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, string(data), "[log]\ndepth = 3\n")
+	assertContains(t, string(data), "[[branch_types]]\nname = \"hotfix\"\nprefix = \"hotfix/\"")
+	if ga.config.LogDepth != 3 {
+		t.Fatalf("log.depth = %d", ga.config.LogDepth)
+	}
+	if got := gitRun(t, dir, "branch", "--show-current"); got != "hotfix/urgent" {
+		t.Fatalf("branche = %q", got)
+	}
+	if !ga.isWorkBranch("hotfix/urgent") {
+		t.Fatal("hotfix/ devrait être poussée avec --set-upstream")
+	}
+}
+
+func TestEnglishCatalogIsComplete(t *testing.T) {
+	source, err := os.ReadFile("GitCtrl.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	literals := regexp.MustCompile(`(?:tr|localizedError)\(("(?:[^"\\]|\\.)*")\)`)
+	
+	keys := []string{cliUsage}
+	for _, match := range literals.FindAllSubmatch(source, -1) {
+		key, err := strconv.Unquote(string(match[1]))
+		if err != nil {
+			t.Fatalf("%s: %v", match[1], err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) < 300 {
+		t.Fatalf("seulement %d messages trouvés", len(keys))
+	}
+	for _, key := range keys {
+		translated, ok := messagesEN[key]
+		if !ok {
+			t.Errorf("traduction anglaise manquante: %q", key)
+			continue
+		}
+		if got, want := verbs.FindAllString(translated, -1), verbs.FindAllString(key, -1); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("%q: verbes %v au lieu de %v", key, got, want)
+		}
+	}
+	
+	// Libellés par défaut des tableaux, traduits à l'affichage
+	same := map[string]bool{"Documentation": true, "Refactoring": true, "Performance": true, "Tests": true, "Maintenance": true, "Configuration": true}
+	var defaults []string
+	for _, ct := range conventionalTypes {
+		defaults = append(defaults, ct.Description)
+	}
+	cfg := defaultConfig()
+	for _, preset := range cfg.Presets {
+		defaults = append(defaults, preset.Message)
+	}
+	for _, bt := range cfg.BranchTypes {
+		defaults = append(defaults, bt.Label)
+	}
+	for _, setting := range configSettings {
+		defaults = append(defaults, setting.Help)
+	}
+	for _, title := range changelogTitles {
+		defaults = append(defaults, title)
+	}
+	for _, label := range backupActionLabels {
+		defaults = append(defaults, label)
+	}
+	for _, label := range defaults {
+		if _, ok := messagesEN[label]; !ok && !same[strings.TrimLeft(label, "📝♻️⚡🔧 \ufe0f")] {
+			t.Errorf("libellé non traduit: %q", label)
+		}
+	}
+}
+
+func TestResolveLocale(t *testing.T) {
+	cases := []struct {
+		requested string
+		env       map[string]string
+		want      string
+	}{
+		{"auto", map[string]string{"LANG": "en_US.UTF-8"}, localeEN},
+		{"", map[string]string{"LANG": "fr_FR.UTF-8"}, localeFR},
+		{"auto", map[string]string{"LC_ALL": "en_GB", "LANG": "fr_FR.UTF-8"}, localeEN},
+		{"auto", map[string]string{"LANG": "C"}, localeFR},
+		{"auto", map[string]string{"LANG": "de_DE.UTF-8"}, localeFR},
+		{"en", map[string]string{"LANG": "fr_FR.UTF-8"}, localeEN},
+	}
+	for _, c := range cases {
+		if got := resolveLocale(c.requested, func(name string) string { return c.env[name] }); got != c.want {
+			t.Errorf("resolveLocale(%q, %v) = %q, attendu %q", c.requested, c.env, got, c.want)
+		}
+	}
+}
+
+func TestEnglishSessionAcceptsLocaleAnswers(t *testing.T) {
+	fake := &fakeBackend{branch: "master", branches: []BranchInfo{{Name: "master", Current: true}, {Name: "old"}}}
+	ga := newTestAssistant(t, t.TempDir(), fake)
+	os.Mkdir(filepath.Join(ga.workingDir, ".git"), 0755)
+	t.Setenv("GITCTRL_LANG", "en")
+	ga.reloadConfig()
+	t.Cleanup(func() { currentLocale = localeFR })
+	
+	// "o" n'est pas un oui en anglais: la suppression est annulée
+	out := runSession(ga, "", "2", "4", "old", "o", "", "2", "4", "old", "y", "", "0")
+	
+	assertContains(t, out, "SMART BRANCH MANAGEMENT")
+	assertContains(t, out, "⚠️ Are you sure you want to delete 'old'? (y/N): ")
+	assertContains(t, out, "❌ Deletion cancelled")
+	assertContains(t, out, "✅ Branch 'old' deleted!")
+	if !fake.called("DeleteBranch old false") {
+		t.Fatalf("suppression attendue: %v", fake.calls)
+	}
+}
+
+func TestTUISession(t *testing.T) {
+	dir := newTestRepo(t)
+	gitRun(t, dir, "branch", "feature/x")
+	writeFile(t, dir, "README.md", "# test\nmodifié\n")
+	writeFile(t, dir, "notes.txt", "brouillon\n")
+	ga := newTestAssistant(t, dir, nil)
+	t.Setenv("COLUMNS", "90")
+	t.Setenv("LINES", "20")
+	var out bytes.Buffer
+	
+	// Indexe README.md, commite, ouvre le commit dans l'historique puis change de branche
+	ga.setIO(strings.NewReader(" cmet à jour le readme\r\t\t\r\x1b[Bq\t\t\rq"), &out)
+	if err := ga.runTUI(); err != nil {
+		t.Fatal(err)
+	}
+	
+	if got := gitRun(t, dir, "log", "-1", "--pretty=%s", "master"); got != "met à jour le readme" {
+		t.Fatalf("dernier commit = %q", got)
+	}
+	if got := gitRun(t, dir, "branch", "--show-current"); got != "feature/x" {
+		t.Fatalf("branche courante = %q", got)
+	}
+	if got := gitRun(t, dir, "status", "--porcelain"); got != "?? notes.txt" {
+		t.Fatalf("statut = %q", got)
+	}
+	assertContains(t, out.String(), "✅ Commit effectué!")
+	assertContains(t, out.String(), "✅ Branche changée!")
+	assertContains(t, out.String(), "+modifié")
+	assertContains(t, out.String(), "│› [ ] ?? notes.txt")
+	
+	if got := fitWidth("✅ éé", 4); got != "✅ …" || displayWidth(got) != 4 {
+		t.Errorf("fitWidth = %q", got)
+	}
+	if got := fitWidth(green("ab"), 4); displayWidth(got) != 4 {
+		t.Errorf("fitWidth coloré = %q", got)
+	}
+}
+
+func TestFuzzyPicker(t *testing.T) {
+	items := []pickerItem{
+		{Key: "master", Label: "  master       a1 initial"},
+		{Key: "feature/login", Label: "  feature/login b2 page de connexion"},
+		{Key: "feature/logout", Label: "  feature/logout c3 déconnexion"},
+		{Key: "bugfix/lien-mort", Label: "  bugfix/lien-mort d4 corrige le lien"},
+	}
+	
+	var keys []string
+	for _, item := range filterPickerItems(items, "flgt") {
+		keys = append(keys, item.Key)
+	}
+	if strings.Join(keys, " ") != "feature/logout" {
+		t.Errorf("filtre flgt = %v", keys)
+	}
+	// À score égal, la ligne la plus courte passe en premier
+	if got := filterPickerItems(items, "log"); len(got) < 2 || got[0].Key != "feature/logout" || got[1].Key != "feature/login" {
+		t.Errorf("correspondances pour log = %v", got)
+	}
+	
+	// Hors terminal: nom exact, préfixe unique, sinon le texte saisi
+	for input, want := range map[string]string{"master": "master", "bug": "bugfix/lien-mort", "feature/": "feature/", "HEAD~2": "HEAD~2"} {
+		if got := resolvePick(items, input); got != want {
+			t.Errorf("resolvePick(%q) = %q", input, got)
+		}
+	}
+	
+	// Frappe "log", flèche bas, Entrée; l'aperçu de l'élément sélectionné est affiché
+	ga := newTestAssistant(t, t.TempDir(), &fakeBackend{})
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader("log\x1b[B\r"), &out)
+	items[1].Preview = func() string { return "b2 page de connexion\nsrc/auth.go | 4 +-" }
+	if got := ga.pickInteractive("🔄 Nom de la branche: ", items); got != "feature/login" {
+		t.Fatalf("choix = %q", got)
+	}
+	assertContains(t, out.String(), "src/auth.go | 4 +-")
+	
+	ga.setIO(strings.NewReader("zzz\x1b"), &out)
+	if got := ga.pickInteractive("🔄 Nom de la branche: ", items); got != "" {
+		t.Fatalf("Échap devrait annuler, choix = %q", got)
+	}
+}
+
+func TestPickerPreviewsUseBackend(t *testing.T) {
+	fake := &fakeBackend{
+		branch:   "master",
+		branches: []BranchInfo{{Name: "feature/login", Hash: "b2c3d4e"}, {Name: "master", Current: true, Hash: "a1b2c3d"}},
+		commits: []CommitInfo{
+			{Hash: "b2c3d4e5f6", ShortHash: "b2c3d4e", Subject: "page de connexion", Refs: []string{"feature/login"}},
+			{Hash: "a1b2c3d4e5", ShortHash: "a1b2c3d", Subject: "initial"},
+		},
+	}
+	ga := newTestAssistant(t, t.TempDir(), fake)
+	
+	branches := ga.branchItems(false)
+	if len(branches) != 1 || branches[0].Key != "feature/login" {
+		t.Fatalf("branches = %+v", branches)
+	}
+	assertContains(t, branches[0].Preview(), "* b2c3d4e (feature/login) page de connexion\n* a1b2c3d initial")
+	if !fake.called("LogFrom feature/login 20") {
+		t.Errorf("appels: %v", fake.calls)
+	}
+	
+	commits := ga.commitItems()
+	if len(commits) != 2 || commits[1].Key != "a1b2c3d" {
+		t.Fatalf("commits = %+v", commits)
+	}
+	if got := commits[1].Preview(); got != "stat de a1b2c3d4e5" || !fake.called("ShowCommit a1b2c3d4e5") {
+		t.Errorf("aperçu = %q, appels: %v", got, fake.calls)
+	}
+}
+
+func TestUndoRestoresHardResetAndDeletedBranch(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "a.txt", "a\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "à annuler")
+	writeFile(t, dir, "README.md", "# test\nen cours\n")
+	ga := newTestAssistant(t, dir, nil)
+	
+	// Reset --hard qui perd un commit et une modification, puis annulation
+	out := runSession(ga, "", "3", "2", "3", "HEAD~1", "", "14", "", "")
+	
+	assertContains(t, out, "💾 État sauvegardé")
+	assertContains(t, out, "Reset · master @ ")
+	assertContains(t, out, "· changements locaux")
+	assertContains(t, out, "✅ État restauré")
+	if got := gitRun(t, dir, "log", "-1", "--pretty=%s"); got != "à annuler" {
+		t.Fatalf("dernier commit = %q", got)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "README.md")); string(data) != "# test\nen cours\n" {
+		t.Fatalf("modification locale perdue: %q", data)
+	}
+	// Seul reste l'instantané pris avant l'annulation, pour la rétablir
+	if got := gitRun(t, dir, "for-each-ref", "--format=%(refname)", "refs/gitctrl/backup/"); !strings.Contains(got, "-undo/head") || strings.Contains(got, "-reset/") {
+		t.Fatalf("instantanés: %s", got)
+	}
+	
+	gitRun(t, dir, "branch", "jetable")
+	ga.setIO(strings.NewReader(""), &bytes.Buffer{})
+	if code := ga.runCLI([]string{"-C", dir, "branch", "delete", "--force", "jetable"}); code != exitOK {
+		t.Fatalf("branch delete: code %d", code)
+	}
+	if code := ga.runCLI([]string{"-C", dir, "undo"}); code != exitOK {
+		t.Fatalf("undo: code %d", code)
+	}
+	gitRun(t, dir, "rev-parse", "--verify", "refs/heads/jetable")
+	if code := ga.runCLI([]string{"-C", dir, "undo", "9"}); code != exitError {
+		t.Fatalf("undo 9: code %d", code)
+	}
+}
+
+func TestResetAsksWhenLocalChangesCannotBeSaved(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "a.txt", "a\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "à garder")
+	writeFile(t, dir, "README.md", "# test\nen cours\n")
+	// Le backend natif ne sait pas mettre les changements locaux de côté
+	ga := newTestAssistant(t, dir, &nativeBackend{dir: func() string { return dir }})
+	
+	out := runSession(ga, "", "3", "2", "3", "HEAD~1", "", "")
+	assertContains(t, out, "⚠️ Changements locaux non sauvegardés")
+	if strings.Contains(out, "💾") || strings.Contains(out, "✅ Reset effectué") {
+		t.Fatalf("reset sans confirmation:\n%s", out)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "README.md")); string(data) != "# test\nen cours\n" {
+		t.Fatalf("modification locale perdue: %q", data)
+	}
+	
+	out = runSession(ga, "", "3", "2", "3", "HEAD~1", "o", "")
+	assertContains(t, out, "💾 Branches sauvegardées: « Annuler la dernière action » ne restaurera pas les changements locaux")
+	assertContains(t, out, "✅ Reset effectué")
+	if got := gitRun(t, dir, "log", "-1", "--pretty=%s"); got == "à garder" {
+		t.Fatalf("reset non effectué")
+	}
+}
+
+// Sortie de "gitctrl undo list", depuis un assistant distinct (runCLI passe en mode ligne de commande)
+func undoList(t *testing.T, dir string) string {
+	t.Helper()
+	ga := newTestAssistant(t, dir, nil)
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(""), &out)
+	if code := ga.runCLI([]string{"undo", "list"}); code != exitOK {
+		t.Fatalf("undo list: code %d\n%s", code, out.String())
+	}
+	return out.String()
+}
+
+func TestJournalRecordsActionsAcrossSessions(t *testing.T) {
+	dir := newTestRepo(t)
+	bare := filepath.Join(t.TempDir(), "origin.git")
+	gitRun(t, dir, "init", "-q", "--bare", bare)
+	gitRun(t, dir, "remote", "add", "origin", "file://"+filepath.ToSlash(bare))
+	ga := newTestAssistant(t, dir, nil)
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(""), &out)
+	
+	if code := ga.runCLI([]string{"branch", "feature", "journal"}); code != exitOK {
+		t.Fatalf("branch feature: code %d", code)
+	}
+	if code := ga.runCLI([]string{"branch", "merge", "n-existe-pas"}); code != exitError {
+		t.Fatalf("branch merge: code %d", code)
+	}
+	writeFile(t, dir, "a.txt", "a\n")
+	if code := ga.runCLI([]string{"sync", "-m", "ajoute a"}); code != exitOK {
+		t.Fatalf("sync: code %d\n%s", code, out.String())
+	}
+	
+	// Le push lancé par sync n'a pas d'entrée à lui
+	entries, err := ga.loadJournal()
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("journal = %+v, %v", entries, err)
+	}
+	created, merge, sync := entries[0], entries[1], entries[2]
+	if created.Action != "branch.create" || created.Origin != originCLI || created.Result != journalOK ||
+		created.Before.Branch != "master" || created.After.Branch != "feature/journal" {
+		t.Fatalf("création de branche: %+v", created)
+	}
+	if _, ok := created.Before.Refs["refs/heads/feature/journal"]; ok || created.After.Refs["refs/heads/feature/journal"] == "" {
+		t.Fatalf("références: %v → %v", created.Before.Refs, created.After.Refs)
+	}
+	if merge.Action != "merge" || merge.Result != journalError || merge.Error == "" {
+		t.Fatalf("fusion ratée: %+v", merge)
+	}
+	if sync.Action != "sync" || sync.Summary != "Synchronisation automatique" || sync.After.Refs["refs/remotes/origin/feature/journal"] == "" {
+		t.Fatalf("sync: %+v", sync)
+	}
+	
+	// Nouvelle session: filtre par type et export JSON
+	ga = newTestAssistant(t, dir, nil)
+	session := runSession(ga, "", "10", "branch", "", "journal.json", "")
+	assertContains(t, session, "branch.create (1), merge (1), sync (1)")
+	assertContains(t, session, "master @ ")
+	assertContains(t, session, "→ feature/journal @ ")
+	assertContains(t, session, "[cli]")
+	if strings.Contains(session, "Synchronisation automatique") {
+		t.Fatalf("le filtre garde sync:\n%s", session)
+	}
+	var report JournalReport
+	data, _ := os.ReadFile(filepath.Join(dir, "journal.json"))
+	if err := json.Unmarshal(data, &report); err != nil || report.Schema != "gitctrl.journal/v1" || len(report.Entries) != 1 {
+		t.Fatalf("export: %v\n%s", err, data)
+	}
+	
+	ga.setIO(strings.NewReader(""), &out)
+	out.Reset()
+	if code := ga.runCLI([]string{"journal", "--type", "merge", "--since", "1h", "--format", "json"}); code != exitOK {
+		t.Fatalf("journal: code %d", code)
+	}
+	report = JournalReport{}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil || len(report.Entries) != 1 || report.Entries[0].Result != journalError {
+		t.Fatalf("journal --type merge: %v\n%s", err, out.String())
+	}
+	if code := ga.runCLI([]string{"journal", "--since", "hier"}); code != exitUsage {
+		t.Fatalf("date invalide: code %d", code)
+	}
+}
+
+func TestSwitchBranchStashesBlockingChanges(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "f.txt", "1\n2\n3\n4\n5\n6\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "f")
+	gitRun(t, dir, "checkout", "-q", "-b", "autre")
+	writeFile(t, dir, "f.txt", "un\n2\n3\n4\n5\n6\n")
+	gitRun(t, dir, "commit", "-q", "-am", "autre")
+	gitRun(t, dir, "checkout", "-q", "master")
+	writeFile(t, dir, "f.txt", "1\n2\n3\n4\n5\nsix\n")
+	ga := newTestAssistant(t, dir, nil)
+	
+	// Le checkout refuse d'écraser f.txt: stash, changement de branche, pop
+	out := runSession(ga, "", "2", "3", "autre", "o", "")
+	assertContains(t, out, "⚠️ Des changements locaux seraient écrasés")
+	assertContains(t, out, "📦 Changements mis de côté dans stash@{0}")
+	assertContains(t, out, "📥 Changements réappliqués")
+	if got := gitRun(t, dir, "branch", "--show-current"); got != "autre" {
+		t.Fatalf("branche = %q", got)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "f.txt")); string(data) != "un\n2\n3\n4\n5\nsix\n" {
+		t.Fatalf("f.txt = %q", data)
+	}
+	if got := gitRun(t, dir, "stash", "list"); got != "" {
+		t.Fatalf("stash non vidé: %s", got)
+	}
+	
+	// En ligne de commande, seulement avec --autostash
+	var stdout bytes.Buffer
+	ga.setIO(strings.NewReader(""), &stdout)
+	if code := ga.runCLI([]string{"branch", "switch", "master"}); code != exitError {
+		t.Fatalf("branch switch: code %d", code)
+	}
+	writeFile(t, dir, "nouveau.txt", "n\n")
+	if code := ga.runCLI([]string{"stash", "save", "-u", "-m", "en cours"}); code != exitOK {
+		t.Fatalf("stash save: code %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "nouveau.txt")); !os.IsNotExist(err) {
+		t.Fatalf("fichier non suivi resté en place: %v", err)
+	}
+	stdout.Reset()
+	if code := ga.runCLI([]string{"stash", "list"}); code != exitOK {
+		t.Fatalf("stash list: code %d", code)
+	}
+	assertContains(t, stdout.String(), "autre: en cours")
+	
+	out = runSession(ga, "", "15", "4", "0", "")
+	assertContains(t, out, "✅ stash@{0} réappliquée")
+	if _, err := os.Stat(filepath.Join(dir, "nouveau.txt")); err != nil {
+		t.Fatalf("fichier non suivi non restauré: %v", err)
+	}
+	
+	ga.setIO(strings.NewReader(""), &stdout)
+	if code := ga.runCLI([]string{"stash", "save"}); code != exitOK {
+		t.Fatalf("stash save: code %d", code)
+	}
+	if code := ga.runCLI([]string{"stash", "branch", "depuis-stash"}); code != exitOK {
+		t.Fatalf("stash branch: code %d", code)
+	}
+	if got := gitRun(t, dir, "branch", "--show-current"); got != "depuis-stash" {
+		t.Fatalf("branche = %q", got)
+	}
+	if code := ga.runCLI([]string{"stash", "pop"}); code != exitError {
+		t.Fatalf("stash pop sans entrée: code %d", code)
+	}
+}
+
+func TestSwitchBranchKeepsStashWhenPopConflicts(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "f.txt", "1\n2\n3\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "f")
+	gitRun(t, dir, "checkout", "-q", "-b", "autre")
+	writeFile(t, dir, "f.txt", "1\ndeux\n3\n")
+	gitRun(t, dir, "commit", "-q", "-am", "autre")
+	gitRun(t, dir, "checkout", "-q", "master")
+	writeFile(t, dir, "f.txt", "1\nzwei\n3\n")
+	ga := newTestAssistant(t, dir, nil)
+	
+	// Le changement de branche réussit, seul le pop bute sur la même ligne
+	var stdout bytes.Buffer
+	ga.setIO(strings.NewReader(""), &stdout)
+	ga.autoStash = autoStashAlways
+	err := ga.switchBranch("autre")
+	if !errors.Is(err, errStashKept) || strings.Contains(err.Error(), "erreur lors du changement de branche") {
+		t.Fatalf("switchBranch = %v", err)
+	}
+	assertContains(t, err.Error(), "ils restent dans stash@{0}")
+	assertContains(t, stdout.String(), "✅ Branche changée!")
+	if got := gitRun(t, dir, "branch", "--show-current"); got != "autre" {
+		t.Fatalf("branche = %q", got)
+	}
+	if got := gitRun(t, dir, "stash", "list"); !strings.Contains(got, "gitctrl: avant le passage à autre") {
+		t.Fatalf("stash non conservé: %q", got)
+	}
+}
+
+func TestParseDiff3(t *testing.T) {
+	merged := "un\n<<<<<<< ours\nnous\n||||||| base\nbase\n=======\neux\n>>>>>>> theirs\ntrois\n"
+	hunks := parseDiff3(merged)
+	if len(hunks) != 3 || hunks[0].Conflict || !hunks[1].Conflict || hunks[2].Conflict {
+		t.Fatalf("hunks = %+v", hunks)
+	}
+	conflict := hunks[1]
+	if strings.Join(conflict.Ours, "") != "nous\n" || strings.Join(conflict.Base, "") != "base\n" || strings.Join(conflict.Theirs, "") != "eux\n" {
+		t.Fatalf("conflit = %+v", conflict)
+	}
+	if got := joinBothSides(hunks); got != "un\nnous\neux\ntrois\n" {
+		t.Fatalf("les deux = %q", got)
+	}
+	
+	// Un côté a supprimé le fichier: « les deux » garde celui qui reste
+	stages := ConflictStages{Ours: "nous\n", HasOurs: true}
+	if content, keep := resolvedContent(stages, nil, sideBoth); !keep || content != "nous\n" {
+		t.Fatalf("both = %q, %v", content, keep)
+	}
+	if _, keep := resolvedContent(stages, nil, sideTheirs); keep {
+		t.Fatal("theirs devrait supprimer le fichier")
+	}
+}
+
+// Deux fichiers en conflit après une fusion
+func newConflictRepo(t *testing.T) string {
+	t.Helper()
+	dir := newTestRepo(t)
+	writeFile(t, dir, "a.txt", "un\ndeux\ntrois\n")
+	writeFile(t, dir, "b.txt", "un\ndeux\ntrois\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "base")
+	gitRun(t, dir, "checkout", "-q", "-b", "autre")
+	writeFile(t, dir, "a.txt", "un\nDEUX autre\ntrois\n")
+	writeFile(t, dir, "b.txt", "un\ndeux autre\ntrois\n")
+	gitRun(t, dir, "commit", "-q", "-am", "autre")
+	gitRun(t, dir, "checkout", "-q", "master")
+	writeFile(t, dir, "a.txt", "un\nDEUX master\ntrois\n")
+	writeFile(t, dir, "b.txt", "un\ndeux master\ntrois\n")
+	gitRun(t, dir, "commit", "-q", "-am", "master")
+	return dir
+}
+
+func TestResolveMergeConflictsSession(t *testing.T) {
+	dir := newConflictRepo(t)
+	ga := newTestAssistant(t, dir, nil)
+	
+	// Fusion malgré les conflits prévus, résolution immédiate: a.txt par la leur,
+	// b.txt par les deux, puis poursuite
+	out := runSession(ga, "", "2", "5", "autre", "", "", "o", "1", "t", "1", "b", "c")
+	assertContains(t, out, "⚔️ Conflits prévus dans 2 fichier(s)")
+	assertContains(t, out, "⚔️ Conflits dans 2 fichier(s)")
+	assertContains(t, out, "--- Conflit 1/1 ---")
+	assertContains(t, out, "DEUX master")
+	assertContains(t, out, "✅ a.txt résolu (theirs)")
+	assertContains(t, out, "✅ Opération terminée (merge)")
+	assertContains(t, out, "✅ Branche 'autre' fusionnée dans 'master'!")
+	
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(data) != "un\nDEUX autre\ntrois\n" {
+		t.Fatalf("a.txt = %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "b.txt")); string(data) != "un\ndeux master\ndeux autre\ntrois\n" {
+		t.Fatalf("b.txt = %q", data)
+	}
+	if parents := gitRun(t, dir, "rev-list", "--parents", "-n", "1", "HEAD"); len(strings.Fields(parents)) != 3 {
+		t.Fatalf("pas de commit de fusion: %s", parents)
+	}
+	// La résolution fait partie de la fusion: une seule entrée au journal
+	entries, err := ga.loadJournal()
+	if err != nil || len(entries) != 1 || entries[0].Action != "merge" || entries[0].Result != journalOK {
+		t.Fatalf("journal = %+v, %v", entries, err)
+	}
+}
+
+func TestConflictsCLI(t *testing.T) {
+	dir := newConflictRepo(t)
+	ga := newTestAssistant(t, dir, nil)
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(""), &out)
+	
+	if code := ga.runCLI([]string{"branch", "merge", "autre"}); code != exitError {
+		t.Fatalf("branch merge: code %d", code)
+	}
+	assertContains(t, out.String(), "gitctrl conflicts")
+	
+	out.Reset()
+	if code := ga.runCLI([]string{"conflicts"}); code != exitOK {
+		t.Fatalf("conflicts: code %d", code)
+	}
+	assertContains(t, out.String(), "Opération en cours: merge")
+	assertContains(t, out.String(), "b.txt")
+	
+	out.Reset()
+	if code := ga.runCLI([]string{"conflicts", "show", "a.txt"}); code != exitOK {
+		t.Fatalf("conflicts show: code %d", code)
+	}
+	assertContains(t, out.String(), "DEUX autre")
+	
+	if code := ga.runCLI([]string{"conflicts", "ours", "a.txt"}); code != exitOK {
+		t.Fatalf("conflicts ours: code %d", code)
+	}
+	if code := ga.runCLI([]string{"conflicts", "continue"}); code != exitError {
+		t.Fatalf("continue avec b.txt en conflit: code %d", code)
+	}
+	if code := ga.runCLI([]string{"conflicts", "abort"}); code != exitOK {
+		t.Fatalf("conflicts abort: code %d", code)
+	}
+	if ga.pendingOperation() != "" || gitRun(t, dir, "status", "--porcelain") != "" {
+		t.Fatal("fusion non abandonnée")
+	}
+	if code := ga.runCLI([]string{"conflicts", "abort"}); code != exitError {
+		t.Fatalf("abort sans opération: code %d", code)
+	}
+}
+
+func TestMergeStrategiesAndPreview(t *testing.T) {
+	dir := newTestRepo(t)
+	gitRun(t, dir, "checkout", "-q", "-b", "feature/x")
+	writeFile(t, dir, "x.txt", "x\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "ajoute x")
+	writeFile(t, dir, "y.txt", "y\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "ajoute y")
+	gitRun(t, dir, "checkout", "-q", "master")
+	writeFile(t, dir, "m.txt", "m\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "master avance")
+	start := gitRun(t, dir, "rev-parse", "HEAD")
+	ga := newTestAssistant(t, dir, nil)
+	var out bytes.Buffer
+	ga.setIO(strings.NewReader(""), &out)
+	
+	// L'aperçu ne touche ni à HEAD, ni à l'index, ni à l'arbre de travail
+	if code := ga.runCLI([]string{"branch", "merge", "--preview", "--format", "json", "feature/x"}); code != exitOK {
+		t.Fatalf("preview: code %d", code)
+	}
+	var report MergePreviewReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("%v\n%s", err, out.String())
+	}
+	if report.Schema != "gitctrl.merge-preview/v1" || report.Into != "master" || len(report.Commits) != 2 ||
+		len(report.Files) != 2 || report.Files[0] != (FileChange{"A", "x.txt"}) || len(report.Conflicts) != 0 || report.FastForward {
+		t.Fatalf("aperçu: %+v", report)
+	}
+	if gitRun(t, dir, "rev-parse", "HEAD") != start || gitRun(t, dir, "status", "--porcelain") != "" {
+		t.Fatal("l'aperçu a modifié le dépôt")
+	}
+	
+	if code := ga.runCLI([]string{"branch", "merge", "--strategy", "ff-only", "feature/x"}); code != exitError {
+		t.Fatalf("ff-only sur des branches divergentes: code %d", code)
+	}
+	if code := ga.runCLI([]string{"branch", "merge", "--strategy", "octopus", "feature/x"}); code != exitUsage {
+		t.Fatalf("stratégie inconnue: code %d", code)
+	}
+	
+	// Squash: un seul commit, sans lien avec la branche
+	if code := ga.runCLI([]string{"branch", "merge", "--strategy", "squash", "feature/x"}); code != exitOK {
+		t.Fatalf("squash: code %d\n%s", code, out.String())
+	}
+	if parents := strings.Fields(gitRun(t, dir, "rev-list", "--parents", "-n", "1", "HEAD")); len(parents) != 2 {
+		t.Fatalf("squash: parents %v", parents)
+	}
+	assertContains(t, gitRun(t, dir, "log", "-1", "--pretty=%B"), "* ajoute y")
+	
+	// Rebase puis fast-forward: historique linéaire, la branche a été rejouée
+	gitRun(t, dir, "reset", "-q", "--hard", start)
+	if code := ga.runCLI([]string{"branch", "merge", "--strategy", "rebase", "feature/x"}); code != exitOK {
+		t.Fatalf("rebase: code %d\n%s", code, out.String())
+	}
+	if gitRun(t, dir, "branch", "--show-current") != "master" || gitRun(t, dir, "rev-parse", "HEAD") != gitRun(t, dir, "rev-parse", "feature/x") {
+		t.Fatal("master n'a pas avancé jusqu'à feature/x rebasée")
+	}
+	if merges := gitRun(t, dir, "rev-list", "--merges", "HEAD"); merges != "" {
+		t.Fatalf("commit de fusion inattendu: %s", merges)
+	}
+	
+	// Dans le menu: no-ff sur une branche qu'un fast-forward suffirait à intégrer
+	gitRun(t, dir, "checkout", "-q", "-b", "feature/z")
+	writeFile(t, dir, "z.txt", "z\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "ajoute z")
+	gitRun(t, dir, "checkout", "-q", "master")
+	session := runSession(ga, "", "2", "5", "feature/z", "3")
+	assertContains(t, session, "✅ Fast-forward possible, aucun conflit")
+	assertContains(t, session, "A z.txt")
+	if parents := strings.Fields(gitRun(t, dir, "rev-list", "--parents", "-n", "1", "HEAD")); len(parents) != 3 {
+		t.Fatalf("no-ff: parents %v", parents)
+	}
+}
+
+func TestInteractiveRebaseSession(t *testing.T) {
+	dir := newTestRepo(t)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		writeFile(t, dir, name+".txt", name+"\n")
+		gitRun(t, dir, "add", ".")
+		gitRun(t, dir, "commit", "-q", "-m", "ajoute "+name)
+	}
+	ga := newTestAssistant(t, dir, nil)
+	
+	// b reformulé avec c fusionné dedans, d supprimé, a déplacé à la fin
+	out := runSession(ga, "", "17", "HEAD~4", "2 r", "b et c", "3 f", "4 d", "9 p", "m 1 4", "")
+	assertContains(t, out, " 1. pick   ")
+	assertContains(t, out, "ajoute b → ")
+	assertContains(t, out, "❌ Choix invalide")
+	assertContains(t, out, "✅ 'master' rebasée sur HEAD~4!")
+	
+	if got := gitRun(t, dir, "log", "--format=%s", "-3"); got != "ajoute a\nb et c\ninitial" {
+		t.Fatalf("historique:\n%s", got)
+	}
+	if files := gitRun(t, dir, "ls-files"); files != "README.md\na.txt\nb.txt\nc.txt" {
+		t.Fatalf("fichiers: %s", files)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "gitctrl", "rebase")); !os.IsNotExist(err) {
+		t.Fatalf("état du rebase non nettoyé: %v", err)
+	}
+	assertContains(t, undoList(t, dir), "     Rebase · master @ ")
+	
+	// Un squash en tête de liste est refusé avant de lancer quoi que ce soit
+	out = runSession(ga, "", "17", "HEAD~2", "1 s", "", "q")
+	assertContains(t, out, "squash")
+	assertContains(t, out, "aucun commit avant auquel l'ajouter")
+	assertContains(t, out, "❌ Rebase annulé")
+}
+
+func TestInteractiveRebaseStopsForConflictAndEdit(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "f.txt", "1\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "crée f")
+	writeFile(t, dir, "f.txt", "2\n")
+	gitRun(t, dir, "commit", "-q", "-am", "modifie f")
+	ga := newTestAssistant(t, dir, nil)
+	
+	// "modifie f" passe en premier et entre en conflit: il est passé, puis l'arrêt
+	// sur "crée f" (edit) est poursuivi sans changement
+	out := runSession(ga, "", "17", "HEAD~2", "m 2 1", "2 e", "", "o", "s", "c")
+	assertContains(t, out, "⚔️ Conflits dans 1 fichier(s)")
+	assertContains(t, out, "s: passer ce commit")
+	assertContains(t, out, "⏸️ Arrêt pour modifier le commit")
+	assertContains(t, out, "✅ 'master' rebasée sur HEAD~2!")
+	
+	if got := gitRun(t, dir, "log", "--format=%s", "-2"); got != "crée f\ninitial" {
+		t.Fatalf("historique:\n%s", got)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "f.txt")); string(data) != "1\n" {
+		t.Fatalf("f.txt = %q", data)
+	}
+}
+
+func TestCherryPickFromHistorySession(t *testing.T) {
+	dir := newTestRepo(t)
+	gitRun(t, dir, "checkout", "-q", "-b", "release")
+	gitRun(t, dir, "checkout", "-q", "-b", "main-dev", "master")
+	for _, name := range []string{"a", "b", "c"} {
+		writeFile(t, dir, name+".txt", name+"\n")
+		gitRun(t, dir, "add", ".")
+		gitRun(t, dir, "commit", "-q", "-m", "ajoute "+name)
+	}
+	gitRun(t, dir, "checkout", "-q", "release")
+	ga := newTestAssistant(t, dir, nil)
+	
+	// Numéros dans l'ordre de la liste (du plus récent au plus ancien): c puis a
+	out := runSession(ga, "", "3", "5", "main-dev", "1 3", "o")
+	assertContains(t, out, " 2. * ")
+	assertContains(t, out, "✅ 2 commit(s) appliqué(s) sur 'release'!")
+	if got := gitRun(t, dir, "log", "--format=%s", "-2"); got != "ajoute c\najoute a" {
+		t.Fatalf("historique:\n%s", got)
+	}
+	assertContains(t, gitRun(t, dir, "log", "-1", "--format=%B"), "(cherry picked from commit ")
+	assertContains(t, undoList(t, dir), " 1. il y a ")
+
+	assertContains(t, undoList(t, dir), "     Cherry-pick · release @ ")
+	
+	// Conflit: le commit modifie b.txt, absent de release; abandon propre
+	writeFile(t, dir, "b.txt", "release\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "b sur release")
+	before := gitRun(t, dir, "rev-parse", "HEAD")
+	out = runSession(ga, "", "3", "5", "main-dev", "2", "n", "o", "a")
+	assertContains(t, out, "⚔️ Conflits dans 1 fichier(s)")
+	assertContains(t, out, "↩️ Opération abandonnée (cherry-pick)")
+	if gitRun(t, dir, "rev-parse", "HEAD") != before || gitRun(t, dir, "status", "--porcelain") != "" || ga.pendingOperation() != "" {
+		t.Fatal("cherry-pick non abandonné proprement")
+	}
+	
+	// Liste réduite à log.depth: le plus ancien, masqué, reste choisissable par hash;
+	// numéros et hash abrégé mêlés sont appliqués du plus ancien au plus récent
+	gitRun(t, dir, "reset", "-q", "--hard", "master")
+	ga.config.LogDepth = 2
+	out = runSession(ga, "", "3", "5", "main-dev", "1 "+gitRun(t, dir, "rev-parse", "--short", "main-dev~2")+" 2", "n")
+	assertContains(t, out, "… 1 commit(s) plus ancien(s) non affiché(s): indiquez leur hash")
+	if got := gitRun(t, dir, "log", "--format=%s", "-3"); got != "ajoute c\najoute b\najoute a" {
+		t.Fatalf("ordre du cherry-pick:\n%s", got)
+	}
+}
+
+func TestRevertMergeCommitChoosesMainline(t *testing.T) {
+	dir := newTestRepo(t)
+	gitRun(t, dir, "checkout", "-q", "-b", "feature")
+	writeFile(t, dir, "f.txt", "f\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "ajoute f")
+	gitRun(t, dir, "checkout", "-q", "master")
+	writeFile(t, dir, "m.txt", "m\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "ajoute m")
+	gitRun(t, dir, "merge", "-q", "--no-edit", "feature")
+	ga := newTestAssistant(t, dir, nil)
+	
+	// Parent 1 (master): la fusion est annulée, f.txt disparaît
+	out := runSession(ga, "", "3", "6", "HEAD", "", "")
+	assertContains(t, out, "est un commit de fusion")
+	assertContains(t, out, "ajoute m")
+	assertContains(t, out, "✅ Commit ")
+	if _, err := os.Stat(filepath.Join(dir, "f.txt")); !os.IsNotExist(err) {
+		t.Fatalf("f.txt devrait être supprimé: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "m.txt")); err != nil {
+		t.Fatal(err)
+	}
+	
+	var stdout bytes.Buffer
+	ga.setIO(strings.NewReader(""), &stdout)
+	if code := ga.runCLI([]string{"revert", "HEAD~1"}); code != exitError {
+		t.Fatalf("revert d'une fusion sans -m: code %d", code)
+	}
+	if code := ga.runCLI([]string{"revert", "-m", "2", "HEAD~1"}); code != exitOK {
+		t.Fatalf("revert -m 2: code %d\n%s", code, stdout.String())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "m.txt")); !os.IsNotExist(err) {
+		t.Fatalf("m.txt devrait être supprimé: %v", err)
+	}
+	assertContains(t, undoList(t, dir), "     Revert · master @ ")
+	entries, _ := ga.loadJournal()
+	if last := entries[len(entries)-1]; last.Action != "revert" || strings.Join(last.Args, " ") != "HEAD~1 -m 2" {
+		t.Fatalf("journal: %+v", last)
+	}
+}
+
+func TestSemverOrderAndReleaseBump(t *testing.T) {
+	tags := []TagInfo{{Name: "v1.2.0-rc.1"}, {Name: "latest"}, {Name: "v1.10.0"}, {Name: "v1.2.0"},
+		{Name: "v1.2.0-beta"}, {Name: "2.0.0"}, {Name: "v1.2.0-rc.10"}}
+	sortTags(tags)
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	if got := strings.Join(names, " "); got != "2.0.0 v1.10.0 v1.2.0 v1.2.0-rc.10 v1.2.0-rc.1 v1.2.0-beta latest" {
+		t.Fatalf("ordre = %s", got)
+	}
+	
+	ga := newTestAssistant(t, t.TempDir(), &fakeBackend{})
+	for _, tc := range []struct {
+		want    string
+		commits []CommitInfo
+	}{
+		{"", nil},
+		{bumpPatch, []CommitInfo{{Subject: "fix: corrige"}, {Subject: "mise à jour"}}},
+		{bumpMinor, []CommitInfo{{Subject: "fix: corrige"}, {Subject: "feat(api): ajoute"}}},
+		{bumpMinor, []CommitInfo{{Subject: "✨ Nouvelle fonctionnalité"}}},
+		{bumpMajor, []CommitInfo{{Subject: "feat!: retire v1"}}},
+		{bumpMajor, []CommitInfo{{Subject: "fix: renomme", Body: "BREAKING CHANGE: l'option -q disparaît"}}},
+	} {
+		if got := ga.releaseBump(tc.commits); got != tc.want {
+			t.Errorf("%+v: %q, attendu %q", tc.commits, got, tc.want)
+		}
+	}
+	if got := (semVersion{Major: 1, Minor: 4, Patch: 2}).bump(bumpMinor).String(); got != "1.5.0" {
+		t.Errorf("bump minor = %s", got)
+	}
+}
+
+func TestReleaseSessionAndTagCLI(t *testing.T) {
+	dir := newTestRepo(t)
+	gitRun(t, dir, "tag", "v1.0.0")
+	// Une version plus haute hors de l'historique de master n'est pas la dernière release
+	gitRun(t, dir, "checkout", "-q", "-b", "experimental")
+	writeFile(t, dir, "log.txt", "essai\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "feat!: essai")
+	gitRun(t, dir, "tag", "v2.0.0")
+	gitRun(t, dir, "checkout", "-q", "master")
+	for _, subject := range []string{"fix: corrige la pagination", "feat(api): ajoute le tri", "🐛 Correction de bug"} {
+		writeFile(t, dir, "log.txt", subject+"\n")
+		gitRun(t, dir, "add", ".")
+		gitRun(t, dir, "commit", "-q", "-m", subject)
+	}
+	ga := newTestAssistant(t, dir, nil)
+	
+	out := runSession(ga, "", "18", "1", "", "")
+	assertContains(t, out, "📌 Dernière release: "+yellow("v1.0.0")+" (3 commit(s) depuis)")
+	assertContains(t, out, "💡 Version suggérée: ")
+	assertContains(t, out, "v1.1.0")
+	assertContains(t, out, "✅ Tag ")
+	if got := gitRun(t, dir, "cat-file", "-t", "v1.1.0"); got != "tag" {
+		t.Fatalf("v1.1.0 devrait être annoté: %s", got)
+	}
+	notes := gitRun(t, dir, "tag", "-l", "--format=%(contents)", "v1.1.0")
+	for _, want := range []string{"v1.1.0 - ", "✨ Nouveautés\n  - api: ajoute le tri", "🐛 Corrections\n  - 🐛 Correction de bug", "  - corrige la pagination"} {
+		assertContains(t, notes, want)
+	}
+	gitRun(t, dir, "tag", "-d", "v2.0.0")
+	
+	// Décorations dans l'historique interactif
+	out = runSession(ga, "", "3", "")
+	assertContains(t, out, "🏷️ v1.1.0")
+	assertContains(t, out, "🏷️ v1.0.0")
+	
+	var stdout bytes.Buffer
+	ga.setIO(strings.NewReader(""), &stdout)
+	if code := ga.runCLI([]string{"release", "--dry-run"}); code != exitOK {
+		t.Fatalf("release --dry-run: code %d", code)
+	}
+	assertContains(t, stdout.String(), "rien à publier")
+	if code := ga.runCLI([]string{"release", "--bump", "huge"}); code != exitUsage {
+		t.Fatalf("--bump inconnu: code %d", code)
+	}
+	if code := ga.runCLI([]string{"tag", "create", "-m", "essai", "v0.9.0", "HEAD~3"}); code != exitOK {
+		t.Fatalf("tag create: code %d", code)
+	}
+	if code := ga.runCLI([]string{"tag", "create", "../x"}); code != exitError {
+		t.Fatalf("nom invalide: code %d", code)
+	}
+	if code := ga.runCLI([]string{"tag", "delete", "v1.0.0"}); code != exitOK {
+		t.Fatalf("tag delete: code %d", code)
+	}
+	
+	stdout.Reset()
+	if code := ga.runCLI([]string{"tag", "list", "--format", "json"}); code != exitOK {
+		t.Fatalf("tag list: code %d", code)
+	}
+	var report TagsReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("JSON invalide: %v\n%s", err, stdout.String())
+	}
+	if report.Schema != "gitctrl.tags/v1" || len(report.Tags) != 2 || report.Tags[0].Name != "v1.1.0" || !report.Tags[0].Annotated || report.Tags[1].Message != "essai" {
+		t.Fatalf("rapport inattendu: %+v", report)
+	}
+	
+	// Le backend natif lit, crée et supprime les mêmes tags
+	native := &nativeBackend{dir: func() string { return dir }}
+	if err := native.CreateTag("v1.1.1", "HEAD", "Version v1.1.1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitRun(t, dir, "tag", "-l", "--format=%(objecttype) %(*objectname) %(contents:subject)", "v1.1.1"); got != "tag "+gitRun(t, dir, "rev-parse", "HEAD")+" Version v1.1.1" {
+		t.Fatalf("tag natif: %s", got)
+	}
+	tags, err := native.Tags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortTags(tags)
+	if len(tags) != 3 || tags[0].Name != "v1.1.1" || tags[2].Name != "v0.9.0" || tags[2].Commit != gitRun(t, dir, "rev-parse", "HEAD~3") {
+		t.Fatalf("tags natifs: %+v", tags)
+	}
+	if err := native.DeleteTag("v1.1.1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitRun(t, dir, "tag", "-l"); got != "v0.9.0\nv1.1.0" {
+		t.Fatalf("tags restants: %q", got)
+	}
+}
+
+func TestChangelogGroupsAndPrepend(t *testing.T) {
+	dir := newTestRepo(t)
+	gitRun(t, dir, "tag", "v1.0.0")
+	// Tag plus haut mais hors de l'historique de HEAD: ignoré comme par git describe
+	gitRun(t, dir, "tag", "v9.0.0", gitRun(t, dir, "commit-tree", "-m", "ailleurs", "HEAD^{tree}"))
+	for _, message := range []string{"feat(api): ajoute le tri", "fix: corrige la pagination", "🐛 Correction de bug", "docs: guide",
+		"refactor!: renomme l'API\n\nBREAKING CHANGE: Client devient Session", "🔒 Sécurité: vérifie les jetons", "bricolage"} {
+		writeFile(t, dir, "log.txt", message+"\n")
+		gitRun(t, dir, "add", ".")
+		gitRun(t, dir, "commit", "-q", "-m", message)
+	}
+	ga := newTestAssistant(t, dir, nil)
+	ga.config.Presets = append(ga.config.Presets, commitPreset{"secu", "🔒 Sécurité"})
+	
+	// Menu: aperçu, puis création de CHANGELOG.md
+	out := runSession(ga, "", "18", "4", "", "", "o")
+	assertContains(t, out, "📍 Depuis (Entrée: v1.0.0)")
+	assertContains(t, out, "## Non publié - ")
+	assertContains(t, out, "✅ Section « Non publié » ajoutée en tête de CHANGELOG.md")
+	created, _ := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+	assertContains(t, string(created), "# Changelog\n\n## Non publié - ")
+	
+	var stdout bytes.Buffer
+	ga.setIO(strings.NewReader(""), &stdout)
+	if code := ga.runCLI([]string{"changelog", "--format", "json"}); code != exitOK {
+		t.Fatalf("changelog json: code %d", code)
+	}
+	var report ChangelogReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("JSON invalide: %v\n%s", err, stdout.String())
+	}
+	var groups []string
+	for _, section := range report.Sections {
+		groups = append(groups, fmt.Sprintf("%s:%d", section.Group, len(section.Entries)))
+	}
+	if report.Schema != "gitctrl.changelog/v1" || report.From != "v1.0.0" || strings.Join(groups, " ") != "breaking:1 feat:1 fix:2 docs:1 preset:secu:1 other:1" {
+		t.Fatalf("rapport inattendu: %s %s %v", report.Schema, report.From, groups)
+	}
+	if entry := report.Sections[1].Entries[0]; entry.Scope != "api" || entry.Summary != "ajoute le tri" || entry.Type != "feat" {
+		t.Fatalf("entrée feat: %+v", entry)
+	}
+	
+	stdout.Reset()
+	if code := ga.runCLI([]string{"changelog", "--format", "text", "--from", "HEAD~2"}); code != exitOK {
+		t.Fatalf("changelog text: code %d", code)
+	}
+	assertContains(t, stdout.String(), "🔒 Sécurité\n  - 🔒 Sécurité: vérifie les jetons (")
+	if strings.Contains(stdout.String(), "ajoute le tri") {
+		t.Fatalf("--from ignoré:\n%s", stdout.String())
+	}
+	
+	writeFile(t, dir, "CHANGELOG.md", "# Changelog\n\nNotes de version.\n\n## v1.0.0 - 2026-01-01\n\n- début\n")
+	if code := ga.runCLI([]string{"changelog", "--title", "v1.1.0", "--prepend", "CHANGELOG.md"}); code != exitOK {
+		t.Fatalf("changelog --prepend: code %d", code)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+	if !regexp.MustCompile(`(?s)^# Changelog\n\nNotes de version\.\n\n## v1\.1\.0 - \d{4}-\d{2}-\d{2}\n\n### ⚠️ Changements incompatibles\n\n- renomme l'API \(\w+\)\n\n### ✨ Nouveautés\n\n- \*\*api:\*\* ajoute le tri .*\n\n## v1\.0\.0 - 2026-01-01\n\n- début\n$`).Match(content) {
+		t.Fatalf("CHANGELOG.md:\n%s", content)
+	}
+	if code := ga.runCLI([]string{"changelog", "--title", "v1.1.0", "--prepend", "CHANGELOG.md"}); code != exitError {
+		t.Fatalf("section en double: code %d", code)
+	}
+	if code := ga.runCLI([]string{"changelog", "--format", "xml"}); code != exitUsage {
+		t.Fatalf("format inconnu: code %d", code)
+	}
+}
+
+func TestContributorStatsMergeMailmapIdentities(t *testing.T) {
+	dir := newTestRepo(t)
+	commitAs := func(author, name, content string) {
+		writeFile(t, dir, name, content)
+		gitRun(t, dir, "add", ".")
+		gitRun(t, dir, "commit", "-q", "--author", author, "-m", "ajoute "+name)
+	}
+	commitAs("Alice <alice@old.example>", "src/a.go", "a\nb\nc\n")
+	commitAs("Alice Martin <ALICE@example.com>", "src/b.go", "d\ne\n")
+	commitAs("Bob <bob@example.com>", "src/c.go", "f\n")
+	commitAs("Bob <bob@example.com>", "docs/guide.md", "g\nh\n")
+	commitAs("Alice Martin <alice@example.com>", "docs/faq.md", "i\nj\n")
+	commitAs("Test <test@example.com>", ".mailmap", "Alice Martin <alice@example.com> <alice@old.example>\n")
+	
+	ga := newTestAssistant(t, dir, nil)
+	var stdout bytes.Buffer
+	ga.setIO(strings.NewReader(""), &stdout)
+	if code := ga.runCLI([]string{"insights", "--format", "json"}); code != exitOK {
+		t.Fatalf("insights: code %d", code)
+	}
+	var report InsightsReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("JSON invalide: %v\n%s", err, stdout.String())
+	}
+	if report.Schema != "gitctrl.insights/v2" || len(report.Authors) != 3 {
+		t.Fatalf("schéma %s, auteurs = %+v", report.Schema, report.Authors)
+	}
+	alice := report.Authors[0]
+	if alice.Name != "Alice Martin" || alice.Commits != 3 || alice.Added != 7 || alice.Deleted != 0 || alice.First.After(alice.Last) {
+		t.Fatalf("Alice = %+v", alice)
+	}
+	
+	// src: Alice a écrit 5 lignes sur 6; docs: 2 lignes chacun, il faut les deux auteurs
+	factors := make(map[string]DirectoryOwnership)
+	for _, dir := range report.BusFactor {
+		factors[dir.Path] = dir
+	}
+	if src := factors["src"]; src.BusFactor != 1 || src.Lines != 6 || src.Owners[0].Name != "Alice Martin" {
+		t.Fatalf("src = %+v", src)
+	}
+	if docs := factors["docs"]; docs.BusFactor != 2 || docs.Lines != 4 {
+		t.Fatalf("docs = %+v", docs)
+	}
+	
+	out := runSession(ga, "", "4")
+	assertContains(t, out, "Contributeurs (3)")
+	assertContains(t, out, "Alice Martin <alice@example.com>: ")
+	assertContains(t, out, "  • src/: ")
+	assertContains(t, out, "Alice Martin 83%")
+	
+	// Le backend natif applique le même .mailmap
+	native := newTestAssistant(t, dir, &nativeBackend{dir: func() string { return dir }}).buildInsightsReport(time.Time{})
+	for i, author := range native.Authors {
+		if want := report.Authors[i]; author.Name != want.Name || author.Email != want.Email || author.Commits != want.Commits || author.Added != want.Added {
+			t.Errorf("natif: %+v au lieu de %+v", author, want)
+		}
+	}
+	if len(native.Authors) != len(report.Authors) || fmt.Sprint(native.BusFactor) != fmt.Sprint(report.BusFactor) {
+		t.Fatalf("natif: auteurs %+v, bus factor %+v", native.Authors, native.BusFactor)
+	}
+	
+	// Backend sans statistiques: sections vides
+	fake := &fakeBackend{errs: map[string]error{"LogStats": errNativeUnsupported}}
+	if report := newTestAssistant(t, t.TempDir(), fake).buildInsightsReport(time.Time{}); report.Authors == nil || len(report.BusFactor) != 0 {
+		t.Fatalf("rapport = %+v", report)
+	}
+}
+
+func TestChangelogScreenFromSubdirectory(t *testing.T) {
+	dir := newTestRepo(t)
+	for _, release := range []string{"v1.0.0", "v1.1.0", "v1.2.0"} {
+		writeFile(t, dir, "log.txt", release+"\n")
+		gitRun(t, dir, "add", ".")
+		gitRun(t, dir, "commit", "-q", "-m", "feat: prépare "+release)
+		gitRun(t, dir, "tag", release)
+	}
+	os.Mkdir(filepath.Join(dir, "docs"), 0755)
+	ga := newTestAssistant(t, filepath.Join(dir, "docs"), nil)
+	
+	// Le début proposé dépend de la fin choisie, et CHANGELOG.md reste à la racine
+	var stdout bytes.Buffer
+	ga.setIO(strings.NewReader("v1.1.0\n\no\n"), &stdout)
+	if err := ga.changelogScreen(); err != nil {
+		t.Fatal(err)
+	}
+	out := stdout.String()
+	assertContains(t, out, "📍 Depuis (Entrée: v1.0.0)")
+	assertContains(t, out, "- prépare v1.1.0")
+	if strings.Contains(out, "prépare v1.2.0") {
+		t.Fatalf("intervalle incorrect:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "CHANGELOG.md")); err != nil {
+		t.Fatalf("CHANGELOG.md absent de la racine: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "docs", "CHANGELOG.md")); !os.IsNotExist(err) {
+		t.Fatalf("CHANGELOG.md créé dans le sous-dossier: %v", err)
+	}
+}
+
+func TestParseMailmap(t *testing.T) {
+	m := parseMailmap(`# identités
+Alice Martin <alice@old.example>
+<bob@example.com> <bob@laptop.local>
+Carol Durand <carol@example.com> <CAROL@perso.example>
+Dave <dave@example.com> dave <shared@example.com>
+`)
+	for _, tc := range []struct{ name, email, wantName, wantEmail string }{
+		{"alice", "Alice@Old.example", "Alice Martin", "Alice@Old.example"},
+		{"Bob", "bob@laptop.local", "Bob", "bob@example.com"},
+		{"carol", "carol@perso.example", "Carol Durand", "carol@example.com"},
+		{"Dave", "shared@example.com", "Dave", "dave@example.com"},
+		{"Eve", "shared@example.com", "Eve", "shared@example.com"},
+		{"Zoé", "zoe@example.com", "Zoé", "zoe@example.com"},
+	} {
+		if name, email := m.resolve(tc.name, tc.email); name != tc.wantName || email != tc.wantEmail {
+			t.Errorf("%s <%s>: %s <%s>", tc.name, tc.email, name, email)
+		}
+	}
+}
+
+func TestChurnHotspotsAndCoupling(t *testing.T) {
+	dir := newTestRepo(t)
+	commit := func(message, date string, files ...string) {
+		for _, name := range files {
+			previous, _ := os.ReadFile(filepath.Join(dir, name))
+			writeFile(t, dir, name, string(previous)+message+"\n")
+		}
+		gitRun(t, dir, "add", ".")
+		cmd := exec.Command("git", "commit", "-q", "-m", message)
+		cmd.Dir = dir
+		if date != "" {
+			cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		}
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("commit: %v\n%s", err, output)
+		}
+	}
+	commit("ancien", "2020-01-01T12:00:00Z", "a.go", "b.go")
+	for _, message := range []string{"un", "deux", "trois"} {
+		commit(message, "", "a.go", "a_test.go")
+	}
+	writeFile(t, dir, "big.txt", strings.Repeat("ligne\n", 50))
+	commit("gros fichier", "")
+	
+	ga := newTestAssistant(t, dir, nil)
+	insights := func(args ...string) ChurnReport {
+		t.Helper()
+		var stdout bytes.Buffer
+		ga.setIO(strings.NewReader(""), &stdout)
+		if code := ga.runCLI(append([]string{"insights", "--format", "json"}, args...)); code != exitOK {
+			t.Fatalf("insights %v: code %d", args, code)
+		}
+		var report InsightsReport
+		if err := json.Unmarshal(stdout.Bytes(), &report); err != nil || report.Churn == nil {
+			t.Fatalf("JSON invalide: %v\n%s", err, stdout.String())
+		}
+		return *report.Churn
+	}
+	
+	// Fenêtre par défaut (insights.churn_days = 90): le commit de 2020 est exclu
+	churn := insights()
+	if churn.Since == nil || churn.Commits != 4 {
+		t.Fatalf("fenêtre = %v, %d commits", churn.Since, churn.Commits)
+	}
+	if top := churn.MostChanged[0]; top.Path != "a.go" || top.Commits != 3 {
+		t.Fatalf("plus modifié = %+v", top)
+	}
+	if top := churn.MostChurned[0]; top.Path != "big.txt" || top.Added != 50 {
+		t.Fatalf("plus de lignes = %+v", top)
+	}
+	if len(churn.Coupled) != 1 || churn.Coupled[0] != (CoChange{"a.go", "a_test.go", 3, 100}) {
+		t.Fatalf("couplage = %+v", churn.Coupled)
+	}
+	
+	// Tout l'historique: a.go et b.go ne sont modifiés ensemble qu'une fois
+	churn = insights("--since", "all")
+	if churn.Since != nil || churn.MostChanged[0].Commits != 4 || len(churn.Coupled) != 1 || churn.Coupled[0].Degree != 86 {
+		t.Fatalf("tout l'historique = %+v", churn)
+	}
+	if code := ga.runCLI([]string{"insights", "--since", "hier"}); code != exitUsage {
+		t.Fatalf("date invalide: code %d", code)
+	}
+	
+	out := runSession(ga, "", "4")
+	assertContains(t, out, "Points chauds (depuis le ")
+	assertContains(t, out, " 1. a.go      ")
+	assertContains(t, out, " 1. a.go ↔ a_test.go: 3 commits communs (100%)")
+}
+
+func TestLanguageBreakdownHonorsGitattributes(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "main.go", "package main\n\n// commentaire\n/* bloc\n   suite */\nvar x = 1\nvar y = 2\n\nfunc main() {}\n")
+	writeFile(t, dir, "vendor/keep/keep.go", "package keep\n")
+	writeFile(t, dir, "vendor/lib/lib.go", "package lib\n")
+	writeFile(t, dir, "api.pb.go", "// Code generated by protoc-gen-go. DO NOT EDIT.\npackage main\n")
+	writeFile(t, dir, "gen/.gitattributes", "*.go linguist-generated\n")
+	writeFile(t, dir, "gen/x.go", "package gen\n")
+	writeFile(t, dir, "Makefile", "# build\nall:\n\tgo build\n")
+	writeFile(t, dir, "Dockerfile", "FROM golang\n")
+	writeFile(t, dir, "scripts/deploy", "#!/usr/bin/env python3\nprint('ok')\n")
+	writeFile(t, dir, "fixtures/a.json", "{}\n")
+	writeFile(t, dir, "fixtures/b.json", "{}\n")
+	writeFile(t, dir, "logo.png", "\x89PNG\x00\x00")
+	writeFile(t, dir, ".gitattributes", "# fixtures de test\nfixtures/** linguist-generated\nvendor/keep/** -linguist-vendored\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "sources")
+	
+	want := map[string]LanguageStats{
+		"Go":         {"Go", 2, 5, 3, 2},
+		"Makefile":   {"Makefile", 1, 2, 1, 0},
+		"Dockerfile": {"Dockerfile", 1, 1, 0, 0},
+		"Python":     {"Python", 1, 1, 1, 0},
+		"Markdown":   {"Markdown", 1, 1, 0, 0},
+		"Other":      {"Other", 2, 4, 0, 0},
+	}
+	for _, backend := range []GitBackend{&execBackend{dir: func() string { return dir }}, &nativeBackend{dir: func() string { return dir }}} {
+		ga := newTestAssistant(t, dir, backend)
+		languages, excluded, err := ga.languageBreakdown()
+		if err != nil {
+			t.Fatalf("%s: %v", backend.Name(), err)
+		}
+		// fixtures/a.json, fixtures/b.json, vendor/lib/lib.go, api.pb.go, gen/x.go
+		if excluded != 5 || len(languages) != len(want) || languages[0].Language != "Go" {
+			t.Fatalf("%s: %d exclus, langages %+v", backend.Name(), excluded, languages)
+		}
+		for _, stats := range languages {
+			if stats != want[stats.Language] {
+				t.Errorf("%s: %+v au lieu de %+v", backend.Name(), stats, want[stats.Language])
+			}
+		}
+	}
+	
+	out := runSession(newTestAssistant(t, dir, nil), "", "4")
+	assertContains(t, out, "  Go                  2")
+	assertContains(t, out, "  Autre               2")
+	assertContains(t, out, "5 fichier(s) vendored ou générés ignorés")
+}